@@ -0,0 +1,170 @@
+package main
+
+import (
+	"archive/zip"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/database"
+	"github.com/nnamm/go-health-tracker/internal/importer"
+	"github.com/nnamm/go-health-tracker/internal/models"
+	"github.com/nnamm/go-health-tracker/internal/validators"
+)
+
+// runImportAppleHealth is an alias of import -format apple-health, kept for existing scripts.
+//
+//	server import-apple-health [-email user@example.com] [-on-conflict skip] export.zip
+func runImportAppleHealth(args []string) error {
+	return runImport(append([]string{"-format", importer.FormatAppleHealth}, args...))
+}
+
+// runImport imports the daily step counts of export files in one of the importer formats into the database,
+// all in a single transaction. Days that already have a record are skipped, overwritten or fail the whole import
+// depending on -on-conflict; skipped days are reported as duplicates or conflicts.
+// Days are counted in the -tz time zone for formats with UTC timestamps.
+// An Apple Health export can be given as export.xml or the export.zip it comes in.
+//
+//	server import -format fitbit [-email user@example.com] [-tz Asia/Tokyo] [-on-conflict skip] steps-2024-01-01.json ...
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	format := fs.String("format", "", "export format: "+strings.Join([]string{importer.FormatAppleHealth, importer.FormatGoogleFitCSV, importer.FormatGoogleFitJSON, importer.FormatFitbit}, ", "))
+	email := fs.String("email", "", "email of the user to import for (default: the default user)")
+	tz := fs.String("tz", "UTC", "time zone days are counted in")
+	conflictFlag := fs.String("on-conflict", string(models.ConflictSkip), "what to do with days that already have a record: skip, overwrite or fail")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("usage: import -format format [-email address] [-tz zone] [-on-conflict policy] file...")
+	}
+
+	loc, err := time.LoadLocation(*tz)
+	if err != nil {
+		return fmt.Errorf("invalid time zone: %w", err)
+	}
+	onConflict, err := models.ParseConflictPolicy(*conflictFlag)
+	if err != nil {
+		return err
+	}
+	imp, err := importer.New(*format, loc)
+	if err != nil {
+		return err
+	}
+
+	var records []models.HealthRecord
+	for _, name := range fs.Args() {
+		fileRecords, err := importFile(imp, name)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		records = append(records, fileRecords...)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	db, err := database.NewDatabase()
+	if err != nil {
+		return fmt.Errorf("initialize database: %w", err)
+	}
+	defer db.Close()
+
+	userID, err := importUserID(ctx, db, *email)
+	if err != nil {
+		return err
+	}
+
+	report, err := importer.ImportRecords(ctx, db, userID, records, validators.NewHealthRecordValidator(), onConflict)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("imported records",
+		slog.Int("days", report.Days), slog.Int("created", report.Created), slog.Int("overwritten", report.Overwritten),
+		slog.Int("duplicates", len(report.Duplicates)), slog.Int("conflicts", len(report.Conflicts)), slog.Int("rejected", len(report.Rejected)))
+	for _, conflict := range report.Conflicts {
+		slog.Warn("conflicting day", slog.String("date", conflict.Date), slog.Int("stored", conflict.Stored), slog.Int("imported", conflict.Imported))
+	}
+	for _, rejected := range report.Rejected {
+		slog.Warn("rejected day", slog.String("date", rejected.Date), slog.String("reason", rejected.Reason))
+	}
+	return nil
+}
+
+// importFile reads the daily records of an export file
+func importFile(imp importer.Importer, name string) ([]models.HealthRecord, error) {
+	var f io.ReadCloser
+	var err error
+	if _, ok := imp.(importer.AppleHealth); ok {
+		f, err = openAppleHealthExport(name)
+	} else {
+		f, err = os.Open(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return imp.Import(f)
+}
+
+// importUserID returns the ID of the user with the given email, or of the default user when email is empty
+func importUserID(ctx context.Context, db database.UserStore, email string) (int64, error) {
+	if email == "" {
+		return defaultUserID(db)
+	}
+
+	user, err := db.ReadUserByEmail(ctx, email)
+	if err != nil {
+		return 0, fmt.Errorf("read user: %w", err)
+	}
+	if user == nil {
+		return 0, fmt.Errorf("no user with email %s", email)
+	}
+	return user.ID, nil
+}
+
+// openAppleHealthExport opens export.xml, directly or inside the export.zip archive
+func openAppleHealthExport(name string) (io.ReadCloser, error) {
+	if !strings.EqualFold(path.Ext(name), ".zip") {
+		return os.Open(name)
+	}
+
+	archive, err := zip.OpenReader(name)
+	if err != nil {
+		return nil, fmt.Errorf("open export archive: %w", err)
+	}
+	for _, f := range archive.File {
+		if path.Base(f.Name) == "export.xml" {
+			rc, err := f.Open()
+			if err != nil {
+				archive.Close()
+				return nil, fmt.Errorf("open %s: %w", f.Name, err)
+			}
+			return &zipEntry{ReadCloser: rc, archive: archive}, nil
+		}
+	}
+
+	archive.Close()
+	return nil, fmt.Errorf("no export.xml in %s", name)
+}
+
+// zipEntry is an open archive entry that closes its archive too
+type zipEntry struct {
+	io.ReadCloser
+	archive *zip.ReadCloser
+}
+
+// Close closes the entry and its archive
+func (e *zipEntry) Close() error {
+	e.ReadCloser.Close()
+	return e.archive.Close()
+}
@@ -3,44 +3,195 @@
 package main
 
 import (
-	"log"
+	"context"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
+	"github.com/nnamm/go-health-tracker/internal/auth"
+	"github.com/nnamm/go-health-tracker/internal/config"
 	"github.com/nnamm/go-health-tracker/internal/database"
 	"github.com/nnamm/go-health-tracker/internal/handlers"
+	"github.com/nnamm/go-health-tracker/internal/logging"
+	"github.com/nnamm/go-health-tracker/internal/models"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 // API path constants
 const (
 	healthRecordsPath = "/health/records"
+	healthBatchPath   = "/health/records:batch"
+	healthStatsPath   = "/health/records/stats"
+	healthExportPath  = "/health/records/export"
+	healthImportPath  = "/health/records/import"
+	appleHealthPath   = "/health/records/import/apple-health"
+	sleepSessionsPath = "/health/sleep"
+	weightPath        = "/health/weight"
+	dailyWeightPath   = "/health/weight/daily"
+	heightPath        = "/health/height"
+
+	goalsPath        = "/health/goals"
+	goalProgressPath = "/health/goals/progress"
+	goalStreaksPath  = "/health/goals/streaks"
+
+	bloodPressurePath      = "/health/blood-pressure"
+	dailyBloodPressurePath = "/health/blood-pressure/daily"
+	heartRatePath          = "/health/heart-rate"
+	dailyHeartRatePath     = "/health/heart-rate/daily"
+
+	metricsPath           = "/health/metrics"
+	metricValuesPath      = "/health/metrics/values"
+	dailyMetricValuesPath = "/health/metrics/daily"
+
+	apiKeysPath = "/account/api-keys"
+
+	authPath         = "/auth/"
+	authRegisterPath = "/auth/register"
+	authLoginPath    = "/auth/login"
+	authRefreshPath  = "/auth/refresh"
+
+	livezPath   = "/livez"
+	readyzPath  = "/readyz"
+	debugDBPath = "/debug/db"
+
+	// metricsPromPath serves Prometheus metrics; metricsPath is the custom health metrics API
+	metricsPromPath = "/metrics"
 )
 
+// apiHandlers groups the endpoint handlers served by routeHandler
+type apiHandlers struct {
+	health  *handlers.HealthRecordHandler
+	sleep   *handlers.SleepHandler
+	weight  *handlers.WeightHandler
+	vitals  *handlers.VitalHandler
+	metrics *handlers.MetricHandler
+	goals   *handlers.GoalHandler
+	apiKeys *handlers.APIKeyHandler
+}
+
 // main is the application entry point.
 // It initializes the database connection, configures routing, and starts the HTTP server.
+// Given a subcommand, it runs that instead (see runCommand).
 func main() {
+	// JSON log lines of the configured level; the log package writes through it too
+	slog.SetDefault(logging.New(os.Stderr, config.LogCfg.Level))
+
+	if len(os.Args) > 1 {
+		if err := runCommand(os.Args[1], os.Args[2:]); err != nil {
+			slog.Error("command failed", slog.String("command", os.Args[1]), slog.Any("error", err))
+			os.Exit(1)
+		}
+		return
+	}
+
+	if err := runServer(); err != nil {
+		slog.Error("server failed", slog.Any("error", err))
+		os.Exit(1)
+	}
+}
+
+// runServer serves the API until SIGINT or SIGTERM, then drains in-flight requests
+// and closes the database
+func runServer() error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// Configure database connection settings
 	db, err := database.NewDatabase()
 	if err != nil {
-		log.Fatalf("failed to initialize database: %v", err)
+		return fmt.Errorf("failed to initialize database: %w", err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			slog.Error("failed to close database", slog.Any("error", err))
+		}
+	}()
+
+	// The handlers use the instrumented database, so every call feeds the query latency metrics
+	store := database.Instrument(db, observeQuery)
+
+	// Initialize handlers
+	h := &apiHandlers{
+		health:  handlers.NewHealthRecordHandler(store),
+		sleep:   handlers.NewSleepHandler(store),
+		weight:  handlers.NewWeightHandler(store),
+		vitals:  handlers.NewVitalHandler(store),
+		metrics: handlers.NewMetricHandler(store),
+		goals:   handlers.NewGoalHandler(store),
+		apiKeys: handlers.NewAPIKeyHandler(store),
 	}
-	defer db.Close()
 
-	// Initialize handler
-	healthHandler := handlers.NewHealthRecordHandler(db)
+	// POST requests with an Idempotency-Key are replayed from the database on retries
+	idempotency := handlers.NewIdempotencyHandler(store)
+	go purgeIdempotencyKeys(ctx, store, idempotencyPurgeInterval)
+
+	// Register route handlers.
+	// With a signing key configured every request needs a bearer token or API key;
+	// otherwise all requests are attributed to the default user.
+	mux := http.NewServeMux()
 
-	// Register route handlers
-	http.HandleFunc("/", logMiddleware(routeHandler(healthHandler)))
+	// Probes and metrics are served without authentication and are not logged.
+	// Metrics include usage figures, so with METRICS_PORT they get a listener of their own.
+	health := handlers.NewHealthHandler(db)
+	mux.HandleFunc("GET "+livezPath, health.Livez)
+	mux.HandleFunc("GET "+readyzPath, health.Readyz)
+	mux.HandleFunc("GET "+debugDBPath, health.DebugDB)
 
-	// Start the server
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8000"
+	metricsHandler := promhttp.HandlerFor(newMetricsRegistry(db), promhttp.HandlerOpts{})
+	if config.ServerCfg.MetricsPort == "" {
+		mux.Handle("GET "+metricsPromPath, metricsHandler)
+	} else {
+		metricsMux := http.NewServeMux()
+		metricsMux.Handle("GET "+metricsPromPath, metricsHandler)
+		if err := serveMetrics(ctx, newMetricsServer(config.ServerCfg, metricsMux), config.ServerCfg.ShutdownTimeout); err != nil {
+			return err
+		}
+	}
+
+	if config.AuthCfg.Enabled() {
+		tokens, err := auth.NewTokenManager(config.AuthCfg)
+		if err != nil {
+			return fmt.Errorf("failed to initialize token signing: %w", err)
+		}
+		authHandler := handlers.NewAuthHandler(store, tokens)
+
+		mux.HandleFunc(authPath, logMiddleware(authRouteHandler(authHandler)))
+		mux.HandleFunc("/", logMiddleware(authHandler.Authenticate(idempotency.Idempotent(routeHandler(h)))))
+		slog.Info("token authentication enabled", slog.String("algorithm", config.AuthCfg.Algorithm))
+	} else {
+		userID, err := defaultUserID(db)
+		if err != nil {
+			return fmt.Errorf("failed to initialize default user: %w", err)
+		}
+		mux.HandleFunc("/", logMiddleware(userMiddleware(userID, idempotency.Idempotent(routeHandler(h)))))
+	}
+
+	// Start the server. Every request gets a request ID, including the probes.
+	return serve(ctx, newServer(config.ServerCfg, requestIDMiddleware(mux)), config.ServerCfg.ShutdownTimeout)
+}
+
+// runCommand runs a command line subcommand.
+//
+// Currently supported subcommands:
+// - import-apple-health - Alias of import -format apple-health
+// - import - Import Apple Health, Google Fit or Fitbit exports
+// - migrate - Apply, revert or list the schema migrations (up, down, status)
+func runCommand(name string, args []string) error {
+	switch name {
+	case "migrate":
+		return runMigrate(args)
+	case "import":
+		return runImport(args)
+	case "import-apple-health":
+		return runImportAppleHealth(args)
+	default:
+		return fmt.Errorf("unknown command: %s", name)
 	}
-	log.Printf("Server is running on http://localhost:%s", port)
-	log.Fatal(http.ListenAndServe(":"+port, nil))
 }
 
 // routeHandler returns a handler function that processes all API routes.
@@ -49,21 +200,124 @@ func main() {
 //
 // Currently supported endpoints:
 // - /health/records - Health record management (GET, POST, PUT, DELETE)
-func routeHandler(handler *handlers.HealthRecordHandler) http.HandlerFunc {
+// - /health/records/{date} - Create or replace the record of a date (PUT)
+// - /health/records:batch - Create or replace many records in a single transaction (POST)
+// - /health/records/stats - Step count statistics (GET)
+// - /health/records/export - CSV export of health records (GET)
+// - /health/records/import - CSV import of health records (POST)
+// - /health/records/import/apple-health - Apple Health export.xml import (POST)
+// - /health/sleep - Sleep session management (GET, POST, PUT, DELETE)
+// - /health/weight - Weight readings (GET, POST, DELETE)
+// - /health/weight/daily - Daily weight values (GET)
+// - /health/height - Height profile used for BMI (GET, PUT)
+// - /health/blood-pressure, /health/heart-rate - Vital sign readings (GET, POST)
+// - /health/blood-pressure/daily, /health/heart-rate/daily - Daily min, max and average (GET)
+// - /health/metrics - Custom metric definitions (GET, POST, DELETE)
+// - /health/metrics/values - Custom metric values (GET, POST)
+// - /health/metrics/daily - Daily custom metric values (GET)
+// - /health/goals - Daily step goals (GET, PUT, DELETE)
+// - /health/goals/progress - Daily goal completion and monthly completion rate (GET)
+// - /health/goals/streaks - Current and longest goal streak (GET)
+// - /account/api-keys - Personal API keys (GET, POST, PUT, DELETE)
+func routeHandler(h *apiHandlers) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Set common response headers
 		setCommonHeaders(w)
 
 		// Route based on path
-		switch strings.TrimSuffix(r.URL.Path, "/") {
+		path := strings.TrimSuffix(r.URL.Path, "/")
+		switch path {
 		case healthRecordsPath:
-			handleHealthRecords(handler, w, r)
+			handleHealthRecords(h.health, w, r)
+		case healthBatchPath:
+			handleUpload(h.health.BatchHealthRecords, w, r)
+		case healthStatsPath:
+			handleReadings(h.health.GetHealthRecordStats, nil, w, r)
+		case healthExportPath:
+			handleReadings(h.health.ExportHealthRecords, nil, w, r)
+		case healthImportPath:
+			handleUpload(h.health.ImportHealthRecords, w, r)
+		case appleHealthPath:
+			handleUpload(h.health.ImportAppleHealth, w, r)
+		case sleepSessionsPath:
+			handleSleepSessions(h.sleep, w, r)
+		case weightPath:
+			handleWeightReadings(h.weight, w, r)
+		case dailyWeightPath:
+			handleDailyWeights(h.weight, w, r)
+		case heightPath:
+			handleHeightProfile(h.weight, w, r)
+		case bloodPressurePath:
+			handleReadings(h.vitals.GetBloodPressureReadings, h.vitals.CreateBloodPressureReading, w, r)
+		case dailyBloodPressurePath:
+			handleReadings(h.vitals.GetDailyBloodPressure, nil, w, r)
+		case heartRatePath:
+			handleReadings(h.vitals.GetHeartRateReadings, h.vitals.CreateHeartRateReading, w, r)
+		case dailyHeartRatePath:
+			handleReadings(h.vitals.GetDailyHeartRate, nil, w, r)
+		case metricsPath:
+			handleMetricDefinitions(h.metrics, w, r)
+		case metricValuesPath:
+			handleReadings(h.metrics.GetMetricValues, h.metrics.CreateMetricValue, w, r)
+		case dailyMetricValuesPath:
+			handleReadings(h.metrics.GetDailyMetricValues, nil, w, r)
+		case goalsPath:
+			handleStepGoals(h.goals, w, r)
+		case goalProgressPath:
+			handleReadings(h.goals.GetGoalProgress, nil, w, r)
+		case goalStreaksPath:
+			handleReadings(h.goals.GetGoalStreaks, nil, w, r)
+		case apiKeysPath:
+			handleAPIKeys(h.apiKeys, w, r)
 		default:
+			// /health/records/{date}
+			if date, ok := strings.CutPrefix(path, healthRecordsPath+"/"); ok && !strings.Contains(date, "/") {
+				r.SetPathValue("date", date)
+				handleHealthRecord(h.health, w, r)
+				return
+			}
 			http.NotFound(w, r)
 		}
 	}
 }
 
+// authRouteHandler returns a handler function that processes the authentication routes.
+// These endpoints are served without a bearer token.
+//
+// Currently supported endpoints:
+// - /auth/register - Create a user account (POST)
+// - /auth/login - Issue an access and refresh token for email and password (POST)
+// - /auth/refresh - Exchange a refresh token for a new token pair (POST)
+func authRouteHandler(h *handlers.AuthHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Set common response headers
+		setCommonHeaders(w)
+
+		var handle http.HandlerFunc
+		switch strings.TrimSuffix(r.URL.Path, "/") {
+		case authRegisterPath:
+			handle = h.Register
+		case authLoginPath:
+			handle = h.Login
+		case authRefreshPath:
+			handle = h.Refresh
+		default:
+			http.NotFound(w, r)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodOptions:
+			// CORS preflight request support
+			w.WriteHeader(http.StatusOK)
+		case http.MethodPost:
+			handle(w, r)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	}
+}
+
 // handleHealthMethod processes HTTP methods (GET, POST, PUT, DELETE) for health records.
 // It calls the appropriate handler function based on the method:
 // - GET: Retrieve health records
@@ -94,38 +348,286 @@ func handleHealthRecords(handler *handlers.HealthRecordHandler, w http.ResponseW
 	}
 }
 
+// handleHealthRecord processes HTTP methods (PUT) for the record of a single date.
+// It also handles CORS preflight requests (OPTIONS).
+func handleHealthRecord(handler *handlers.HealthRecordHandler, w http.ResponseWriter, r *http.Request) {
+	// CORS preflight request support
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		handler.PutHealthRecord(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleSleepSessions processes HTTP methods (GET, POST, PUT, DELETE) for sleep sessions.
+// It also handles CORS preflight requests (OPTIONS).
+func handleSleepSessions(handler *handlers.SleepHandler, w http.ResponseWriter, r *http.Request) {
+	// CORS preflight request support
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		handler.GetSleepSessions(w, r)
+	case http.MethodPost:
+		handler.CreateSleepSession(w, r)
+	case http.MethodPut:
+		handler.UpdateSleepSession(w, r)
+	case http.MethodDelete:
+		handler.DeleteSleepSession(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleWeightReadings processes HTTP methods (GET, POST, DELETE) for weight readings.
+// It also handles CORS preflight requests (OPTIONS).
+func handleWeightReadings(handler *handlers.WeightHandler, w http.ResponseWriter, r *http.Request) {
+	// CORS preflight request support
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		handler.GetWeightReadings(w, r)
+	case http.MethodPost:
+		handler.CreateWeightReading(w, r)
+	case http.MethodDelete:
+		handler.DeleteWeightReading(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleDailyWeights processes HTTP methods (GET) for daily weight values.
+// It also handles CORS preflight requests (OPTIONS).
+func handleDailyWeights(handler *handlers.WeightHandler, w http.ResponseWriter, r *http.Request) {
+	// CORS preflight request support
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		handler.GetDailyWeights(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleHeightProfile processes HTTP methods (GET, PUT) for the height profile.
+// It also handles CORS preflight requests (OPTIONS).
+func handleHeightProfile(handler *handlers.WeightHandler, w http.ResponseWriter, r *http.Request) {
+	// CORS preflight request support
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		handler.GetHeightProfile(w, r)
+	case http.MethodPut:
+		handler.PutHeightProfile(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleReadings processes HTTP methods (GET, POST) for append-only reading endpoints.
+// A nil create handler makes the endpoint read-only.
+// It also handles CORS preflight requests (OPTIONS).
+func handleReadings(get, create http.HandlerFunc, w http.ResponseWriter, r *http.Request) {
+	// CORS preflight request support
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet:
+		get(w, r)
+	case r.Method == http.MethodPost && create != nil:
+		create(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleUpload processes HTTP methods (POST) for file upload endpoints.
+// It also handles CORS preflight requests (OPTIONS).
+func handleUpload(upload http.HandlerFunc, w http.ResponseWriter, r *http.Request) {
+	// CORS preflight request support
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		upload(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleMetricDefinitions processes HTTP methods (GET, POST, DELETE) for custom metric definitions.
+// It also handles CORS preflight requests (OPTIONS).
+func handleMetricDefinitions(handler *handlers.MetricHandler, w http.ResponseWriter, r *http.Request) {
+	// CORS preflight request support
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		handler.GetMetricDefinitions(w, r)
+	case http.MethodPost:
+		handler.CreateMetricDefinition(w, r)
+	case http.MethodDelete:
+		handler.DeleteMetricDefinition(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleStepGoals processes HTTP methods (GET, PUT, DELETE) for daily step goals.
+// PUT sets the goal effective from a date, replacing one set for the same date.
+// It also handles CORS preflight requests (OPTIONS).
+func handleStepGoals(handler *handlers.GoalHandler, w http.ResponseWriter, r *http.Request) {
+	// CORS preflight request support
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		handler.GetStepGoals(w, r)
+	case http.MethodPut:
+		handler.PutStepGoal(w, r)
+	case http.MethodDelete:
+		handler.DeleteStepGoal(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleAPIKeys processes HTTP methods (GET, POST, PUT, DELETE) for personal API keys.
+// PUT renames a key and DELETE revokes it.
+// It also handles CORS preflight requests (OPTIONS).
+func handleAPIKeys(handler *handlers.APIKeyHandler, w http.ResponseWriter, r *http.Request) {
+	// CORS preflight request support
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		handler.GetAPIKeys(w, r)
+	case http.MethodPost:
+		handler.CreateAPIKey(w, r)
+	case http.MethodPut:
+		handler.UpdateAPIKey(w, r)
+	case http.MethodDelete:
+		handler.RevokeAPIKey(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
 // setCommonHeaders sets common HTTP headers for all responses.
 // Headers set:
 // - Content-Type: application/json
 // - Access-Control-Allow-Origin: * (CORS support)
 // - Access-Control-Allow-Methods
 // - Access-Control-Allow-Headers
+// - Access-Control-Expose-Headers
 //
 // Note: More restrictive CORS settings are recommended for production environments.
 func setCommonHeaders(w http.ResponseWriter) {
 	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("Access-Control-Allow-Origin", "*") // CORS
 	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key, If-Match, If-None-Match, Idempotency-Key, X-Request-ID")
+	w.Header().Set("Access-Control-Expose-Headers", "ETag, Idempotency-Replayed, X-Request-ID")
+}
+
+// defaultUserID returns the ID of the default user, creating the user on first start
+func defaultUserID(db database.UserStore) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(config.RequestTimeoutSecond)*time.Second)
+	defer cancel()
+
+	user, err := db.ReadUserByEmail(ctx, config.DefaultUserEmail)
+	if err != nil {
+		return 0, fmt.Errorf("read default user: %w", err)
+	}
+	if user == nil {
+		user, err = db.CreateUser(ctx, &models.User{Email: config.DefaultUserEmail})
+		if err != nil {
+			return 0, fmt.Errorf("create default user: %w", err)
+		}
+	}
+
+	return user.ID, nil
+}
+
+// userMiddleware is middleware that attributes every request to the given user.
+func userMiddleware(userID int64, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		next(w, r.WithContext(auth.WithUserID(r.Context(), userID)))
+	}
+}
+
+// requestIDMiddleware is middleware that identifies every request by an X-Request-ID.
+// A valid ID sent by the client is kept, otherwise one is generated. The ID is echoed
+// in the response and added to every line of the request's context logger.
+func requestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(logging.RequestIDHeader)
+		if !logging.ValidRequestID(id) {
+			id = logging.NewRequestID()
+		}
+
+		w.Header().Set(logging.RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(logging.WithRequestID(r.Context(), id)))
+	})
 }
 
 // logMiddleware is middleware that logs HTTP request details.
-// It records the request method, path, client IP address, and processing time
-// to the log output.
+// It records the request method, path, client IP address, status code and processing time
+// with the request's context logger, and feeds the request count and duration metrics.
 func logMiddleware(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		startTime := time.Now()
 
 		// call the warapped handler
-		next(w, r)
+		rec := &statusRecorder{ResponseWriter: w}
+		next(rec, r)
+
+		duration := time.Since(startTime)
+		observeRequest(r, rec.Status(), duration)
 
 		// Log the request details
-		log.Printf(
-			"[%s] %s %s %s",
-			r.Method,
-			r.URL.Path,
-			r.RemoteAddr,
-			time.Since(startTime),
+		logging.FromContext(r.Context()).InfoContext(r.Context(), "request",
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+			slog.String("remote_addr", r.RemoteAddr),
+			slog.Int("status", rec.Status()),
+			logging.Duration(duration),
 		)
 	}
 }
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -19,17 +20,35 @@ import (
 var testServer *httptest.Server
 
 func TestMain(m *testing.M) {
-	// Set up a database for testing
-	db, err := database.NewDB(":memory:")
+	// Set up a database for testing. The server's connection pool needs a file,
+	// since every connection to ":memory:" opens a database of its own.
+	dir, err := os.MkdirTemp("", "health-tracker-test")
+	if err != nil {
+		panic(err)
+	}
+	db, err := database.NewSQLiteDB(filepath.Join(dir, "test.db"))
+	if err != nil {
+		panic(err)
+	}
+
+	userID, err := defaultUserID(db)
 	if err != nil {
 		panic(err)
 	}
 
 	// Set up server for testing
-	healthHandler := handlers.NewHealthRecordHandler(db)
+	h := &apiHandlers{
+		health:  handlers.NewHealthRecordHandler(db),
+		sleep:   handlers.NewSleepHandler(db),
+		weight:  handlers.NewWeightHandler(db),
+		vitals:  handlers.NewVitalHandler(db),
+		metrics: handlers.NewMetricHandler(db),
+		goals:   handlers.NewGoalHandler(db),
+		apiKeys: handlers.NewAPIKeyHandler(db),
+	}
 
 	testServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		routeHandler(healthHandler)(w, r)
+		userMiddleware(userID, routeHandler(h))(w, r)
 	}))
 
 	// Run all tests
@@ -38,6 +57,7 @@ func TestMain(m *testing.M) {
 	// Clean up
 	testServer.Close()
 	db.Close()
+	os.RemoveAll(dir)
 
 	os.Exit(code)
 }
@@ -193,8 +213,17 @@ func TestHealthRecordIntegration(t *testing.T) {
 	}
 	defer checkDeletedRes.Body.Close()
 
-	if checkDeletedRes.StatusCode != http.StatusNotFound {
-		t.Errorf("expected status NotFound after delete, got %v", checkDeletedRes.Status)
+	// A date without a record is an empty result
+	if checkDeletedRes.StatusCode != http.StatusOK {
+		t.Errorf("expected status OK after delete, got %v", checkDeletedRes.Status)
+	}
+
+	var deletedResult handlers.HealthRecordResult
+	if err := json.NewDecoder(checkDeletedRes.Body).Decode(&deletedResult); err != nil {
+		t.Fatalf("failed to decode response after delete: %v", err)
+	}
+	if len(deletedResult.Records) != 0 {
+		t.Errorf("expected no records after delete, got %d", len(deletedResult.Records))
 	}
 }
 
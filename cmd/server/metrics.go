@@ -0,0 +1,225 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/config"
+	"github.com/nnamm/go-health-tracker/internal/database"
+	"github.com/nnamm/go-health-tracker/internal/logging"
+	"github.com/nnamm/go-health-tracker/internal/models"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// usageCacheTTL is how long usage figures are reused, so one scrape reads them once
+const usageCacheTTL = 15 * time.Second
+
+// Metrics of the served requests and database calls
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Number of HTTP requests by route, method and status code.",
+	}, []string{"route", "method", "status"})
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Duration of HTTP requests by route, method and status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+	dbQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "Duration of database calls by DBInterface method.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method"})
+)
+
+// Usage metrics, see usageCollector
+var (
+	usersDesc          = prometheus.NewDesc("health_tracker_users", "Number of user accounts.", nil, nil)
+	recordsWrittenDesc = prometheus.NewDesc("health_tracker_health_records_written_today", "Number of health records created or updated since midnight UTC.", nil, nil)
+)
+
+// knownRoutes are the paths used as route labels as they are; any other path is reported as "other",
+// so unknown URLs can't create an unbounded number of series
+var knownRoutes = map[string]bool{
+	healthRecordsPath: true, healthBatchPath: true, healthStatsPath: true, healthExportPath: true,
+	healthImportPath: true, appleHealthPath: true, sleepSessionsPath: true, weightPath: true,
+	dailyWeightPath: true, heightPath: true, goalsPath: true, goalProgressPath: true,
+	goalStreaksPath: true, bloodPressurePath: true, dailyBloodPressurePath: true, heartRatePath: true,
+	dailyHeartRatePath: true, metricsPath: true, metricValuesPath: true, dailyMetricValuesPath: true,
+	apiKeysPath: true, authRegisterPath: true, authLoginPath: true, authRefreshPath: true,
+}
+
+// newMetricsRegistry creates the registry served on /metrics: request and query metrics,
+// the connection pool of a PostgreSQL database and usage figures if the database reports them
+func newMetricsRegistry(db database.DBInterface) *prometheus.Registry {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(httpRequests, httpRequestDuration, dbQueryDuration)
+
+	if pg, ok := db.(*database.PostgresDB); ok {
+		registerPoolMetrics(registry, pg)
+	}
+	if reader, ok := db.(database.UsageReader); ok {
+		registry.MustRegister(&usageCollector{usage: &usageCache{reader: reader}})
+	}
+
+	return registry
+}
+
+// registerPoolMetrics registers gauges and counters of the pgx connection pool
+func registerPoolMetrics(registry *prometheus.Registry, db *database.PostgresDB) {
+	gauge := func(name, help string, value func() float64) prometheus.Collector {
+		return prometheus.NewGaugeFunc(prometheus.GaugeOpts{Name: name, Help: help}, value)
+	}
+	counter := func(name, help string, value func() float64) prometheus.Collector {
+		return prometheus.NewCounterFunc(prometheus.CounterOpts{Name: name, Help: help}, value)
+	}
+
+	registry.MustRegister(
+		gauge("db_pool_acquired_connections", "Number of connections currently in use.",
+			func() float64 { return float64(db.Stats().AcquiredConns()) }),
+		gauge("db_pool_idle_connections", "Number of idle connections in the pool.",
+			func() float64 { return float64(db.Stats().IdleConns()) }),
+		gauge("db_pool_total_connections", "Number of open connections in the pool.",
+			func() float64 { return float64(db.Stats().TotalConns()) }),
+		gauge("db_pool_max_connections", "Maximum size of the pool.",
+			func() float64 { return float64(db.Stats().MaxConns()) }),
+		counter("db_pool_acquires_total", "Number of successful connection acquires.",
+			func() float64 { return float64(db.Stats().AcquireCount()) }),
+		counter("db_pool_acquire_duration_seconds_total", "Total time spent acquiring connections.",
+			func() float64 { return db.Stats().AcquireDuration().Seconds() }),
+		counter("db_pool_empty_acquires_total", "Number of acquires that had to wait for a connection.",
+			func() float64 { return float64(db.Stats().EmptyAcquireCount()) }),
+		counter("db_pool_canceled_acquires_total", "Number of acquires canceled by their context.",
+			func() float64 { return float64(db.Stats().CanceledAcquireCount()) }),
+	)
+}
+
+// usageCollector reports the business gauges of the service.
+// When the usage can't be read, the gauges are left out of the scrape rather than reported as zero.
+type usageCollector struct {
+	usage *usageCache
+}
+
+// Describe implements the prometheus.Collector interface
+func (c *usageCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- usersDesc
+	ch <- recordsWrittenDesc
+}
+
+// Collect implements the prometheus.Collector interface
+func (c *usageCollector) Collect(ch chan<- prometheus.Metric) {
+	u := c.usage.get()
+	if u == nil {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(usersDesc, prometheus.GaugeValue, float64(u.Users))
+	ch <- prometheus.MustNewConstMetric(recordsWrittenDesc, prometheus.GaugeValue, float64(u.RecordsWritten))
+}
+
+// usageCache reads the usage figures at most once per usageCacheTTL
+type usageCache struct {
+	reader database.UsageReader
+
+	mu     sync.Mutex
+	readAt time.Time
+	usage  *models.Usage
+}
+
+// get returns the usage since midnight UTC, or nil if it can't be read
+func (c *usageCache) get() *models.Usage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if c.usage != nil && now.Sub(c.readAt) < usageCacheTTL {
+		return c.usage
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	today := now.UTC().Truncate(24 * time.Hour)
+	usage, err := c.reader.ReadUsage(ctx, today)
+	if err != nil {
+		slog.Error("failed to read usage metrics", slog.Any("error", err))
+		return nil
+	}
+
+	c.readAt = now
+	c.usage = usage
+	return usage
+}
+
+// observeRequest records a served request
+func observeRequest(r *http.Request, status int, d time.Duration) {
+	route := routeLabel(r.URL.Path)
+	code := strconv.Itoa(status)
+
+	httpRequests.WithLabelValues(route, r.Method, code).Inc()
+	httpRequestDuration.WithLabelValues(route, r.Method, code).Observe(d.Seconds())
+}
+
+// observeQuery records a database call; it is the database.QueryObserver of the instrumented database.
+// Calls are logged with the logger of the request that made them, slow ones as warnings.
+func observeQuery(ctx context.Context, method string, d time.Duration) {
+	dbQueryDuration.WithLabelValues(method).Observe(d.Seconds())
+
+	logger := logging.FromContext(ctx)
+	if d >= config.LogCfg.SlowQueryThreshold {
+		logger.WarnContext(ctx, "slow database query", slog.String("method", method), logging.Duration(d))
+		return
+	}
+	logger.DebugContext(ctx, "database query", slog.String("method", method), logging.Duration(d))
+}
+
+// routeLabel returns the route of a request path for the metric labels
+func routeLabel(path string) string {
+	path = strings.TrimSuffix(path, "/")
+	if knownRoutes[path] {
+		return path
+	}
+	if date, ok := strings.CutPrefix(path, healthRecordsPath+"/"); ok && !strings.Contains(date, "/") {
+		return healthRecordsPath + "/{date}"
+	}
+	return "other"
+}
+
+// statusRecorder captures the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader records the status code and writes it
+func (rec *statusRecorder) WriteHeader(code int) {
+	if rec.status == 0 {
+		rec.status = code
+	}
+	rec.ResponseWriter.WriteHeader(code)
+}
+
+// Write records an implicit 200 status before the first write
+func (rec *statusRecorder) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	return rec.ResponseWriter.Write(b)
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (rec *statusRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
+
+// Status returns the recorded status code; a handler that wrote nothing responded 200
+func (rec *statusRecorder) Status() int {
+	if rec.status == 0 {
+		return http.StatusOK
+	}
+	return rec.status
+}
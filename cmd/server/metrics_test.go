@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/database"
+	"github.com/nnamm/go-health-tracker/internal/models"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// fakeUsageDB is a DBInterface that only reports usage; other methods are not used and panic
+type fakeUsageDB struct {
+	database.DBInterface
+	usage *models.Usage
+	err   error
+}
+
+func (f *fakeUsageDB) ReadUsage(context.Context, time.Time) (*models.Usage, error) {
+	return f.usage, f.err
+}
+
+// scrapeMetrics returns the text served on /metrics for the database
+func scrapeMetrics(t *testing.T, db database.DBInterface) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	promhttp.HandlerFor(newMetricsRegistry(db), promhttp.HandlerOpts{}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, metricsPromPath, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("scrape status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	return rec.Body.String()
+}
+
+func TestMetricsRegistry(t *testing.T) {
+	observeRequest(httptest.NewRequest(http.MethodPatch, "/unknown/path", nil), http.StatusTeapot, 20*time.Millisecond)
+
+	body := scrapeMetrics(t, &fakeUsageDB{usage: &models.Usage{Users: 3, RecordsWritten: 12}})
+	for _, want := range []string{
+		`http_requests_total{method="PATCH",route="other",status="418"} 1`,
+		`http_request_duration_seconds_bucket{method="PATCH",route="other",status="418",le="0.01"} 0`,
+		`http_request_duration_seconds_bucket{method="PATCH",route="other",status="418",le="0.025"} 1`,
+		`http_request_duration_seconds_bucket{method="PATCH",route="other",status="418",le="+Inf"} 1`,
+		`http_request_duration_seconds_count{method="PATCH",route="other",status="418"} 1`,
+		"health_tracker_users 3\n",
+		"health_tracker_health_records_written_today 12\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics do not contain %q:\n%s", want, body)
+		}
+	}
+
+	// Usage that can't be read is left out rather than reported as zero
+	if body := scrapeMetrics(t, &fakeUsageDB{err: errors.New("database is down")}); strings.Contains(body, "health_tracker_users") {
+		t.Errorf("metrics contain the usage gauges although usage can't be read:\n%s", body)
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+
+	"github.com/nnamm/go-health-tracker/internal/database"
+)
+
+// runMigrate applies, reverts or lists the schema migrations of the configured database.
+// The server applies pending migrations on start as well; down reverts one migration unless -steps is given.
+//
+//	server migrate up
+//	server migrate down [-steps 1]
+//	server migrate status
+func runMigrate(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: migrate up|down|status")
+	}
+
+	fs := flag.NewFlagSet("migrate "+args[0], flag.ContinueOnError)
+	steps := fs.Int("steps", 1, "number of migrations to revert")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return fmt.Errorf("usage: migrate up|down [-steps n]|status")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	migrator, err := database.NewMigrator()
+	if err != nil {
+		return fmt.Errorf("initialize database: %w", err)
+	}
+	defer migrator.Close()
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.MigrateUp(ctx)
+		for _, m := range applied {
+			slog.Info("applied migration", slog.Int("version", m.Version), slog.String("name", m.Name))
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			slog.Info("no pending migrations")
+		}
+	case "down":
+		if *steps < 1 {
+			return fmt.Errorf("steps must be at least 1")
+		}
+		reverted, err := migrator.MigrateDown(ctx, *steps)
+		for _, m := range reverted {
+			slog.Info("reverted migration", slog.Int("version", m.Version), slog.String("name", m.Name))
+		}
+		if err != nil {
+			return err
+		}
+		if len(reverted) == 0 {
+			slog.Info("no applied migrations")
+		}
+	case "status":
+		statuses, err := migrator.MigrationStatus(ctx)
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = "applied " + s.AppliedAt.Local().Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%s\t%s\n", s.Version, s.Name, applied)
+		}
+	default:
+		return fmt.Errorf("unknown migrate command: %s (use up, down or status)", args[0])
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/database"
+)
+
+// idempotencyPurgeInterval is how often expired idempotency keys are deleted
+const idempotencyPurgeInterval = time.Hour
+
+// purgeIdempotencyKeys deletes expired idempotency keys every interval until the context is done
+func purgeIdempotencyKeys(ctx context.Context, store database.IdempotencyStore, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			purged, err := store.PurgeIdempotencyKeys(ctx, now)
+			if err != nil {
+				slog.Error("failed to purge idempotency keys", slog.Any("error", err))
+				continue
+			}
+			if purged > 0 {
+				slog.Info("purged expired idempotency keys", slog.Int64("purged", purged))
+			}
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/config"
+)
+
+// newServer creates the HTTP server of the API with the configured timeouts
+func newServer(cfg *config.ServerConfig, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              ":" + cfg.Port,
+		Handler:           handler,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}
+}
+
+// newMetricsServer creates the HTTP server of the metrics listener on MetricsPort
+func newMetricsServer(cfg *config.ServerConfig, handler http.Handler) *http.Server {
+	srv := newServer(cfg, handler)
+	srv.Addr = ":" + cfg.MetricsPort
+	return srv
+}
+
+// serveMetrics runs the metrics server in the background until the context is done.
+// The port is bound before it returns, so a port already in use fails the startup.
+func serveMetrics(ctx context.Context, srv *http.Server, shutdownTimeout time.Duration) error {
+	ln, err := net.Listen("tcp", srv.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen for metrics: %w", err)
+	}
+
+	go func() {
+		slog.Info("metrics server is running", slog.String("addr", "http://localhost"+srv.Addr))
+		if err := srv.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("metrics server failed", slog.Any("error", err))
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+	return nil
+}
+
+// serve runs the server until it fails or the context is done.
+// It then stops accepting connections and waits up to shutdownTimeout for in-flight requests;
+// connections still open after that are closed.
+func serve(ctx context.Context, srv *http.Server, shutdownTimeout time.Duration) error {
+	errCh := make(chan error, 1)
+	go func() {
+		slog.Info("server is running", slog.String("addr", "http://localhost"+srv.Addr))
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	slog.Info("shutting down, waiting for in-flight requests", slog.String("timeout", shutdownTimeout.String()))
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Error("graceful shutdown failed", slog.Any("error", err))
+		srv.Close()
+	}
+
+	// ListenAndServe returns ErrServerClosed as soon as Shutdown is called
+	if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	slog.Info("server stopped")
+	return nil
+}
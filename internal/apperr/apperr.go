@@ -1,20 +1,59 @@
 package apperr
 
+import (
+	"strings"
+	"unicode"
+)
+
 type ErrorType string
 
 const (
-	ErrorTypeBadRequest     ErrorType = "BadRequest"
-	ErrorTypeInvalidDate    ErrorType = "InvalidDate"
-	ErrorTypeInvalidYear    ErrorType = "InvalidYear"
-	ErrorTypeInvalidMonth   ErrorType = "InvalidMonth"
-	ErrorTypeInvalidFormat  ErrorType = "InvalidFormat"
-	ErrorTypeNotFound       ErrorType = "NotFound"
-	ErrorTypeInternalServer ErrorType = "InternalServer"
+	ErrorTypeBadRequest    ErrorType = "BadRequest"
+	ErrorTypeInvalidDate   ErrorType = "InvalidDate"
+	ErrorTypeInvalidYear   ErrorType = "InvalidYear"
+	ErrorTypeInvalidMonth  ErrorType = "InvalidMonth"
+	ErrorTypeInvalidRange  ErrorType = "InvalidRange"
+	ErrorTypeInvalidFormat ErrorType = "InvalidFormat"
+	ErrorTypeNotFound      ErrorType = "NotFound"
+	ErrorTypeUnauthorized  ErrorType = "Unauthorized"
+	ErrorTypeForbidden     ErrorType = "Forbidden"
+	ErrorTypeConflict      ErrorType = "Conflict"
+	// ErrorTypePreconditionFailed and ErrorTypePreconditionRequired report failed and missing If-Match conditions
+	ErrorTypePreconditionFailed   ErrorType = "PreconditionFailed"
+	ErrorTypePreconditionRequired ErrorType = "PreconditionRequired"
+	// ErrorTypeUnprocessableEntity reports a well-formed request that cannot be processed, e.g. a reused Idempotency-Key
+	ErrorTypeUnprocessableEntity ErrorType = "UnprocessableEntity"
+	ErrorTypeInternalServer      ErrorType = "InternalServer"
 )
 
+// Code returns the stable machine-readable code of the error type, e.g. "invalid_date"
+func (t ErrorType) Code() string {
+	var b strings.Builder
+	for i, r := range string(t) {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// FieldError is a validation problem of a single field
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
 type AppError struct {
 	Type    ErrorType
 	Message string
+
+	// Fields lists every field-level problem of a validation failure
+	Fields []FieldError
 }
 
 func (e AppError) Error() string {
@@ -28,3 +67,17 @@ func NewAppError(errorType ErrorType, message string) AppError {
 		Message: message,
 	}
 }
+
+// NewValidationError creates an AppError reporting all field-level problems.
+// Its message lists every problem.
+func NewValidationError(errorType ErrorType, fields []FieldError) AppError {
+	messages := make([]string, len(fields))
+	for i, f := range fields {
+		messages[i] = f.Message
+	}
+	return AppError{
+		Type:    errorType,
+		Message: strings.Join(messages, "; "),
+		Fields:  fields,
+	}
+}
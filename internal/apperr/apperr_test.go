@@ -40,3 +40,43 @@ func TestNewAppError(t *testing.T) {
 		})
 	}
 }
+
+func TestErrorType_Code(t *testing.T) {
+	tests := []struct {
+		errType ErrorType
+		want    string
+	}{
+		{ErrorTypeBadRequest, "bad_request"},
+		{ErrorTypeInvalidDate, "invalid_date"},
+		{ErrorTypeNotFound, "not_found"},
+		{ErrorTypeConflict, "conflict"},
+		{ErrorTypeUnprocessableEntity, "unprocessable_entity"},
+		{ErrorTypeInternalServer, "internal_server"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.errType), func(t *testing.T) {
+			if got := tt.errType.Code(); got != tt.want {
+				t.Errorf("ErrorType.Code() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewValidationError(t *testing.T) {
+	fields := []FieldError{
+		{Field: "date", Code: "invalid_date", Message: "date is required"},
+		{Field: "step_count", Code: "invalid_format", Message: "step count must not be negative"},
+	}
+
+	got := NewValidationError(ErrorTypeInvalidDate, fields)
+	if got.Type != ErrorTypeInvalidDate {
+		t.Errorf("NewValidationError().Type = %v, want %v", got.Type, ErrorTypeInvalidDate)
+	}
+	if want := "date is required; step count must not be negative"; got.Message != want {
+		t.Errorf("NewValidationError().Message = %v, want %v", got.Message, want)
+	}
+	if len(got.Fields) != 2 {
+		t.Errorf("NewValidationError().Fields = %v, want %v", got.Fields, fields)
+	}
+}
@@ -0,0 +1,37 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+const (
+	// apiKeyPrefix marks API keys so they are recognizable in configs and secret scanners
+	apiKeyPrefix = "ht_"
+	// apiKeyBytes is the amount of randomness in a key
+	apiKeyBytes = 32
+	// apiKeyDisplayLength is the length of the key prefix shown when listing keys
+	apiKeyDisplayLength = 11
+)
+
+// GenerateAPIKey creates a new random API key.
+// It returns the key, which is shown to the user only once, its display prefix and the hash to store.
+func GenerateAPIKey() (key, prefix, hash string, err error) {
+	b := make([]byte, apiKeyBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", "", fmt.Errorf("generate api key: %w", err)
+	}
+
+	key = apiKeyPrefix + base64.RawURLEncoding.EncodeToString(b)
+	return key, key[:apiKeyDisplayLength], HashAPIKey(key), nil
+}
+
+// HashAPIKey returns the hash under which an API key is stored.
+// Keys carry 256 bits of randomness, so a plain SHA-256 is enough and allows lookup by hash.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
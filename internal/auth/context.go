@@ -0,0 +1,37 @@
+// Package auth identifies the user on whose behalf a request is processed.
+package auth
+
+import (
+	"context"
+
+	"github.com/nnamm/go-health-tracker/internal/models"
+)
+
+// userIDKey is the context key for the authenticated user ID
+type userIDKey struct{}
+
+// apiKeyScopeKey is the context key for the scope of the API key a request was authenticated with
+type apiKeyScopeKey struct{}
+
+// WithUserID returns a copy of ctx carrying the authenticated user ID
+func WithUserID(ctx context.Context, userID int64) context.Context {
+	return context.WithValue(ctx, userIDKey{}, userID)
+}
+
+// UserIDFromContext returns the authenticated user ID carried by ctx, if any
+func UserIDFromContext(ctx context.Context) (int64, bool) {
+	userID, ok := ctx.Value(userIDKey{}).(int64)
+	return userID, ok && userID > 0
+}
+
+// WithAPIKeyScope returns a copy of ctx marking the request as authenticated with an API key of the given scope
+func WithAPIKeyScope(ctx context.Context, scope models.APIKeyScope) context.Context {
+	return context.WithValue(ctx, apiKeyScopeKey{}, scope)
+}
+
+// APIKeyScopeFromContext returns the scope of the API key the request was authenticated with.
+// It returns false for requests authenticated otherwise.
+func APIKeyScopeFromContext(ctx context.Context) (models.APIKeyScope, bool) {
+	scope, ok := ctx.Value(apiKeyScopeKey{}).(models.APIKeyScope)
+	return scope, ok
+}
@@ -0,0 +1,39 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nnamm/go-health-tracker/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserIDFromContext(t *testing.T) {
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		wantID int64
+		wantOK bool
+	}{
+		{"with user", WithUserID(context.Background(), 42), 42, true},
+		{"without user", context.Background(), 0, false},
+		{"invalid user ID", WithUserID(context.Background(), 0), 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, ok := UserIDFromContext(tt.ctx)
+			assert.Equal(t, tt.wantID, id)
+			assert.Equal(t, tt.wantOK, ok)
+		})
+	}
+}
+
+func TestAPIKeyScopeFromContext(t *testing.T) {
+	scope, ok := APIKeyScopeFromContext(WithAPIKeyScope(context.Background(), models.APIKeyScopeReadOnly))
+	assert.True(t, ok)
+	assert.Equal(t, models.APIKeyScopeReadOnly, scope)
+
+	_, ok = APIKeyScopeFromContext(context.Background())
+	assert.False(t, ok)
+}
@@ -0,0 +1,27 @@
+package auth
+
+import "golang.org/x/crypto/bcrypt"
+
+// HashPassword returns the bcrypt hash of a password
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// dummyHash is a bcrypt hash at the default cost that empty hashes are compared against,
+// so checking the password of an unknown user or one without a password takes as long as any other check
+var dummyHash = []byte("$2a$10$vcBp7pkGT8lsw9iBetCVQOI00JKtju6IQ.i9xoIcmNpJn06AAALEC")
+
+// CheckPassword reports whether the password matches the bcrypt hash.
+// An empty hash never matches, so users without a password cannot log in;
+// pass an empty hash for an unknown user, so the response time doesn't reveal which accounts exist.
+func CheckPassword(hash, password string) bool {
+	if hash == "" {
+		bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
@@ -0,0 +1,25 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestPassword(t *testing.T) {
+	hash, err := HashPassword("correct horse")
+	require.NoError(t, err)
+	assert.NotEqual(t, "correct horse", hash)
+
+	assert.True(t, CheckPassword(hash, "correct horse"))
+	assert.False(t, CheckPassword(hash, "wrong horse"))
+	assert.False(t, CheckPassword("", "correct horse"), "users without a password cannot log in")
+}
+
+func TestDummyHashCost(t *testing.T) {
+	cost, err := bcrypt.Cost(dummyHash)
+	require.NoError(t, err)
+	assert.Equal(t, bcrypt.DefaultCost, cost, "the dummy hash must cost as much as the stored ones")
+}
@@ -0,0 +1,329 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/config"
+)
+
+// TokenType distinguishes short-lived access tokens from refresh tokens
+type TokenType string
+
+const (
+	TokenTypeAccess  TokenType = "access"
+	TokenTypeRefresh TokenType = "refresh"
+)
+
+var (
+	// ErrInvalidToken is returned for malformed tokens and tokens with a bad signature, issuer or type
+	ErrInvalidToken = errors.New("invalid token")
+	// ErrExpiredToken is returned for tokens past their expiry time
+	ErrExpiredToken = errors.New("token has expired")
+)
+
+// Claims is the payload of the JWTs issued by the TokenManager
+type Claims struct {
+	Issuer    string    `json:"iss"`
+	Subject   string    `json:"sub"`
+	Type      TokenType `json:"typ"`
+	IssuedAt  int64     `json:"iat"`
+	ExpiresAt int64     `json:"exp"`
+}
+
+// UserID returns the user ID carried in the subject claim
+func (c *Claims) UserID() (int64, error) {
+	userID, err := strconv.ParseInt(c.Subject, 10, 64)
+	if err != nil || userID <= 0 {
+		return 0, ErrInvalidToken
+	}
+	return userID, nil
+}
+
+// TokenPair is the response of a successful login or token refresh
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+// header is the JOSE header of the issued tokens
+type header struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ"`
+}
+
+// TokenManager issues and verifies HS256 or RS256 signed JWTs
+type TokenManager struct {
+	algorithm  string
+	secret     []byte
+	privateKey *rsa.PrivateKey
+	publicKey  *rsa.PublicKey
+	issuer     string
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+	now        func() time.Time
+}
+
+// NewHS256TokenManager creates a TokenManager signing with a shared secret
+func NewHS256TokenManager(secret []byte, issuer string, accessTTL, refreshTTL time.Duration) (*TokenManager, error) {
+	if len(secret) == 0 {
+		return nil, fmt.Errorf("HS256 secret is empty")
+	}
+	return &TokenManager{
+		algorithm:  config.SigningHS256,
+		secret:     secret,
+		issuer:     issuer,
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
+		now:        time.Now,
+	}, nil
+}
+
+// NewRS256TokenManager creates a TokenManager signing with an RSA key pair.
+// A nil private key gives a manager that can only verify tokens.
+func NewRS256TokenManager(privateKey *rsa.PrivateKey, publicKey *rsa.PublicKey, issuer string, accessTTL, refreshTTL time.Duration) (*TokenManager, error) {
+	if publicKey == nil && privateKey != nil {
+		publicKey = &privateKey.PublicKey
+	}
+	if publicKey == nil {
+		return nil, fmt.Errorf("RS256 requires a private or public key")
+	}
+	return &TokenManager{
+		algorithm:  config.SigningRS256,
+		privateKey: privateKey,
+		publicKey:  publicKey,
+		issuer:     issuer,
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
+		now:        time.Now,
+	}, nil
+}
+
+// NewTokenManager creates a TokenManager from the authentication configuration,
+// loading the RSA keys from their PEM files
+func NewTokenManager(cfg *config.AuthConfig) (*TokenManager, error) {
+	switch cfg.Algorithm {
+	case config.SigningHS256:
+		return NewHS256TokenManager([]byte(cfg.Secret), cfg.Issuer, cfg.AccessTokenTTL, cfg.RefreshTokenTTL)
+	case config.SigningRS256:
+		var privateKey *rsa.PrivateKey
+		var publicKey *rsa.PublicKey
+		var err error
+		if cfg.PrivateKeyFile != "" {
+			if privateKey, err = loadPrivateKey(cfg.PrivateKeyFile); err != nil {
+				return nil, err
+			}
+		}
+		if cfg.PublicKeyFile != "" {
+			if publicKey, err = loadPublicKey(cfg.PublicKeyFile); err != nil {
+				return nil, err
+			}
+		}
+		return NewRS256TokenManager(privateKey, publicKey, cfg.Issuer, cfg.AccessTokenTTL, cfg.RefreshTokenTTL)
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm: %s", cfg.Algorithm)
+	}
+}
+
+// IssueTokenPair issues a new access and refresh token for the user
+func (m *TokenManager) IssueTokenPair(userID int64) (*TokenPair, error) {
+	access, err := m.issue(userID, TokenTypeAccess, m.accessTTL)
+	if err != nil {
+		return nil, err
+	}
+	refresh, err := m.issue(userID, TokenTypeRefresh, m.refreshTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(m.accessTTL.Seconds()),
+	}, nil
+}
+
+// Verify checks the signature, issuer, expiry and type of a token and returns its claims
+func (m *TokenManager) Verify(token string, typ TokenType) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidToken
+	}
+
+	// The algorithm is fixed by configuration; a token claiming another one is rejected
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil || h.Algorithm != m.algorithm {
+		return nil, ErrInvalidToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	if err := m.verifySignature(parts[0]+"."+parts[1], signature); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+	if claims.Issuer != m.issuer || claims.Type != typ {
+		return nil, ErrInvalidToken
+	}
+	if !m.now().Before(time.Unix(claims.ExpiresAt, 0)) {
+		return nil, ErrExpiredToken
+	}
+
+	return &claims, nil
+}
+
+// issue creates a signed token of the given type
+func (m *TokenManager) issue(userID int64, typ TokenType, ttl time.Duration) (string, error) {
+	now := m.now()
+	claims := Claims{
+		Issuer:    m.issuer,
+		Subject:   strconv.FormatInt(userID, 10),
+		Type:      typ,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(ttl).Unix(),
+	}
+
+	h, err := encodeSegment(header{Algorithm: m.algorithm, Type: "JWT"})
+	if err != nil {
+		return "", err
+	}
+	c, err := encodeSegment(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := h + "." + c
+	signature, err := m.sign(signingInput)
+	if err != nil {
+		return "", err
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// sign signs the token header and claims
+func (m *TokenManager) sign(signingInput string) ([]byte, error) {
+	switch m.algorithm {
+	case config.SigningHS256:
+		mac := hmac.New(sha256.New, m.secret)
+		mac.Write([]byte(signingInput))
+		return mac.Sum(nil), nil
+	default:
+		if m.privateKey == nil {
+			return nil, fmt.Errorf("no private key configured for signing")
+		}
+		digest := sha256.Sum256([]byte(signingInput))
+		return rsa.SignPKCS1v15(rand.Reader, m.privateKey, crypto.SHA256, digest[:])
+	}
+}
+
+// verifySignature checks the signature of the token header and claims
+func (m *TokenManager) verifySignature(signingInput string, signature []byte) error {
+	switch m.algorithm {
+	case config.SigningHS256:
+		mac := hmac.New(sha256.New, m.secret)
+		mac.Write([]byte(signingInput))
+		if !hmac.Equal(signature, mac.Sum(nil)) {
+			return ErrInvalidToken
+		}
+		return nil
+	default:
+		digest := sha256.Sum256([]byte(signingInput))
+		return rsa.VerifyPKCS1v15(m.publicKey, crypto.SHA256, digest[:], signature)
+	}
+}
+
+// encodeSegment encodes a token segment as base64url JSON
+func encodeSegment(v any) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("encode token segment: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// decodeSegment decodes a base64url JSON token segment
+func decodeSegment(segment string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// loadPrivateKey reads a PKCS#1 or PKCS#8 RSA private key from a PEM file
+func loadPrivateKey(path string) (*rsa.PrivateKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse private key %s: %w", path, err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key %s is not an RSA key", path)
+	}
+	return key, nil
+}
+
+// loadPublicKey reads a PKIX or PKCS#1 RSA public key from a PEM file
+func loadPublicKey(path string) (*rsa.PublicKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse public key %s: %w", path, err)
+	}
+	key, ok := parsed.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key %s is not an RSA key", path)
+	}
+	return key, nil
+}
+
+// readPEM reads the first PEM block of a file
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read key file: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data in %s", path)
+	}
+	return block, nil
+}
@@ -0,0 +1,168 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testIssuer = "go-health-tracker-test"
+
+func newTestRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return key
+}
+
+func TestTokenManager_RoundTrip(t *testing.T) {
+	hs, err := NewHS256TokenManager([]byte("test-secret"), testIssuer, 15*time.Minute, 24*time.Hour)
+	require.NoError(t, err)
+	rs, err := NewRS256TokenManager(newTestRSAKey(t), nil, testIssuer, 15*time.Minute, 24*time.Hour)
+	require.NoError(t, err)
+
+	for name, m := range map[string]*TokenManager{"HS256": hs, "RS256": rs} {
+		t.Run(name, func(t *testing.T) {
+			pair, err := m.IssueTokenPair(42)
+			require.NoError(t, err)
+			assert.Equal(t, "Bearer", pair.TokenType)
+			assert.Equal(t, int64(900), pair.ExpiresIn)
+
+			claims, err := m.Verify(pair.AccessToken, TokenTypeAccess)
+			require.NoError(t, err)
+			userID, err := claims.UserID()
+			require.NoError(t, err)
+			assert.Equal(t, int64(42), userID)
+
+			_, err = m.Verify(pair.RefreshToken, TokenTypeRefresh)
+			assert.NoError(t, err)
+
+			// A refresh token is not accepted as an access token and vice versa
+			_, err = m.Verify(pair.RefreshToken, TokenTypeAccess)
+			assert.ErrorIs(t, err, ErrInvalidToken)
+			_, err = m.Verify(pair.AccessToken, TokenTypeRefresh)
+			assert.ErrorIs(t, err, ErrInvalidToken)
+		})
+	}
+}
+
+func TestTokenManager_Verify(t *testing.T) {
+	m, err := NewHS256TokenManager([]byte("test-secret"), testIssuer, 15*time.Minute, 24*time.Hour)
+	require.NoError(t, err)
+	pair, err := m.IssueTokenPair(1)
+	require.NoError(t, err)
+	parts := strings.Split(pair.AccessToken, ".")
+
+	otherSecret, _ := NewHS256TokenManager([]byte("other-secret"), testIssuer, 15*time.Minute, 24*time.Hour)
+	otherPair, _ := otherSecret.IssueTokenPair(1)
+	otherIssuer, _ := NewHS256TokenManager([]byte("test-secret"), "someone-else", 15*time.Minute, 24*time.Hour)
+	otherIssuerPair, _ := otherIssuer.IssueTokenPair(1)
+
+	noneHeader := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+	tamperedClaims := base64.RawURLEncoding.EncodeToString([]byte(`{"iss":"` + testIssuer + `","sub":"2","typ":"access","iat":0,"exp":9999999999}`))
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{name: "valid", token: pair.AccessToken},
+		{name: "malformed", token: "not-a-token", wantErr: ErrInvalidToken},
+		{name: "empty", token: "", wantErr: ErrInvalidToken},
+		{name: "wrong secret", token: otherPair.AccessToken, wantErr: ErrInvalidToken},
+		{name: "wrong issuer", token: otherIssuerPair.AccessToken, wantErr: ErrInvalidToken},
+		{name: "tampered claims", token: parts[0] + "." + tamperedClaims + "." + parts[2], wantErr: ErrInvalidToken},
+		{name: "alg none", token: noneHeader + "." + parts[1] + ".", wantErr: ErrInvalidToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := m.Verify(tt.token, TokenTypeAccess)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTokenManager_Expired(t *testing.T) {
+	m, err := NewHS256TokenManager([]byte("test-secret"), testIssuer, 15*time.Minute, 24*time.Hour)
+	require.NoError(t, err)
+	issuedAt := time.Now()
+	m.now = func() time.Time { return issuedAt }
+	pair, err := m.IssueTokenPair(1)
+	require.NoError(t, err)
+
+	m.now = func() time.Time { return issuedAt.Add(16 * time.Minute) }
+	_, err = m.Verify(pair.AccessToken, TokenTypeAccess)
+	assert.ErrorIs(t, err, ErrExpiredToken)
+
+	// The refresh token lives longer
+	_, err = m.Verify(pair.RefreshToken, TokenTypeRefresh)
+	assert.NoError(t, err)
+}
+
+func TestTokenManager_AlgorithmConfusion(t *testing.T) {
+	key := newTestRSAKey(t)
+	rs, err := NewRS256TokenManager(nil, &key.PublicKey, testIssuer, 15*time.Minute, 24*time.Hour)
+	require.NoError(t, err)
+
+	// An HS256 token signed with the public key bytes must not pass RS256 verification
+	pubDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	hs, err := NewHS256TokenManager(pubDER, testIssuer, 15*time.Minute, 24*time.Hour)
+	require.NoError(t, err)
+	pair, err := hs.IssueTokenPair(1)
+	require.NoError(t, err)
+
+	_, err = rs.Verify(pair.AccessToken, TokenTypeAccess)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	// A verify-only manager cannot issue tokens
+	_, err = rs.IssueTokenPair(1)
+	assert.Error(t, err)
+}
+
+func TestNewTokenManager(t *testing.T) {
+	key := newTestRSAKey(t)
+	dir := t.TempDir()
+
+	privatePath := filepath.Join(dir, "private.pem")
+	privatePEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	require.NoError(t, os.WriteFile(privatePath, privatePEM, 0o600))
+
+	pubDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	publicPath := filepath.Join(dir, "public.pem")
+	require.NoError(t, os.WriteFile(publicPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}), 0o644))
+
+	signer, err := NewTokenManager(&config.AuthConfig{Algorithm: config.SigningRS256, PrivateKeyFile: privatePath, Issuer: testIssuer, AccessTokenTTL: time.Minute, RefreshTokenTTL: time.Hour})
+	require.NoError(t, err)
+	verifier, err := NewTokenManager(&config.AuthConfig{Algorithm: config.SigningRS256, PublicKeyFile: publicPath, Issuer: testIssuer, AccessTokenTTL: time.Minute, RefreshTokenTTL: time.Hour})
+	require.NoError(t, err)
+
+	pair, err := signer.IssueTokenPair(7)
+	require.NoError(t, err)
+	_, err = verifier.Verify(pair.AccessToken, TokenTypeAccess)
+	assert.NoError(t, err)
+
+	_, err = NewTokenManager(&config.AuthConfig{Algorithm: config.SigningHS256})
+	assert.Error(t, err, "HS256 without a secret")
+	_, err = NewTokenManager(&config.AuthConfig{Algorithm: "ES256", Secret: "secret"})
+	assert.Error(t, err, "unsupported algorithm")
+	_, err = NewTokenManager(&config.AuthConfig{Algorithm: config.SigningRS256, PrivateKeyFile: filepath.Join(dir, "missing.pem")})
+	assert.Error(t, err, "missing key file")
+}
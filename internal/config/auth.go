@@ -0,0 +1,58 @@
+package config
+
+import "time"
+
+// Supported token signing algorithms
+const (
+	SigningHS256 = "HS256"
+	SigningRS256 = "RS256"
+)
+
+// AuthConfig holds the configuration of the signed bearer tokens
+type AuthConfig struct {
+	// Algorithm is the signing algorithm, HS256 or RS256
+	Algorithm string
+
+	// HS256 shared secret
+	Secret string
+
+	// RS256 PEM key files. The public key is derived from the private key when not set.
+	PrivateKeyFile string
+	PublicKeyFile  string
+
+	Issuer          string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+}
+
+// AuthCfg is global authentication configuration instance
+var AuthCfg *AuthConfig
+
+// LoadAuthConfig loads authentication configuration from environment variables
+func LoadAuthConfig() *AuthConfig {
+	return &AuthConfig{
+		Algorithm:       getEnv("JWT_ALGORITHM", SigningHS256),
+		Secret:          getEnv("JWT_SECRET", ""),
+		PrivateKeyFile:  getEnv("JWT_PRIVATE_KEY_FILE", ""),
+		PublicKeyFile:   getEnv("JWT_PUBLIC_KEY_FILE", ""),
+		Issuer:          getEnv("JWT_ISSUER", "go-health-tracker"),
+		AccessTokenTTL:  time.Duration(getEnvAsInt("JWT_ACCESS_TTL_MINUTES", 15)) * time.Minute,
+		RefreshTokenTTL: time.Duration(getEnvAsInt("JWT_REFRESH_TTL_HOURS", 720)) * time.Hour,
+	}
+}
+
+// Enabled returns true if a signing key is configured.
+// Without one, requests are attributed to the default user.
+func (c *AuthConfig) Enabled() bool {
+	switch c.Algorithm {
+	case SigningRS256:
+		return c.PrivateKeyFile != "" || c.PublicKeyFile != ""
+	default:
+		return c.Secret != ""
+	}
+}
+
+// init function to initialize authentication configuration
+func init() {
+	AuthCfg = LoadAuthConfig()
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadAuthConfig(t *testing.T) {
+	t.Run("default values", func(t *testing.T) {
+		for _, key := range []string{"JWT_ALGORITHM", "JWT_SECRET", "JWT_PRIVATE_KEY_FILE", "JWT_PUBLIC_KEY_FILE", "JWT_ISSUER", "JWT_ACCESS_TTL_MINUTES", "JWT_REFRESH_TTL_HOURS"} {
+			t.Setenv(key, "") // restores the original value after the test
+			os.Unsetenv(key)
+		}
+
+		cfg := LoadAuthConfig()
+		assert.Equal(t, SigningHS256, cfg.Algorithm)
+		assert.Equal(t, "go-health-tracker", cfg.Issuer)
+		assert.Equal(t, 15*time.Minute, cfg.AccessTokenTTL)
+		assert.Equal(t, 720*time.Hour, cfg.RefreshTokenTTL)
+	})
+
+	t.Run("custom values", func(t *testing.T) {
+		t.Setenv("JWT_ALGORITHM", SigningRS256)
+		t.Setenv("JWT_PRIVATE_KEY_FILE", "/keys/private.pem")
+		t.Setenv("JWT_ACCESS_TTL_MINUTES", "5")
+		t.Setenv("JWT_REFRESH_TTL_HOURS", "24")
+
+		cfg := LoadAuthConfig()
+		assert.Equal(t, SigningRS256, cfg.Algorithm)
+		assert.Equal(t, "/keys/private.pem", cfg.PrivateKeyFile)
+		assert.Equal(t, 5*time.Minute, cfg.AccessTokenTTL)
+		assert.Equal(t, 24*time.Hour, cfg.RefreshTokenTTL)
+	})
+}
+
+func TestAuthConfig_Enabled(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  AuthConfig
+		want bool
+	}{
+		{"no key", AuthConfig{Algorithm: SigningHS256}, false},
+		{"HS256 secret", AuthConfig{Algorithm: SigningHS256, Secret: "secret"}, true},
+		{"RS256 without key files", AuthConfig{Algorithm: SigningRS256, Secret: "secret"}, false},
+		{"RS256 private key", AuthConfig{Algorithm: SigningRS256, PrivateKeyFile: "private.pem"}, true},
+		{"RS256 public key only", AuthConfig{Algorithm: SigningRS256, PublicKeyFile: "public.pem"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.cfg.Enabled())
+		})
+	}
+}
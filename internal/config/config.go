@@ -11,6 +11,18 @@ var IsDevelopment bool
 // RequestTimeoutSecond is the default timeout for HTTP requests
 var RequestTimeoutSecond = 30
 
+// ImportTimeoutSecond is the timeout for uploads of large import files
+var ImportTimeoutSecond = 600
+
+// RequireIfMatch makes writes to health records require an If-Match precondition
+var RequireIfMatch = false
+
+// IdempotencyKeyTTLHours is how long the response of a request sent with an Idempotency-Key is kept for retries
+var IdempotencyKeyTTLHours = 24
+
+// DefaultUserEmail is the account requests are attributed to until authentication is configured
+var DefaultUserEmail = "default@localhost"
+
 // IsDev returns true if the application is running in development mode
 func IsDev() bool {
 	return os.Getenv("ENV") == "development"
@@ -25,6 +37,28 @@ func ReloadConfig() {
 			RequestTimeoutSecond = val
 		}
 	}
+
+	if timeout := os.Getenv("IMPORT_TIMEOUT_SECONDS"); timeout != "" {
+		if val, err := strconv.Atoi(timeout); err == nil {
+			ImportTimeoutSecond = val
+		}
+	}
+
+	if require := os.Getenv("REQUIRE_IF_MATCH"); require != "" {
+		if val, err := strconv.ParseBool(require); err == nil {
+			RequireIfMatch = val
+		}
+	}
+
+	if ttl := os.Getenv("IDEMPOTENCY_KEY_TTL_HOURS"); ttl != "" {
+		if val, err := strconv.Atoi(ttl); err == nil && val > 0 {
+			IdempotencyKeyTTLHours = val
+		}
+	}
+
+	if email := os.Getenv("DEFAULT_USER_EMAIL"); email != "" {
+		DefaultUserEmail = email
+	}
 }
 
 // init initializes the configuration
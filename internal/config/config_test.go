@@ -84,3 +84,159 @@ func TestRequestTimeoutSecond(t *testing.T) {
 		})
 	}
 }
+
+func TestImportTimeoutSecond(t *testing.T) {
+	orgTimeout, timeoutExists := os.LookupEnv("IMPORT_TIMEOUT_SECONDS")
+
+	defer func() {
+		if timeoutExists {
+			os.Setenv("IMPORT_TIMEOUT_SECONDS", orgTimeout)
+		} else {
+			os.Unsetenv("IMPORT_TIMEOUT_SECONDS")
+		}
+	}()
+
+	tests := []struct {
+		name    string
+		timeout string
+		want    int
+	}{
+		{"with timeout specified", "1800", 1800},
+		{"invalid value", "invalid", 600}, // back to default value
+		{"unset", "", 600},                // default value
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.timeout == "" {
+				os.Unsetenv("IMPORT_TIMEOUT_SECONDS")
+			} else {
+				os.Setenv("IMPORT_TIMEOUT_SECONDS", tt.timeout)
+			}
+
+			ImportTimeoutSecond = 600
+			ReloadConfig()
+
+			if ImportTimeoutSecond != tt.want {
+				t.Errorf("ImportTimeoutSecond = %v, want %v", ImportTimeoutSecond, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequireIfMatch(t *testing.T) {
+	orgRequire, requireExists := os.LookupEnv("REQUIRE_IF_MATCH")
+
+	defer func() {
+		if requireExists {
+			os.Setenv("REQUIRE_IF_MATCH", orgRequire)
+		} else {
+			os.Unsetenv("REQUIRE_IF_MATCH")
+		}
+	}()
+
+	tests := []struct {
+		name    string
+		require string
+		want    bool
+	}{
+		{"required", "true", true},
+		{"invalid value", "invalid", false}, // back to default value
+		{"unset", "", false},                // default value
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.require == "" {
+				os.Unsetenv("REQUIRE_IF_MATCH")
+			} else {
+				os.Setenv("REQUIRE_IF_MATCH", tt.require)
+			}
+
+			RequireIfMatch = false
+			ReloadConfig()
+
+			if RequireIfMatch != tt.want {
+				t.Errorf("RequireIfMatch = %v, want %v", RequireIfMatch, tt.want)
+			}
+		})
+	}
+}
+
+func TestIdempotencyKeyTTLHours(t *testing.T) {
+	orgTTL, ttlExists := os.LookupEnv("IDEMPOTENCY_KEY_TTL_HOURS")
+
+	defer func() {
+		if ttlExists {
+			os.Setenv("IDEMPOTENCY_KEY_TTL_HOURS", orgTTL)
+		} else {
+			os.Unsetenv("IDEMPOTENCY_KEY_TTL_HOURS")
+		}
+	}()
+
+	tests := []struct {
+		name string
+		ttl  string
+		want int
+	}{
+		{"with ttl specified", "48", 48},
+		{"invalid value", "invalid", 24}, // back to default value
+		{"not positive", "0", 24},        // back to default value
+		{"unset", "", 24},                // default value
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.ttl == "" {
+				os.Unsetenv("IDEMPOTENCY_KEY_TTL_HOURS")
+			} else {
+				os.Setenv("IDEMPOTENCY_KEY_TTL_HOURS", tt.ttl)
+			}
+
+			IdempotencyKeyTTLHours = 24
+			ReloadConfig()
+
+			if IdempotencyKeyTTLHours != tt.want {
+				t.Errorf("IdempotencyKeyTTLHours = %v, want %v", IdempotencyKeyTTLHours, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultUserEmail(t *testing.T) {
+	orgEmail, emailExists := os.LookupEnv("DEFAULT_USER_EMAIL")
+
+	defer func() {
+		if emailExists {
+			os.Setenv("DEFAULT_USER_EMAIL", orgEmail)
+		} else {
+			os.Unsetenv("DEFAULT_USER_EMAIL")
+		}
+	}()
+
+	tests := []struct {
+		name  string
+		email string
+		want  string
+	}{
+		{"with email specified", "me@example.com", "me@example.com"},
+		{"unset", "", "default@localhost"}, // default value
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.email == "" {
+				os.Unsetenv("DEFAULT_USER_EMAIL")
+			} else {
+				os.Setenv("DEFAULT_USER_EMAIL", tt.email)
+			}
+
+			DefaultUserEmail = "default@localhost"
+			ReloadConfig()
+
+			if DefaultUserEmail != tt.want {
+				t.Errorf("DefaultUserEmail = %v, want %v", DefaultUserEmail, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"log/slog"
+	"time"
+)
+
+// LogConfig holds the configuration of the structured logger
+type LogConfig struct {
+	// Level is the minimum level written: debug, info, warn or error
+	Level slog.Level
+
+	// SlowQueryThreshold is the duration from which a database call is logged as slow
+	SlowQueryThreshold time.Duration
+}
+
+// LogCfg is global logging configuration instance
+var LogCfg *LogConfig
+
+// LoadLogConfig loads logging configuration from environment variables.
+// An unknown LOG_LEVEL falls back to info.
+func LoadLogConfig() *LogConfig {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(getEnv("LOG_LEVEL", "info"))); err != nil {
+		level = slog.LevelInfo
+	}
+
+	return &LogConfig{
+		Level:              level,
+		SlowQueryThreshold: time.Duration(getEnvAsInt("SLOW_QUERY_THRESHOLD_MS", 200)) * time.Millisecond,
+	}
+}
+
+// init function to initialize logging configuration
+func init() {
+	LogCfg = LoadLogConfig()
+}
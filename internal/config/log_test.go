@@ -0,0 +1,38 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadLogConfig(t *testing.T) {
+	t.Run("default values", func(t *testing.T) {
+		for _, key := range []string{"LOG_LEVEL", "SLOW_QUERY_THRESHOLD_MS"} {
+			t.Setenv(key, "") // restores the original value after the test
+			os.Unsetenv(key)
+		}
+
+		cfg := LoadLogConfig()
+		assert.Equal(t, slog.LevelInfo, cfg.Level)
+		assert.Equal(t, 200*time.Millisecond, cfg.SlowQueryThreshold)
+	})
+
+	t.Run("custom values", func(t *testing.T) {
+		t.Setenv("LOG_LEVEL", "DEBUG")
+		t.Setenv("SLOW_QUERY_THRESHOLD_MS", "50")
+
+		cfg := LoadLogConfig()
+		assert.Equal(t, slog.LevelDebug, cfg.Level)
+		assert.Equal(t, 50*time.Millisecond, cfg.SlowQueryThreshold)
+	})
+
+	t.Run("unknown level", func(t *testing.T) {
+		t.Setenv("LOG_LEVEL", "verbose")
+
+		assert.Equal(t, slog.LevelInfo, LoadLogConfig().Level)
+	})
+}
@@ -0,0 +1,48 @@
+package config
+
+import "time"
+
+// ServerConfig holds the configuration of the HTTP server
+type ServerConfig struct {
+	Port string
+
+	// MetricsPort serves /metrics on a listener of its own when set, so it can be kept off the public network.
+	// When empty, /metrics is served on Port without authentication.
+	MetricsPort string
+
+	// Timeouts of a single connection, see http.Server
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+
+	// ShutdownTimeout is how long in-flight requests may take to finish after SIGINT or SIGTERM
+	ShutdownTimeout time.Duration
+}
+
+// ServerCfg is global HTTP server configuration instance
+var ServerCfg *ServerConfig
+
+// LoadServerConfig loads HTTP server configuration from environment variables.
+// The write timeout has to leave room for RequestTimeoutSecond; imports extend their own deadlines.
+func LoadServerConfig() *ServerConfig {
+	port := getEnv("PORT", "")
+	if port == "" {
+		port = "8000"
+	}
+
+	return &ServerConfig{
+		Port:              port,
+		MetricsPort:       getEnv("METRICS_PORT", ""),
+		ReadHeaderTimeout: time.Duration(getEnvAsInt("SERVER_READ_HEADER_TIMEOUT_SECONDS", 10)) * time.Second,
+		ReadTimeout:       time.Duration(getEnvAsInt("SERVER_READ_TIMEOUT_SECONDS", 60)) * time.Second,
+		WriteTimeout:      time.Duration(getEnvAsInt("SERVER_WRITE_TIMEOUT_SECONDS", 60)) * time.Second,
+		IdleTimeout:       time.Duration(getEnvAsInt("SERVER_IDLE_TIMEOUT_SECONDS", 120)) * time.Second,
+		ShutdownTimeout:   time.Duration(getEnvAsInt("SERVER_SHUTDOWN_TIMEOUT_SECONDS", 25)) * time.Second,
+	}
+}
+
+// init function to initialize HTTP server configuration
+func init() {
+	ServerCfg = LoadServerConfig()
+}
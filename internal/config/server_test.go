@@ -0,0 +1,46 @@
+package config
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadServerConfig(t *testing.T) {
+	t.Run("default values", func(t *testing.T) {
+		for _, key := range []string{"PORT", "SERVER_READ_HEADER_TIMEOUT_SECONDS", "SERVER_READ_TIMEOUT_SECONDS", "SERVER_WRITE_TIMEOUT_SECONDS", "SERVER_IDLE_TIMEOUT_SECONDS", "SERVER_SHUTDOWN_TIMEOUT_SECONDS", "METRICS_PORT"} {
+			t.Setenv(key, "") // restores the original value after the test
+			os.Unsetenv(key)
+		}
+
+		cfg := LoadServerConfig()
+		assert.Equal(t, "8000", cfg.Port)
+		assert.Empty(t, cfg.MetricsPort)
+		assert.Equal(t, 10*time.Second, cfg.ReadHeaderTimeout)
+		assert.Equal(t, 60*time.Second, cfg.ReadTimeout)
+		assert.Equal(t, 60*time.Second, cfg.WriteTimeout)
+		assert.Equal(t, 120*time.Second, cfg.IdleTimeout)
+		assert.Equal(t, 25*time.Second, cfg.ShutdownTimeout)
+	})
+
+	t.Run("custom values", func(t *testing.T) {
+		t.Setenv("PORT", "9000")
+		t.Setenv("METRICS_PORT", "9100")
+		t.Setenv("SERVER_WRITE_TIMEOUT_SECONDS", "90")
+		t.Setenv("SERVER_SHUTDOWN_TIMEOUT_SECONDS", "5")
+
+		cfg := LoadServerConfig()
+		assert.Equal(t, "9000", cfg.Port)
+		assert.Equal(t, "9100", cfg.MetricsPort)
+		assert.Equal(t, 90*time.Second, cfg.WriteTimeout)
+		assert.Equal(t, 5*time.Second, cfg.ShutdownTimeout)
+	})
+
+	t.Run("empty port", func(t *testing.T) {
+		t.Setenv("PORT", "")
+
+		assert.Equal(t, "8000", LoadServerConfig().Port)
+	})
+}
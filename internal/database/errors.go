@@ -0,0 +1,39 @@
+package database
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mattn/go-sqlite3"
+)
+
+// Errors returned identically by every backend, so callers can tell
+// a missing or duplicate record apart from a failing database
+var (
+	// ErrNotFound is returned when the record to update or delete does not exist
+	ErrNotFound = errors.New("record not found")
+	// ErrConflict is returned when a write would duplicate an existing record or overlap a sleep session
+	ErrConflict = errors.New("record already exists")
+	// ErrVersionMismatch is returned when a conditional write finds the record changed since it was read
+	ErrVersionMismatch = errors.New("record version does not match")
+	// ErrPoolExhausted is returned by health checks while every connection of the pool is in use
+	ErrPoolExhausted = errors.New("connection pool exhausted")
+)
+
+// pgUniqueViolation is the PostgreSQL SQLSTATE of a unique constraint violation
+const pgUniqueViolation = "23505"
+
+// isSQLiteUniqueViolation reports whether err is a SQLite unique or primary key constraint violation
+func isSQLiteUniqueViolation(err error) bool {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	return sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique || sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey
+}
+
+// isPostgresUniqueViolation reports whether err is a PostgreSQL unique constraint violation
+func isPostgresUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation
+}
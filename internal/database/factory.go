@@ -29,6 +29,29 @@ func NewDatabase() (DBInterface, error) {
 	}
 }
 
+// NewMigrator connects to the configured database without applying migrations,
+// so that they can be applied, reverted and listed explicitly
+func NewMigrator() (Migrator, error) {
+	dbConfig := config.DBConfig
+	if dbConfig == nil {
+		return nil, fmt.Errorf("database configuration is not initialized")
+	}
+
+	connectionString := dbConfig.GetConnectionString()
+	if connectionString == "" {
+		return nil, fmt.Errorf("database connection string is empty")
+	}
+
+	switch dbConfig.Type {
+	case config.DatabasePostgreSQL:
+		return OpenPostgresDB(connectionString)
+	case config.DatabaseSQLite:
+		return OpenSQLiteDB(connectionString)
+	default:
+		return nil, fmt.Errorf("unsupported database type: %s", dbConfig.Type)
+	}
+}
+
 // NewDatabaseWithConfig creates a database instance with explicit configuration
 // This functions is useful for testing or when you need to override the global config
 func NewDatabaseWithConfig(dbConfig *config.DatabaseConfig) (DBInterface, error) {
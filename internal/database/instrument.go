@@ -0,0 +1,280 @@
+package database
+
+import (
+	"context"
+	"iter"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/models"
+)
+
+// QueryObserver is called after every DBInterface call with the name of the method
+// and how long it took. The context is the one the method was called with.
+type QueryObserver func(ctx context.Context, method string, d time.Duration)
+
+// Instrument wraps a database so that every DBInterface call is reported to observe.
+// Optional interfaces such as HealthChecker are not forwarded; keep the unwrapped database for those.
+func Instrument(db DBInterface, observe QueryObserver) DBInterface {
+	return &instrumentedDB{DBInterface: db, observer: observe}
+}
+
+// instrumentedDB times the calls to the wrapped DBInterface
+type instrumentedDB struct {
+	DBInterface
+	observer QueryObserver
+}
+
+// observe reports a call that started at start; it is deferred at the top of every method
+func (db *instrumentedDB) observe(ctx context.Context, method string, start time.Time) {
+	db.observer(ctx, method, time.Since(start))
+}
+
+func (db *instrumentedDB) CreateHealthRecord(ctx context.Context, userID int64, hr *models.HealthRecord) (*models.HealthRecord, error) {
+	defer db.observe(ctx, "CreateHealthRecord", time.Now())
+	return db.DBInterface.CreateHealthRecord(ctx, userID, hr)
+}
+
+func (db *instrumentedDB) ReadHealthRecord(ctx context.Context, userID int64, date time.Time) (*models.HealthRecord, error) {
+	defer db.observe(ctx, "ReadHealthRecord", time.Now())
+	return db.DBInterface.ReadHealthRecord(ctx, userID, date)
+}
+
+func (db *instrumentedDB) ReadHealthRecordsByYear(ctx context.Context, userID int64, year int) ([]models.HealthRecord, error) {
+	defer db.observe(ctx, "ReadHealthRecordsByYear", time.Now())
+	return db.DBInterface.ReadHealthRecordsByYear(ctx, userID, year)
+}
+
+func (db *instrumentedDB) ReadHealthRecordsByYearMonth(ctx context.Context, userID int64, year, month int) ([]models.HealthRecord, error) {
+	defer db.observe(ctx, "ReadHealthRecordsByYearMonth", time.Now())
+	return db.DBInterface.ReadHealthRecordsByYearMonth(ctx, userID, year, month)
+}
+
+func (db *instrumentedDB) ReadHealthRecordsByRange(ctx context.Context, userID int64, startDate, endDate time.Time) ([]models.HealthRecord, error) {
+	defer db.observe(ctx, "ReadHealthRecordsByRange", time.Now())
+	return db.DBInterface.ReadHealthRecordsByRange(ctx, userID, startDate, endDate)
+}
+
+func (db *instrumentedDB) ReadHealthRecordsPage(ctx context.Context, userID int64, startDate, endDate time.Time, page models.PageRequest) (*models.HealthRecordPage, error) {
+	defer db.observe(ctx, "ReadHealthRecordsPage", time.Now())
+	return db.DBInterface.ReadHealthRecordsPage(ctx, userID, startDate, endDate, page)
+}
+
+func (db *instrumentedDB) ReadStepCountStats(ctx context.Context, userID int64, startDate, endDate time.Time, groupBy models.StatsGrouping) ([]models.StepCountStats, error) {
+	defer db.observe(ctx, "ReadStepCountStats", time.Now())
+	return db.DBInterface.ReadStepCountStats(ctx, userID, startDate, endDate, groupBy)
+}
+
+func (db *instrumentedDB) UpdateHealthRecord(ctx context.Context, userID int64, hr *models.HealthRecord) error {
+	defer db.observe(ctx, "UpdateHealthRecord", time.Now())
+	return db.DBInterface.UpdateHealthRecord(ctx, userID, hr)
+}
+
+func (db *instrumentedDB) UpsertHealthRecord(ctx context.Context, userID int64, hr *models.HealthRecord) (stored *models.HealthRecord, created bool, err error) {
+	defer db.observe(ctx, "UpsertHealthRecord", time.Now())
+	return db.DBInterface.UpsertHealthRecord(ctx, userID, hr)
+}
+
+func (db *instrumentedDB) UpsertHealthRecords(ctx context.Context, userID int64, records []models.HealthRecord) ([]models.HealthRecordUpsert, error) {
+	defer db.observe(ctx, "UpsertHealthRecords", time.Now())
+	return db.DBInterface.UpsertHealthRecords(ctx, userID, records)
+}
+
+func (db *instrumentedDB) ImportHealthRecords(ctx context.Context, userID int64, records iter.Seq2[models.HealthRecord, error], opts models.ImportOptions) ([]models.HealthRecordImport, error) {
+	defer db.observe(ctx, "ImportHealthRecords", time.Now())
+	return db.DBInterface.ImportHealthRecords(ctx, userID, records, opts)
+}
+
+func (db *instrumentedDB) DeleteHealthRecord(ctx context.Context, userID int64, date time.Time, version int64) error {
+	defer db.observe(ctx, "DeleteHealthRecord", time.Now())
+	return db.DBInterface.DeleteHealthRecord(ctx, userID, date, version)
+}
+
+func (db *instrumentedDB) CreateUser(ctx context.Context, u *models.User) (*models.User, error) {
+	defer db.observe(ctx, "CreateUser", time.Now())
+	return db.DBInterface.CreateUser(ctx, u)
+}
+
+func (db *instrumentedDB) ReadUser(ctx context.Context, id int64) (*models.User, error) {
+	defer db.observe(ctx, "ReadUser", time.Now())
+	return db.DBInterface.ReadUser(ctx, id)
+}
+
+func (db *instrumentedDB) ReadUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	defer db.observe(ctx, "ReadUserByEmail", time.Now())
+	return db.DBInterface.ReadUserByEmail(ctx, email)
+}
+
+func (db *instrumentedDB) CreateAPIKey(ctx context.Context, userID int64, k *models.APIKey) (*models.APIKey, error) {
+	defer db.observe(ctx, "CreateAPIKey", time.Now())
+	return db.DBInterface.CreateAPIKey(ctx, userID, k)
+}
+
+func (db *instrumentedDB) ReadAPIKeys(ctx context.Context, userID int64) ([]models.APIKey, error) {
+	defer db.observe(ctx, "ReadAPIKeys", time.Now())
+	return db.DBInterface.ReadAPIKeys(ctx, userID)
+}
+
+func (db *instrumentedDB) ReadAPIKeyByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	defer db.observe(ctx, "ReadAPIKeyByHash", time.Now())
+	return db.DBInterface.ReadAPIKeyByHash(ctx, keyHash)
+}
+
+func (db *instrumentedDB) UpdateAPIKeyName(ctx context.Context, userID int64, id int64, name string) error {
+	defer db.observe(ctx, "UpdateAPIKeyName", time.Now())
+	return db.DBInterface.UpdateAPIKeyName(ctx, userID, id, name)
+}
+
+func (db *instrumentedDB) RevokeAPIKey(ctx context.Context, userID int64, id int64) error {
+	defer db.observe(ctx, "RevokeAPIKey", time.Now())
+	return db.DBInterface.RevokeAPIKey(ctx, userID, id)
+}
+
+func (db *instrumentedDB) TouchAPIKey(ctx context.Context, id int64, usedAt time.Time) error {
+	defer db.observe(ctx, "TouchAPIKey", time.Now())
+	return db.DBInterface.TouchAPIKey(ctx, id, usedAt)
+}
+
+func (db *instrumentedDB) UpsertStepGoal(ctx context.Context, userID int64, g *models.StepGoal) (*models.StepGoal, error) {
+	defer db.observe(ctx, "UpsertStepGoal", time.Now())
+	return db.DBInterface.UpsertStepGoal(ctx, userID, g)
+}
+
+func (db *instrumentedDB) ReadStepGoals(ctx context.Context, userID int64) ([]models.StepGoal, error) {
+	defer db.observe(ctx, "ReadStepGoals", time.Now())
+	return db.DBInterface.ReadStepGoals(ctx, userID)
+}
+
+func (db *instrumentedDB) DeleteStepGoal(ctx context.Context, userID int64, effectiveFrom time.Time) error {
+	defer db.observe(ctx, "DeleteStepGoal", time.Now())
+	return db.DBInterface.DeleteStepGoal(ctx, userID, effectiveFrom)
+}
+
+func (db *instrumentedDB) CreateSleepSession(ctx context.Context, userID int64, s *models.SleepSession) (*models.SleepSession, error) {
+	defer db.observe(ctx, "CreateSleepSession", time.Now())
+	return db.DBInterface.CreateSleepSession(ctx, userID, s)
+}
+
+func (db *instrumentedDB) ReadSleepSession(ctx context.Context, userID int64, id int64) (*models.SleepSession, error) {
+	defer db.observe(ctx, "ReadSleepSession", time.Now())
+	return db.DBInterface.ReadSleepSession(ctx, userID, id)
+}
+
+func (db *instrumentedDB) ReadSleepSessionsByRange(ctx context.Context, userID int64, startDate, endDate time.Time) ([]models.SleepSession, error) {
+	defer db.observe(ctx, "ReadSleepSessionsByRange", time.Now())
+	return db.DBInterface.ReadSleepSessionsByRange(ctx, userID, startDate, endDate)
+}
+
+func (db *instrumentedDB) ReadOverlappingSleepSessions(ctx context.Context, userID int64, bedTime, wakeTime time.Time) ([]models.SleepSession, error) {
+	defer db.observe(ctx, "ReadOverlappingSleepSessions", time.Now())
+	return db.DBInterface.ReadOverlappingSleepSessions(ctx, userID, bedTime, wakeTime)
+}
+
+func (db *instrumentedDB) UpdateSleepSession(ctx context.Context, userID int64, s *models.SleepSession) error {
+	defer db.observe(ctx, "UpdateSleepSession", time.Now())
+	return db.DBInterface.UpdateSleepSession(ctx, userID, s)
+}
+
+func (db *instrumentedDB) DeleteSleepSession(ctx context.Context, userID int64, id int64) error {
+	defer db.observe(ctx, "DeleteSleepSession", time.Now())
+	return db.DBInterface.DeleteSleepSession(ctx, userID, id)
+}
+
+func (db *instrumentedDB) CreateWeightReading(ctx context.Context, userID int64, wr *models.WeightReading) (*models.WeightReading, error) {
+	defer db.observe(ctx, "CreateWeightReading", time.Now())
+	return db.DBInterface.CreateWeightReading(ctx, userID, wr)
+}
+
+func (db *instrumentedDB) ReadWeightReadingsByRange(ctx context.Context, userID int64, startDate, endDate time.Time) ([]models.WeightReading, error) {
+	defer db.observe(ctx, "ReadWeightReadingsByRange", time.Now())
+	return db.DBInterface.ReadWeightReadingsByRange(ctx, userID, startDate, endDate)
+}
+
+func (db *instrumentedDB) DeleteWeightReading(ctx context.Context, userID int64, id int64) error {
+	defer db.observe(ctx, "DeleteWeightReading", time.Now())
+	return db.DBInterface.DeleteWeightReading(ctx, userID, id)
+}
+
+func (db *instrumentedDB) ReadHeightProfile(ctx context.Context, userID int64) (*models.HeightProfile, error) {
+	defer db.observe(ctx, "ReadHeightProfile", time.Now())
+	return db.DBInterface.ReadHeightProfile(ctx, userID)
+}
+
+func (db *instrumentedDB) UpsertHeightProfile(ctx context.Context, userID int64, hp *models.HeightProfile) (*models.HeightProfile, error) {
+	defer db.observe(ctx, "UpsertHeightProfile", time.Now())
+	return db.DBInterface.UpsertHeightProfile(ctx, userID, hp)
+}
+
+func (db *instrumentedDB) CreateBloodPressureReading(ctx context.Context, userID int64, bp *models.BloodPressureReading) (*models.BloodPressureReading, error) {
+	defer db.observe(ctx, "CreateBloodPressureReading", time.Now())
+	return db.DBInterface.CreateBloodPressureReading(ctx, userID, bp)
+}
+
+func (db *instrumentedDB) ReadBloodPressureReadingsByRange(ctx context.Context, userID int64, startDate, endDate time.Time) ([]models.BloodPressureReading, error) {
+	defer db.observe(ctx, "ReadBloodPressureReadingsByRange", time.Now())
+	return db.DBInterface.ReadBloodPressureReadingsByRange(ctx, userID, startDate, endDate)
+}
+
+func (db *instrumentedDB) CreateHeartRateReading(ctx context.Context, userID int64, hr *models.HeartRateReading) (*models.HeartRateReading, error) {
+	defer db.observe(ctx, "CreateHeartRateReading", time.Now())
+	return db.DBInterface.CreateHeartRateReading(ctx, userID, hr)
+}
+
+func (db *instrumentedDB) ReadHeartRateReadingsByRange(ctx context.Context, userID int64, startDate, endDate time.Time) ([]models.HeartRateReading, error) {
+	defer db.observe(ctx, "ReadHeartRateReadingsByRange", time.Now())
+	return db.DBInterface.ReadHeartRateReadingsByRange(ctx, userID, startDate, endDate)
+}
+
+func (db *instrumentedDB) CreateMetricDefinition(ctx context.Context, userID int64, def *models.MetricDefinition) (*models.MetricDefinition, error) {
+	defer db.observe(ctx, "CreateMetricDefinition", time.Now())
+	return db.DBInterface.CreateMetricDefinition(ctx, userID, def)
+}
+
+func (db *instrumentedDB) ReadMetricDefinition(ctx context.Context, userID int64, name string) (*models.MetricDefinition, error) {
+	defer db.observe(ctx, "ReadMetricDefinition", time.Now())
+	return db.DBInterface.ReadMetricDefinition(ctx, userID, name)
+}
+
+func (db *instrumentedDB) ReadMetricDefinitions(ctx context.Context, userID int64) ([]models.MetricDefinition, error) {
+	defer db.observe(ctx, "ReadMetricDefinitions", time.Now())
+	return db.DBInterface.ReadMetricDefinitions(ctx, userID)
+}
+
+func (db *instrumentedDB) DeleteMetricDefinition(ctx context.Context, userID int64, id int64) error {
+	defer db.observe(ctx, "DeleteMetricDefinition", time.Now())
+	return db.DBInterface.DeleteMetricDefinition(ctx, userID, id)
+}
+
+func (db *instrumentedDB) CreateMetricValue(ctx context.Context, userID int64, mv *models.MetricValue) (*models.MetricValue, error) {
+	defer db.observe(ctx, "CreateMetricValue", time.Now())
+	return db.DBInterface.CreateMetricValue(ctx, userID, mv)
+}
+
+func (db *instrumentedDB) ReadMetricValuesByRange(ctx context.Context, userID int64, metricID int64, startDate, endDate time.Time) ([]models.MetricValue, error) {
+	defer db.observe(ctx, "ReadMetricValuesByRange", time.Now())
+	return db.DBInterface.ReadMetricValuesByRange(ctx, userID, metricID, startDate, endDate)
+}
+
+func (db *instrumentedDB) ReserveIdempotencyKey(ctx context.Context, userID int64, k *models.IdempotencyKey) error {
+	defer db.observe(ctx, "ReserveIdempotencyKey", time.Now())
+	return db.DBInterface.ReserveIdempotencyKey(ctx, userID, k)
+}
+
+func (db *instrumentedDB) ReadIdempotencyKey(ctx context.Context, userID int64, key string) (*models.IdempotencyKey, error) {
+	defer db.observe(ctx, "ReadIdempotencyKey", time.Now())
+	return db.DBInterface.ReadIdempotencyKey(ctx, userID, key)
+}
+
+func (db *instrumentedDB) CompleteIdempotencyKey(ctx context.Context, userID int64, k *models.IdempotencyKey) error {
+	defer db.observe(ctx, "CompleteIdempotencyKey", time.Now())
+	return db.DBInterface.CompleteIdempotencyKey(ctx, userID, k)
+}
+
+func (db *instrumentedDB) DeleteIdempotencyKey(ctx context.Context, userID int64, key string) error {
+	defer db.observe(ctx, "DeleteIdempotencyKey", time.Now())
+	return db.DBInterface.DeleteIdempotencyKey(ctx, userID, key)
+}
+
+func (db *instrumentedDB) PurgeIdempotencyKeys(ctx context.Context, now time.Time) (int64, error) {
+	defer db.observe(ctx, "PurgeIdempotencyKeys", time.Now())
+	return db.DBInterface.PurgeIdempotencyKeys(ctx, now)
+}
@@ -0,0 +1,42 @@
+package database_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/database"
+	"github.com/nnamm/go-health-tracker/testutils"
+)
+
+type ctxKey struct{}
+
+func TestInstrument(t *testing.T) {
+	testDB, cleanup := testutils.SetupSQLiteTester(t)
+	defer cleanup()
+
+	var methods []string
+	var gotValue any
+	db := database.Instrument(testDB, func(ctx context.Context, method string, d time.Duration) {
+		methods = append(methods, method)
+		gotValue = ctx.Value(ctxKey{})
+		if d < 0 {
+			t.Errorf("observed duration = %v, want >= 0", d)
+		}
+	})
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "request")
+	if _, err := db.ReadHealthRecord(ctx, testutils.TestUserID, testutils.CreateDate("2024-01-01")); err != nil {
+		t.Fatalf("ReadHealthRecord() error = %v", err)
+	}
+	if _, err := db.ReadUser(ctx, testutils.TestUserID); err != nil {
+		t.Fatalf("ReadUser() error = %v", err)
+	}
+
+	if len(methods) != 2 || methods[0] != "ReadHealthRecord" || methods[1] != "ReadUser" {
+		t.Errorf("observed methods = %v, want [ReadHealthRecord ReadUser]", methods)
+	}
+	if gotValue != "request" {
+		t.Errorf("observed context value = %v, want the caller's context", gotValue)
+	}
+}
@@ -4,17 +4,129 @@ package database
 
 import (
 	"context"
+	"iter"
 	"time"
 
 	"github.com/nnamm/go-health-tracker/internal/models"
 )
 
+// DBInterface is the storage used by the handlers.
+// Every method except those of UserStore and the API key lookups used for authentication
+// is scoped to the user identified by userID.
 type DBInterface interface {
-	CreateHealthRecord(ctx context.Context, hr *models.HealthRecord) (*models.HealthRecord, error)
-	ReadHealthRecord(ctx context.Context, date time.Time) (*models.HealthRecord, error)
-	ReadHealthRecordsByYear(ctx context.Context, year int) ([]models.HealthRecord, error)
-	ReadHealthRecordsByYearMonth(ctx context.Context, year, month int) ([]models.HealthRecord, error)
-	UpdateHealthRecord(ctx context.Context, hr *models.HealthRecord) error
-	DeleteHealthRecord(ctx context.Context, date time.Time) error
+	CreateHealthRecord(ctx context.Context, userID int64, hr *models.HealthRecord) (*models.HealthRecord, error)
+	ReadHealthRecord(ctx context.Context, userID int64, date time.Time) (*models.HealthRecord, error)
+	ReadHealthRecordsByYear(ctx context.Context, userID int64, year int) ([]models.HealthRecord, error)
+	ReadHealthRecordsByYearMonth(ctx context.Context, userID int64, year, month int) ([]models.HealthRecord, error)
+	ReadHealthRecordsByRange(ctx context.Context, userID int64, startDate, endDate time.Time) ([]models.HealthRecord, error)
+	ReadHealthRecordsPage(ctx context.Context, userID int64, startDate, endDate time.Time, page models.PageRequest) (*models.HealthRecordPage, error)
+	ReadStepCountStats(ctx context.Context, userID int64, startDate, endDate time.Time, groupBy models.StatsGrouping) ([]models.StepCountStats, error)
+	UpdateHealthRecord(ctx context.Context, userID int64, hr *models.HealthRecord) error
+	UpsertHealthRecord(ctx context.Context, userID int64, hr *models.HealthRecord) (stored *models.HealthRecord, created bool, err error)
+	// UpsertHealthRecords creates or replaces the records in a single transaction; on error none are stored
+	UpsertHealthRecords(ctx context.Context, userID int64, records []models.HealthRecord) ([]models.HealthRecordUpsert, error)
+	// ImportHealthRecords writes the yielded records in a single transaction, applying the conflict policy to dates
+	// that already have a record. Under ConflictFail the first such date rolls the import back with ErrConflict,
+	// as does an error yielded with a record; a dry run always rolls back.
+	ImportHealthRecords(ctx context.Context, userID int64, records iter.Seq2[models.HealthRecord, error], opts models.ImportOptions) ([]models.HealthRecordImport, error)
+	DeleteHealthRecord(ctx context.Context, userID int64, date time.Time, version int64) error
+	UserStore
+	APIKeyStore
+	GoalStore
+	SleepStore
+	WeightStore
+	VitalStore
+	MetricStore
+	IdempotencyStore
 	Close() error
 }
+
+// UserStore stores the user accounts that own all other data
+type UserStore interface {
+	CreateUser(ctx context.Context, u *models.User) (*models.User, error)
+	ReadUser(ctx context.Context, id int64) (*models.User, error)
+	ReadUserByEmail(ctx context.Context, email string) (*models.User, error)
+}
+
+// APIKeyStore stores the hashed API keys of the users.
+// ReadAPIKeyByHash and TouchAPIKey serve authentication and are not scoped to a user.
+type APIKeyStore interface {
+	CreateAPIKey(ctx context.Context, userID int64, k *models.APIKey) (*models.APIKey, error)
+	ReadAPIKeys(ctx context.Context, userID int64) ([]models.APIKey, error)
+	ReadAPIKeyByHash(ctx context.Context, keyHash string) (*models.APIKey, error)
+	UpdateAPIKeyName(ctx context.Context, userID int64, id int64, name string) error
+	RevokeAPIKey(ctx context.Context, userID int64, id int64) error
+	TouchAPIKey(ctx context.Context, id int64, usedAt time.Time) error
+}
+
+// GoalStore stores the daily step goals. Each goal applies from its effective date
+// until the next one, so goal changes never affect earlier days.
+type GoalStore interface {
+	UpsertStepGoal(ctx context.Context, userID int64, g *models.StepGoal) (*models.StepGoal, error)
+	ReadStepGoals(ctx context.Context, userID int64) ([]models.StepGoal, error)
+	DeleteStepGoal(ctx context.Context, userID int64, effectiveFrom time.Time) error
+}
+
+// SleepStore stores sleep sessions
+type SleepStore interface {
+	CreateSleepSession(ctx context.Context, userID int64, s *models.SleepSession) (*models.SleepSession, error)
+	ReadSleepSession(ctx context.Context, userID int64, id int64) (*models.SleepSession, error)
+	ReadSleepSessionsByRange(ctx context.Context, userID int64, startDate, endDate time.Time) ([]models.SleepSession, error)
+	ReadOverlappingSleepSessions(ctx context.Context, userID int64, bedTime, wakeTime time.Time) ([]models.SleepSession, error)
+	UpdateSleepSession(ctx context.Context, userID int64, s *models.SleepSession) error
+	DeleteSleepSession(ctx context.Context, userID int64, id int64) error
+}
+
+// WeightStore stores body weight readings and the height profile used for BMI
+type WeightStore interface {
+	CreateWeightReading(ctx context.Context, userID int64, wr *models.WeightReading) (*models.WeightReading, error)
+	ReadWeightReadingsByRange(ctx context.Context, userID int64, startDate, endDate time.Time) ([]models.WeightReading, error)
+	DeleteWeightReading(ctx context.Context, userID int64, id int64) error
+	ReadHeightProfile(ctx context.Context, userID int64) (*models.HeightProfile, error)
+	UpsertHeightProfile(ctx context.Context, userID int64, hp *models.HeightProfile) (*models.HeightProfile, error)
+}
+
+// VitalStore stores timestamped blood pressure and heart rate readings
+type VitalStore interface {
+	CreateBloodPressureReading(ctx context.Context, userID int64, bp *models.BloodPressureReading) (*models.BloodPressureReading, error)
+	ReadBloodPressureReadingsByRange(ctx context.Context, userID int64, startDate, endDate time.Time) ([]models.BloodPressureReading, error)
+	CreateHeartRateReading(ctx context.Context, userID int64, hr *models.HeartRateReading) (*models.HeartRateReading, error)
+	ReadHeartRateReadingsByRange(ctx context.Context, userID int64, startDate, endDate time.Time) ([]models.HeartRateReading, error)
+}
+
+// MetricStore stores custom metric definitions and their recorded values
+type MetricStore interface {
+	CreateMetricDefinition(ctx context.Context, userID int64, def *models.MetricDefinition) (*models.MetricDefinition, error)
+	ReadMetricDefinition(ctx context.Context, userID int64, name string) (*models.MetricDefinition, error)
+	ReadMetricDefinitions(ctx context.Context, userID int64) ([]models.MetricDefinition, error)
+	DeleteMetricDefinition(ctx context.Context, userID int64, id int64) error
+	CreateMetricValue(ctx context.Context, userID int64, mv *models.MetricValue) (*models.MetricValue, error)
+	ReadMetricValuesByRange(ctx context.Context, userID int64, metricID int64, startDate, endDate time.Time) ([]models.MetricValue, error)
+}
+
+// IdempotencyStore stores the responses of requests sent with an Idempotency-Key header until they expire.
+// A key is reserved when its first request starts and completed with the response once that finishes;
+// the request hash is stored on completion, when the whole body has been read.
+type IdempotencyStore interface {
+	ReserveIdempotencyKey(ctx context.Context, userID int64, k *models.IdempotencyKey) error
+	ReadIdempotencyKey(ctx context.Context, userID int64, key string) (*models.IdempotencyKey, error)
+	CompleteIdempotencyKey(ctx context.Context, userID int64, k *models.IdempotencyKey) error
+	DeleteIdempotencyKey(ctx context.Context, userID int64, key string) error
+	PurgeIdempotencyKeys(ctx context.Context, now time.Time) (int64, error)
+}
+
+// HealthChecker is implemented by backends that can report whether they are able to serve requests.
+// It is optional; callers check for it with a type assertion.
+type HealthChecker interface {
+	// HealthCheck returns an error if the database is unreachable or its connection pool is exhausted (ErrPoolExhausted)
+	HealthCheck(ctx context.Context) error
+	// GetPoolInfo returns connection pool statistics for debugging
+	GetPoolInfo() map[string]any
+}
+
+// UsageReader is implemented by backends that can summarize usage across all users for monitoring.
+// It is optional; callers check for it with a type assertion.
+type UsageReader interface {
+	// ReadUsage returns the number of users and of health records written since the given time
+	ReadUsage(ctx context.Context, since time.Time) (*models.Usage, error)
+}
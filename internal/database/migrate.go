@@ -0,0 +1,178 @@
+package database
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// migrationFiles holds the numbered migrations of each dialect,
+// named like migrations/sqlite/0002_add_index.up.sql and 0002_add_index.down.sql
+//
+//go:embed migrations
+var migrationFiles embed.FS
+
+// Migration dialects, the directories of migrationFiles
+const (
+	dialectSQLite   = "sqlite"
+	dialectPostgres = "postgres"
+)
+
+// Migration is a numbered schema change and the SQL to apply and revert it.
+// Code migrations have no SQL; every backend implements them in Go.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+	Code    bool
+}
+
+// Versions of the code migrations
+const (
+	// migrationLegacyHealthRecords upgrades the health_records table of databases created before
+	// schema migrations existed, which 0001 leaves as it is: their records are assigned to the default
+	// user and the version column is added. It depends on the shape of the existing table and on the
+	// configured default user, so it can't be written as plain SQL.
+	migrationLegacyHealthRecords = 2
+)
+
+// codeMigrations are the migrations implemented in Go, applied in version order with the SQL migrations.
+// They only upgrade existing tables in place, so reverting them is a no-op.
+var codeMigrations = []Migration{
+	{Version: migrationLegacyHealthRecords, Name: "upgrade_legacy_health_records", Code: true},
+}
+
+// MigrationStatus is a migration and when it was applied. AppliedAt is nil for pending migrations.
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// Migrator applies and reverts the schema migrations of a database
+type Migrator interface {
+	// MigrateUp applies all pending migrations in order and returns them
+	MigrateUp(ctx context.Context) ([]Migration, error)
+	// MigrateDown reverts the last steps applied migrations in reverse order and returns them
+	MigrateDown(ctx context.Context, steps int) ([]Migration, error)
+	// MigrationStatus lists all known migrations
+	MigrationStatus(ctx context.Context) ([]MigrationStatus, error)
+	Close() error
+}
+
+// loadMigrations reads the migrations of a dialect, sorted by version.
+// Every version needs both an up and a down file.
+func loadMigrations(dialect string) ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, path.Join("migrations", dialect))
+	if err != nil {
+		return nil, fmt.Errorf("read migrations: %w", err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		name := entry.Name()
+		base, direction, ok := strings.Cut(strings.TrimSuffix(name, ".sql"), ".")
+		if !ok || !strings.HasSuffix(name, ".sql") || (direction != "up" && direction != "down") {
+			return nil, fmt.Errorf("invalid migration file name: %s", name)
+		}
+		number, label, ok := strings.Cut(base, "_")
+		version, err := strconv.Atoi(number)
+		if !ok || err != nil || version <= 0 {
+			return nil, fmt.Errorf("invalid migration file name: %s", name)
+		}
+
+		sql, err := fs.ReadFile(migrationFiles, path.Join("migrations", dialect, name))
+		if err != nil {
+			return nil, fmt.Errorf("read migration %s: %w", name, err)
+		}
+
+		m, exists := byVersion[version]
+		if !exists {
+			m = &Migration{Version: version, Name: label}
+			byVersion[version] = m
+		}
+		if m.Name != label {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, m.Name, label)
+		}
+		if direction == "up" {
+			m.Up = string(sql)
+		} else {
+			m.Down = string(sql)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion)+len(codeMigrations))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s needs an up and a down file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	for _, m := range codeMigrations {
+		if _, exists := byVersion[m.Version]; exists {
+			return nil, fmt.Errorf("migration %d is both a code and a SQL migration", m.Version)
+		}
+		migrations = append(migrations, m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+// pendingMigrations returns the migrations that have not been applied, in order
+func pendingMigrations(migrations []Migration, applied map[int]time.Time) []Migration {
+	var pending []Migration
+	for _, m := range migrations {
+		if _, ok := applied[m.Version]; !ok {
+			pending = append(pending, m)
+		}
+	}
+	return pending
+}
+
+// revertibleMigrations returns the last steps applied migrations, latest first.
+// An applied version without a migration file can't be reverted.
+func revertibleMigrations(migrations []Migration, applied map[int]time.Time, steps int) ([]Migration, error) {
+	byVersion := make(map[int]Migration, len(migrations))
+	for _, m := range migrations {
+		byVersion[m.Version] = m
+	}
+
+	versions := make([]int, 0, len(applied))
+	for version := range applied {
+		versions = append(versions, version)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(versions)))
+
+	var revert []Migration
+	for _, version := range versions {
+		if len(revert) == steps {
+			break
+		}
+		m, ok := byVersion[version]
+		if !ok {
+			return nil, fmt.Errorf("applied migration %d is unknown to this build", version)
+		}
+		revert = append(revert, m)
+	}
+	return revert, nil
+}
+
+// migrationStatuses pairs the migrations with the time they were applied
+func migrationStatuses(migrations []Migration, applied map[int]time.Time) []MigrationStatus {
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		status := MigrationStatus{Migration: m}
+		if at, ok := applied[m.Version]; ok {
+			status.AppliedAt = &at
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
@@ -0,0 +1,12 @@
+DROP TABLE IF EXISTS idempotency_keys;
+DROP TABLE IF EXISTS metric_values;
+DROP TABLE IF EXISTS metric_definitions;
+DROP TABLE IF EXISTS heart_rate_readings;
+DROP TABLE IF EXISTS blood_pressure_readings;
+DROP TABLE IF EXISTS height_profiles;
+DROP TABLE IF EXISTS weight_readings;
+DROP TABLE IF EXISTS sleep_sessions;
+DROP TABLE IF EXISTS step_goals;
+DROP TABLE IF EXISTS health_records;
+DROP TABLE IF EXISTS api_keys;
+DROP TABLE IF EXISTS users;
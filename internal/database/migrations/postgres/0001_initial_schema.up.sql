@@ -0,0 +1,166 @@
+-- Initial schema. The statements are idempotent, so databases created before
+-- schema migrations were introduced keep their health_records table; migration 2
+-- (upgrade_legacy_health_records, implemented in Go) brings it to this shape.
+
+CREATE TABLE IF NOT EXISTS users (
+    id SERIAL PRIMARY KEY,
+    email VARCHAR(255) NOT NULL UNIQUE,
+    password_hash VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(20) NOT NULL,
+    key_hash CHAR(64) NOT NULL UNIQUE,
+    scope VARCHAR(20) NOT NULL,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_user
+    ON api_keys(user_id);
+
+CREATE TABLE IF NOT EXISTS health_records (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    step_count INTEGER NOT NULL CHECK (step_count >= 0),
+    version BIGINT NOT NULL DEFAULT 1,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, date)
+);
+
+CREATE TABLE IF NOT EXISTS step_goals (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    effective_from DATE NOT NULL,
+    step_count INTEGER NOT NULL CHECK (step_count > 0),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, effective_from)
+);
+
+CREATE TABLE IF NOT EXISTS sleep_sessions (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    bed_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    wake_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    duration_minutes INTEGER NOT NULL CHECK (duration_minutes > 0),
+    quality INTEGER CHECK (quality BETWEEN 0 AND 100),
+    is_nap BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (wake_time > bed_time)
+);
+
+CREATE INDEX IF NOT EXISTS idx_sleep_sessions_date
+    ON sleep_sessions(user_id, date);
+
+CREATE INDEX IF NOT EXISTS idx_sleep_sessions_bed_time
+    ON sleep_sessions(user_id, bed_time);
+
+CREATE TABLE IF NOT EXISTS weight_readings (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    measured_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    weight_kg NUMERIC(5,2) NOT NULL CHECK (weight_kg > 0),
+    body_fat_percent NUMERIC(4,2) CHECK (body_fat_percent BETWEEN 0 AND 100),
+    muscle_mass_kg NUMERIC(5,2) CHECK (muscle_mass_kg > 0),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_weight_readings_date
+    ON weight_readings(user_id, date, measured_at);
+
+CREATE TABLE IF NOT EXISTS height_profiles (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    height_cm NUMERIC(5,2) NOT NULL CHECK (height_cm > 0),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS blood_pressure_readings (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    measured_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    systolic INTEGER NOT NULL CHECK (systolic > 0),
+    diastolic INTEGER NOT NULL CHECK (diastolic > 0),
+    pulse INTEGER CHECK (pulse > 0),
+    context VARCHAR(20) NOT NULL DEFAULT '',
+    arm VARCHAR(10) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_blood_pressure_readings_date
+    ON blood_pressure_readings(user_id, date, measured_at);
+
+CREATE TABLE IF NOT EXISTS heart_rate_readings (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    measured_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    bpm INTEGER NOT NULL CHECK (bpm > 0),
+    context VARCHAR(20) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_heart_rate_readings_date
+    ON heart_rate_readings(user_id, date, measured_at);
+
+CREATE TABLE IF NOT EXISTS metric_definitions (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    unit VARCHAR(20) NOT NULL DEFAULT '',
+    value_type VARCHAR(10) NOT NULL CHECK (value_type IN ('int', 'float', 'duration', 'enum')),
+    min_value DOUBLE PRECISION,
+    max_value DOUBLE PRECISION,
+    enum_values TEXT[] NOT NULL DEFAULT '{}',
+    aggregation VARCHAR(10) NOT NULL CHECK (aggregation IN ('sum', 'avg', 'last')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS metric_values (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    metric_id INTEGER NOT NULL REFERENCES metric_definitions(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    recorded_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    value DOUBLE PRECISION,
+    enum_value VARCHAR(50) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_metric_values_metric_date
+    ON metric_values(user_id, metric_id, date, recorded_at);
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    idempotency_key VARCHAR(255) NOT NULL,
+    request_hash CHAR(64) NOT NULL,
+    status_code INTEGER NOT NULL DEFAULT 0,
+    headers TEXT NOT NULL DEFAULT '{}',
+    body BYTEA,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    UNIQUE (user_id, idempotency_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires
+    ON idempotency_keys(expires_at);
@@ -0,0 +1,165 @@
+-- Initial schema. The statements are idempotent, so databases created before
+-- schema migrations were introduced keep their health_records table; migration 2
+-- (upgrade_legacy_health_records, implemented in Go) brings it to this shape.
+
+CREATE TABLE IF NOT EXISTS users (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    email TEXT NOT NULL UNIQUE,
+    password_hash TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    name TEXT NOT NULL,
+    prefix TEXT NOT NULL,
+    key_hash TEXT NOT NULL UNIQUE,
+    scope TEXT NOT NULL,
+    last_used_at DATETIME,
+    revoked_at DATETIME,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_user
+    ON api_keys(user_id);
+
+CREATE TABLE IF NOT EXISTS health_records (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    date DATE NOT NULL,
+    step_count INTEGER NOT NULL,
+    version INTEGER NOT NULL DEFAULT 1,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    UNIQUE (user_id, date)
+);
+
+CREATE TABLE IF NOT EXISTS step_goals (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    effective_from DATE NOT NULL,
+    step_count INTEGER NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    UNIQUE (user_id, effective_from)
+);
+
+CREATE TABLE IF NOT EXISTS sleep_sessions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    date DATE NOT NULL,
+    bed_time DATETIME NOT NULL,
+    wake_time DATETIME NOT NULL,
+    duration_minutes INTEGER NOT NULL,
+    quality INTEGER,
+    is_nap BOOLEAN NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_sleep_sessions_date
+    ON sleep_sessions(user_id, date);
+
+CREATE INDEX IF NOT EXISTS idx_sleep_sessions_bed_time
+    ON sleep_sessions(user_id, bed_time);
+
+CREATE TABLE IF NOT EXISTS weight_readings (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    date DATE NOT NULL,
+    measured_at DATETIME NOT NULL,
+    weight_kg REAL NOT NULL,
+    body_fat_percent REAL,
+    muscle_mass_kg REAL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_weight_readings_date
+    ON weight_readings(user_id, date, measured_at);
+
+CREATE TABLE IF NOT EXISTS height_profiles (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id),
+    height_cm REAL NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS blood_pressure_readings (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    date DATE NOT NULL,
+    measured_at DATETIME NOT NULL,
+    systolic INTEGER NOT NULL,
+    diastolic INTEGER NOT NULL,
+    pulse INTEGER,
+    context TEXT NOT NULL DEFAULT '',
+    arm TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_blood_pressure_readings_date
+    ON blood_pressure_readings(user_id, date, measured_at);
+
+CREATE TABLE IF NOT EXISTS heart_rate_readings (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    date DATE NOT NULL,
+    measured_at DATETIME NOT NULL,
+    bpm INTEGER NOT NULL,
+    context TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_heart_rate_readings_date
+    ON heart_rate_readings(user_id, date, measured_at);
+
+CREATE TABLE IF NOT EXISTS metric_definitions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    name TEXT NOT NULL,
+    unit TEXT NOT NULL DEFAULT '',
+    value_type TEXT NOT NULL,
+    min_value REAL,
+    max_value REAL,
+    enum_values TEXT NOT NULL DEFAULT '[]',
+    aggregation TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    UNIQUE (user_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS metric_values (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    metric_id INTEGER NOT NULL REFERENCES metric_definitions(id),
+    date DATE NOT NULL,
+    recorded_at DATETIME NOT NULL,
+    value REAL,
+    enum_value TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_metric_values_metric_date
+    ON metric_values(user_id, metric_id, date, recorded_at);
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    idempotency_key TEXT NOT NULL,
+    request_hash TEXT NOT NULL,
+    status_code INTEGER NOT NULL DEFAULT 0,
+    headers TEXT NOT NULL DEFAULT '{}',
+    body BLOB,
+    created_at DATETIME NOT NULL,
+    expires_at DATETIME NOT NULL,
+    UNIQUE (user_id, idempotency_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires
+    ON idempotency_keys(expires_at);
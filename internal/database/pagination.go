@@ -0,0 +1,64 @@
+package database
+
+import (
+	"github.com/nnamm/go-health-tracker/internal/models"
+)
+
+// recordPageOrder describes how a health record listing is sorted and continued after a cursor.
+// The date is unique per user, so it breaks ties between equal step counts.
+type recordPageOrder struct {
+	orderBy string
+	// conditions selecting the records after the cursor; the Postgres form
+	// continues the numbering after $1-$3 (user ID, start and end date)
+	sqliteAfter   string
+	postgresAfter string
+	// cursorArgs returns the values for the placeholders of the after condition
+	cursorArgs func(c *models.HealthRecordCursor) []any
+}
+
+// recordPageOrders maps every supported sort to its query parts
+var recordPageOrders = map[models.RecordSort]recordPageOrder{
+	models.RecordSortDate: {
+		orderBy:       "date",
+		sqliteAfter:   "date > ?",
+		postgresAfter: "date > $4",
+		cursorArgs:    dateCursorArgs,
+	},
+	models.RecordSortDateDesc: {
+		orderBy:       "date DESC",
+		sqliteAfter:   "date < ?",
+		postgresAfter: "date < $4",
+		cursorArgs:    dateCursorArgs,
+	},
+	models.RecordSortStepCount: {
+		orderBy:       "step_count, date",
+		sqliteAfter:   "(step_count, date) > (?, ?)",
+		postgresAfter: "(step_count, date) > ($4, $5)",
+		cursorArgs:    stepCountCursorArgs,
+	},
+	models.RecordSortStepCountDesc: {
+		orderBy:       "step_count DESC, date DESC",
+		sqliteAfter:   "(step_count, date) < (?, ?)",
+		postgresAfter: "(step_count, date) < ($4, $5)",
+		cursorArgs:    stepCountCursorArgs,
+	},
+}
+
+func dateCursorArgs(c *models.HealthRecordCursor) []any {
+	return []any{c.Date}
+}
+
+func stepCountCursorArgs(c *models.HealthRecordCursor) []any {
+	return []any{c.StepCount, c.Date}
+}
+
+// pageRecords trims the one extra record fetched to detect a further page
+// and returns the page with the cursor pointing after its last record
+func pageRecords(records []models.HealthRecord, page models.PageRequest, total int) *models.HealthRecordPage {
+	result := &models.HealthRecordPage{Records: records, Total: total}
+	if len(records) > page.Limit {
+		result.Records = records[:page.Limit]
+		result.NextCursor = models.CursorFor(page.Sort, &result.Records[page.Limit-1]).Encode()
+	}
+	return result
+}
@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"fmt"
+	"iter"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -16,6 +17,7 @@ type PgxPool interface {
 	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
 	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
 	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	Begin(ctx context.Context) (pgx.Tx, error)
 	Ping(ctx context.Context) error
 	Close()
 	Stat() *pgxpool.Stat
@@ -41,8 +43,26 @@ func WithConnLife(d time.Duration) DBOption {
 	return func(cfg *pgxpool.Config) { cfg.MaxConnLifetime = d }
 }
 
-// NewPostgresDB creates a new PostgresDB instance.
+// NewPostgresDB creates a new PostgresDB instance and applies pending schema migrations.
 func NewPostgresDB(dsn string, opts ...DBOption) (*PostgresDB, error) {
+	db, err := OpenPostgresDB(dsn, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if _, err := db.MigrateUp(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrate schema: %w", err)
+	}
+
+	return db, nil
+}
+
+// OpenPostgresDB connects to the database without touching the schema, e.g. to run migrations.
+func OpenPostgresDB(dsn string, opts ...DBOption) (*PostgresDB, error) {
 	poolCfg, err := pgxpool.ParseConfig(dsn)
 	if err != nil {
 		return nil, fmt.Errorf("parse config: %w", err)
@@ -70,14 +90,7 @@ func NewPostgresDB(dsn string, opts ...DBOption) (*PostgresDB, error) {
 		return nil, fmt.Errorf("ping: %w", err)
 	}
 
-	db := &PostgresDB{pool: pool}
-
-	if err := db.createTable(); err != nil {
-		pool.Close()
-		return nil, err
-	}
-
-	return db, nil
+	return &PostgresDB{pool: pool}, nil
 }
 
 // NewPostgresDBWithPool creates a new PostgresDB instance with a pgxpool.Pool.
@@ -85,37 +98,12 @@ func NewPostgresDBWithPool(pool PgxPool) *PostgresDB {
 	return &PostgresDB{pool: pool}
 }
 
-// createTable creates the health_records table if it doesn't exist
-func (db *PostgresDB) createTable() error {
-	queries := []string{
-		`CREATE TABLE IF NOT EXISTS health_records (
-			id SERIAL PRIMARY KEY,
-			date DATE NOT NULL UNIQUE,
-			step_count INTEGER NOT NULL CHECK (step_count >= 0),
-			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
-			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
-	    )`,
-		`CREATE UNIQUE INDEX IF NOT EXISTS idx_health_records_date
-         ON health_records(date)`,
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	for _, query := range queries {
-		if _, err := db.pool.Exec(ctx, query); err != nil {
-			return fmt.Errorf("failed to execute query %s: %w", query, err)
-		}
-	}
-	return nil
-}
-
 // CreateHealthRecord creates a new health record
-func (db *PostgresDB) CreateHealthRecord(ctx context.Context, hr *models.HealthRecord) (*models.HealthRecord, error) {
+func (db *PostgresDB) CreateHealthRecord(ctx context.Context, userID int64, hr *models.HealthRecord) (*models.HealthRecord, error) {
 	query := `
-		INSERT INTO health_records (date, step_count, created_at, updated_at)
-		VALUES ($1, $2, $3, $4)
-		RETURNING id, created_at, updated_at`
+		INSERT INTO health_records (user_id, date, step_count, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, version, created_at, updated_at`
 
 	now := time.Now()
 	var createdRecord models.HealthRecord
@@ -124,11 +112,15 @@ func (db *PostgresDB) CreateHealthRecord(ctx context.Context, hr *models.HealthR
 	createdRecord.Date = hr.Date
 	createdRecord.StepCount = hr.StepCount
 
-	err := db.pool.QueryRow(ctx, query, hr.Date, hr.StepCount, now, now).Scan(
+	err := db.pool.QueryRow(ctx, query, userID, hr.Date, hr.StepCount, now, now).Scan(
 		&createdRecord.ID,
+		&createdRecord.Version,
 		&createdRecord.CreatedAt,
 		&createdRecord.UpdatedAt,
 	)
+	if isPostgresUniqueViolation(err) {
+		return nil, ErrConflict
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to create health record: %w", err)
 	}
@@ -137,14 +129,15 @@ func (db *PostgresDB) CreateHealthRecord(ctx context.Context, hr *models.HealthR
 }
 
 // ReadHealthRecord reads a health record by date
-func (db *PostgresDB) ReadHealthRecord(ctx context.Context, date time.Time) (*models.HealthRecord, error) {
-	query := `SELECT id, date, step_count, created_at, updated_at FROM health_records WHERE date = $1`
+func (db *PostgresDB) ReadHealthRecord(ctx context.Context, userID int64, date time.Time) (*models.HealthRecord, error) {
+	query := `SELECT id, date, step_count, version, created_at, updated_at FROM health_records WHERE user_id = $1 AND date = $2`
 
 	var hr models.HealthRecord
-	err := db.pool.QueryRow(ctx, query, date).Scan(
+	err := db.pool.QueryRow(ctx, query, userID, date).Scan(
 		&hr.ID,
 		&hr.Date,
 		&hr.StepCount,
+		&hr.Version,
 		&hr.CreatedAt,
 		&hr.UpdatedAt,
 	)
@@ -159,28 +152,28 @@ func (db *PostgresDB) ReadHealthRecord(ctx context.Context, date time.Time) (*mo
 }
 
 // ReadHealthRecordsByYear reads health records for a specific year
-func (db *PostgresDB) ReadHealthRecordsByYear(ctx context.Context, year int) ([]models.HealthRecord, error) {
+func (db *PostgresDB) ReadHealthRecordsByYear(ctx context.Context, userID int64, year int) ([]models.HealthRecord, error) {
 	startDate := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
 	endDate := startDate.AddDate(1, 0, 0)
-	return db.readHealthRecordsByRange(ctx, startDate, endDate)
+	return db.ReadHealthRecordsByRange(ctx, userID, startDate, endDate)
 }
 
 // ReadHealthRecordsByYearMonth reads health records for a specific year and month
-func (db *PostgresDB) ReadHealthRecordsByYearMonth(ctx context.Context, year, month int) ([]models.HealthRecord, error) {
+func (db *PostgresDB) ReadHealthRecordsByYearMonth(ctx context.Context, userID int64, year, month int) ([]models.HealthRecord, error) {
 	startDate := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
 	endDate := startDate.AddDate(0, 1, 0)
-	return db.readHealthRecordsByRange(ctx, startDate, endDate)
+	return db.ReadHealthRecordsByRange(ctx, userID, startDate, endDate)
 }
 
-// readHealthRecordsByRange reads health records within a date range
-func (db *PostgresDB) readHealthRecordsByRange(ctx context.Context, startDate, endDate time.Time) ([]models.HealthRecord, error) {
+// ReadHealthRecordsByRange reads health records within a date range [startDate, endDate)
+func (db *PostgresDB) ReadHealthRecordsByRange(ctx context.Context, userID int64, startDate, endDate time.Time) ([]models.HealthRecord, error) {
 	query := `
-		SELECT id, date, step_count, created_at, updated_at
+		SELECT id, date, step_count, version, created_at, updated_at
 		FROM health_records
-		WHERE date >= $1 AND date < $2
+		WHERE user_id = $1 AND date >= $2 AND date < $3
 		ORDER BY date`
 
-	rows, err := db.pool.Query(ctx, query, startDate, endDate)
+	rows, err := db.pool.Query(ctx, query, userID, startDate, endDate)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query health records: %w", err)
 	}
@@ -189,7 +182,7 @@ func (db *PostgresDB) readHealthRecordsByRange(ctx context.Context, startDate, e
 	var records []models.HealthRecord
 	for rows.Next() {
 		var hr models.HealthRecord
-		if err := rows.Scan(&hr.ID, &hr.Date, &hr.StepCount, &hr.CreatedAt, &hr.UpdatedAt); err != nil {
+		if err := rows.Scan(&hr.ID, &hr.Date, &hr.StepCount, &hr.Version, &hr.CreatedAt, &hr.UpdatedAt); err != nil {
 			return nil, fmt.Errorf("failed to scan record: %w", err)
 		}
 		records = append(records, hr)
@@ -202,41 +195,249 @@ func (db *PostgresDB) readHealthRecordsByRange(ctx context.Context, startDate, e
 	return records, nil
 }
 
-// UpdateHealthRecord updates an existing health record
-func (db *PostgresDB) UpdateHealthRecord(ctx context.Context, hr *models.HealthRecord) error {
+// ReadHealthRecordsPage reads one page of the health records within a date range [startDate, endDate)
+func (db *PostgresDB) ReadHealthRecordsPage(ctx context.Context, userID int64, startDate, endDate time.Time, page models.PageRequest) (*models.HealthRecordPage, error) {
+	order, ok := recordPageOrders[page.Sort]
+	if !ok {
+		return nil, fmt.Errorf("unsupported sort: %s", page.Sort)
+	}
+
+	countQuery := `SELECT COUNT(*) FROM health_records WHERE user_id = $1 AND date >= $2 AND date < $3`
+
+	var total int
+	if err := db.pool.QueryRow(ctx, countQuery, userID, startDate, endDate).Scan(&total); err != nil {
+		return nil, fmt.Errorf("failed to count health records: %w", err)
+	}
+
+	query := `
+		SELECT id, date, step_count, version, created_at, updated_at
+		FROM health_records
+		WHERE user_id = $1 AND date >= $2 AND date < $3`
+	args := []any{userID, startDate, endDate}
+	if page.After != nil {
+		query += ` AND ` + order.postgresAfter
+		args = append(args, order.cursorArgs(page.After)...)
+	}
+	args = append(args, page.Limit+1)
+	query += fmt.Sprintf(` ORDER BY %s LIMIT $%d`, order.orderBy, len(args))
+
+	rows, err := db.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query health records: %w", err)
+	}
+	defer rows.Close()
+
+	var records []models.HealthRecord
+	for rows.Next() {
+		var hr models.HealthRecord
+		if err := rows.Scan(&hr.ID, &hr.Date, &hr.StepCount, &hr.Version, &hr.CreatedAt, &hr.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan record: %w", err)
+		}
+		records = append(records, hr)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating through rows: %w", err)
+	}
+
+	return pageRecords(records, page, total), nil
+}
+
+// UpdateHealthRecord updates an existing health record.
+// A non-zero hr.Version must match the stored version, otherwise ErrVersionMismatch is returned.
+func (db *PostgresDB) UpdateHealthRecord(ctx context.Context, userID int64, hr *models.HealthRecord) error {
 	query := `UPDATE health_records
-	          SET step_count = $1, updated_at = $2
-	          WHERE date = $3`
+	          SET step_count = $1, updated_at = $2, version = version + 1
+	          WHERE user_id = $3 AND date = $4 AND ($5 = 0 OR version = $5)`
 
 	now := time.Now()
-	tag, err := db.pool.Exec(ctx, query, hr.StepCount, now, hr.Date)
+	tag, err := db.pool.Exec(ctx, query, hr.StepCount, now, userID, hr.Date, hr.Version)
 	if err != nil {
 		return fmt.Errorf("failed to update health record: %w", err)
 	}
 
 	if tag.RowsAffected() == 0 {
-		return fmt.Errorf("record not found for date: %v", hr.Date)
+		return db.missingOrStaleHealthRecord(ctx, userID, hr.Date, hr.Version)
 	}
 
 	return nil
 }
 
-// DeleteHealthRecord deletes a health record
-func (db *PostgresDB) DeleteHealthRecord(ctx context.Context, date time.Time) error {
-	query := `DELETE FROM health_records WHERE date = $1`
+// UpsertHealthRecord creates the record of a date or replaces the step count of the stored one.
+// created reports whether the record was created.
+func (db *PostgresDB) UpsertHealthRecord(ctx context.Context, userID int64, hr *models.HealthRecord) (*models.HealthRecord, bool, error) {
+	query := `
+		INSERT INTO health_records (user_id, date, step_count, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, date) DO UPDATE SET step_count = EXCLUDED.step_count, updated_at = EXCLUDED.updated_at, version = health_records.version + 1
+		RETURNING id, date, step_count, version, created_at, updated_at`
+
+	now := time.Now()
+	var stored models.HealthRecord
+	err := db.pool.QueryRow(ctx, query, userID, hr.Date, hr.StepCount, now, now).Scan(
+		&stored.ID,
+		&stored.Date,
+		&stored.StepCount,
+		&stored.Version,
+		&stored.CreatedAt,
+		&stored.UpdatedAt,
+	)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to upsert health record: %w", err)
+	}
+
+	// Every replace increments the version, so only a new record is at version 1
+	return &stored, stored.Version == 1, nil
+}
+
+// UpsertHealthRecords creates or replaces the records of their dates in a single transaction.
+// The upserts are sent as one pgx batch, so the whole set costs a single round trip. If any record fails, none are stored.
+func (db *PostgresDB) UpsertHealthRecords(ctx context.Context, userID int64, records []models.HealthRecord) ([]models.HealthRecordUpsert, error) {
+	query := `
+		INSERT INTO health_records (user_id, date, step_count, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, date) DO UPDATE SET step_count = EXCLUDED.step_count, updated_at = EXCLUDED.updated_at, version = health_records.version + 1
+		RETURNING id, date, step_count, version, created_at, updated_at`
 
-	tag, err := db.pool.Exec(ctx, query, date)
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // no-op after commit
+
+	now := time.Now()
+	batch := &pgx.Batch{}
+	for _, hr := range records {
+		batch.Queue(query, userID, hr.Date, hr.StepCount, now, now)
+	}
+
+	results := tx.SendBatch(ctx, batch)
+	upserts := make([]models.HealthRecordUpsert, 0, len(records))
+	for _, hr := range records {
+		var stored models.HealthRecord
+		err := results.QueryRow().Scan(
+			&stored.ID,
+			&stored.Date,
+			&stored.StepCount,
+			&stored.Version,
+			&stored.CreatedAt,
+			&stored.UpdatedAt,
+		)
+		if err != nil {
+			results.Close()
+			return nil, fmt.Errorf("failed to upsert health record of %s: %w", hr.Date.Format("2006-01-02"), err)
+		}
+
+		// Every replace increments the version, so only a new record is at version 1
+		upserts = append(upserts, models.HealthRecordUpsert{Record: stored, Created: stored.Version == 1})
+	}
+	if err := results.Close(); err != nil {
+		return nil, fmt.Errorf("failed to upsert health records: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return upserts, nil
+}
+
+// ImportHealthRecords writes the yielded records in a single transaction, applying the conflict policy to dates that already have a record.
+// Unless overwriting, a record is written by an insert that does nothing on conflict, so the existence check and the write are one statement.
+func (db *PostgresDB) ImportHealthRecords(ctx context.Context, userID int64, records iter.Seq2[models.HealthRecord, error], opts models.ImportOptions) ([]models.HealthRecordImport, error) {
+	query := `
+		INSERT INTO health_records (user_id, date, step_count, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, date) DO NOTHING
+		RETURNING id, date, step_count, version, created_at, updated_at`
+	if opts.OnConflict == models.ConflictOverwrite {
+		query = `
+			INSERT INTO health_records (user_id, date, step_count, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (user_id, date) DO UPDATE SET step_count = EXCLUDED.step_count, updated_at = EXCLUDED.updated_at, version = health_records.version + 1
+			RETURNING id, date, step_count, version, created_at, updated_at`
+	}
+	selectQuery := `SELECT id, date, step_count, version, created_at, updated_at FROM health_records WHERE user_id = $1 AND date = $2`
+
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // no-op after commit, and how a dry run ends
+
+	var imports []models.HealthRecordImport
+	now := time.Now()
+	for hr, err := range records {
+		if err != nil {
+			return nil, err
+		}
+
+		imported := models.HealthRecordImport{Action: models.ImportCreated}
+		stored := &imported.Record
+		err := tx.QueryRow(ctx, query, userID, hr.Date, hr.StepCount, now, now).Scan(&stored.ID, &stored.Date, &stored.StepCount, &stored.Version, &stored.CreatedAt, &stored.UpdatedAt)
+		switch {
+		case err == pgx.ErrNoRows:
+			// Nothing was inserted, so the date already has a record
+			if opts.OnConflict == models.ConflictFail {
+				return nil, fmt.Errorf("health record of %s: %w", hr.Date.Format("2006-01-02"), ErrConflict)
+			}
+			imported.Action = models.ImportSkipped
+			err = tx.QueryRow(ctx, selectQuery, userID, hr.Date).Scan(&stored.ID, &stored.Date, &stored.StepCount, &stored.Version, &stored.CreatedAt, &stored.UpdatedAt)
+		case err == nil && stored.Version > 1:
+			// Every replace increments the version, so only a new record is at version 1
+			imported.Action = models.ImportOverwritten
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to import health record of %s: %w", hr.Date.Format("2006-01-02"), err)
+		}
+
+		imports = append(imports, imported)
+	}
+
+	if opts.DryRun {
+		return imports, nil
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return imports, nil
+}
+
+// DeleteHealthRecord deletes a health record.
+// A non-zero version must match the stored version, otherwise ErrVersionMismatch is returned.
+func (db *PostgresDB) DeleteHealthRecord(ctx context.Context, userID int64, date time.Time, version int64) error {
+	query := `DELETE FROM health_records WHERE user_id = $1 AND date = $2 AND ($3 = 0 OR version = $3)`
+
+	tag, err := db.pool.Exec(ctx, query, userID, date, version)
 	if err != nil {
 		return fmt.Errorf("failed to delete health record: %w", err)
 	}
 
 	if tag.RowsAffected() == 0 {
-		return fmt.Errorf("record not found for date: %v", date)
+		return db.missingOrStaleHealthRecord(ctx, userID, date, version)
 	}
 
 	return nil
 }
 
+// missingOrStaleHealthRecord tells why a conditional write of a health record changed no row:
+// the record doesn't exist, or its version no longer matches
+func (db *PostgresDB) missingOrStaleHealthRecord(ctx context.Context, userID int64, date time.Time, version int64) error {
+	if version == 0 {
+		return ErrNotFound
+	}
+
+	hr, err := db.ReadHealthRecord(ctx, userID, date)
+	if err != nil {
+		return err
+	}
+	if hr == nil {
+		return ErrNotFound
+	}
+	return ErrVersionMismatch
+}
+
 // Close closes the database connection pool
 func (db *PostgresDB) Close() error {
 	if db.pool != nil {
@@ -255,13 +456,19 @@ func (db *PostgresDB) Stats() *pgxpool.Stat {
 	return db.pool.Stat()
 }
 
-// HealthCheck performs a comprehensive health check of the database connection
+// HealthCheck performs a comprehensive health check of the database connection.
+// It returns ErrPoolExhausted while every connection of the pool is acquired.
 func (db *PostgresDB) HealthCheck(ctx context.Context) error {
 	// Check if pool is available
 	if db.pool == nil {
 		return fmt.Errorf("database pool is not initialized")
 	}
 
+	// Checked first, since the ping itself has to wait for a connection
+	if stats := db.pool.Stat(); stats.AcquiredConns() >= stats.MaxConns() {
+		return ErrPoolExhausted
+	}
+
 	// Ping the database
 	if err := db.pool.Ping(ctx); err != nil {
 		return fmt.Errorf("database ping failed: %w", err)
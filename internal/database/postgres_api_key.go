@@ -0,0 +1,137 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nnamm/go-health-tracker/internal/models"
+)
+
+const apiKeyColumns = `id, user_id, name, prefix, key_hash, scope, last_used_at, revoked_at, created_at, updated_at`
+
+// CreateAPIKey creates a new API key
+func (db *PostgresDB) CreateAPIKey(ctx context.Context, userID int64, k *models.APIKey) (*models.APIKey, error) {
+	query := `
+		INSERT INTO api_keys (user_id, name, prefix, key_hash, scope, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at, updated_at`
+
+	now := time.Now()
+	created := *k
+	created.UserID = userID
+
+	err := db.pool.QueryRow(ctx, query, userID, k.Name, k.Prefix, k.KeyHash, k.Scope, now, now).Scan(
+		&created.ID,
+		&created.CreatedAt,
+		&created.UpdatedAt,
+	)
+	if isPostgresUniqueViolation(err) {
+		return nil, ErrConflict
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	return &created, nil
+}
+
+// ReadAPIKeys reads all API keys of a user, including revoked ones
+func (db *PostgresDB) ReadAPIKeys(ctx context.Context, userID int64) ([]models.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE user_id = $1 ORDER BY id`
+
+	rows, err := db.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query api keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []models.APIKey
+	for rows.Next() {
+		k, err := scanPostgresAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan api key: %w", err)
+		}
+		keys = append(keys, *k)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating through rows: %w", err)
+	}
+
+	return keys, nil
+}
+
+// ReadAPIKeyByHash reads the API key with the given hash
+func (db *PostgresDB) ReadAPIKeyByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE key_hash = $1`
+
+	k, err := scanPostgresAPIKey(db.pool.QueryRow(ctx, query, keyHash))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil // No key found, return nil without error
+		}
+		return nil, fmt.Errorf("failed to read api key: %w", err)
+	}
+
+	return k, nil
+}
+
+// UpdateAPIKeyName renames an API key
+func (db *PostgresDB) UpdateAPIKeyName(ctx context.Context, userID int64, id int64, name string) error {
+	query := `UPDATE api_keys SET name = $3, updated_at = $4 WHERE user_id = $1 AND id = $2`
+
+	tag, err := db.pool.Exec(ctx, query, userID, id, name, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to update api key: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// RevokeAPIKey revokes an API key that is not revoked yet
+func (db *PostgresDB) RevokeAPIKey(ctx context.Context, userID int64, id int64) error {
+	query := `UPDATE api_keys SET revoked_at = $3, updated_at = $3 WHERE user_id = $1 AND id = $2 AND revoked_at IS NULL`
+
+	tag, err := db.pool.Exec(ctx, query, userID, id, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// TouchAPIKey records the time an API key was last used
+func (db *PostgresDB) TouchAPIKey(ctx context.Context, id int64, usedAt time.Time) error {
+	query := `UPDATE api_keys SET last_used_at = $2 WHERE id = $1`
+
+	tag, err := db.pool.Exec(ctx, query, id, usedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update api key last use: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// scanPostgresAPIKey scans an API key row
+func scanPostgresAPIKey(row pgx.Row) (*models.APIKey, error) {
+	var k models.APIKey
+	err := row.Scan(&k.ID, &k.UserID, &k.Name, &k.Prefix, &k.KeyHash, &k.Scope, &k.LastUsedAt, &k.RevokedAt, &k.CreatedAt, &k.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
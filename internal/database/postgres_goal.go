@@ -0,0 +1,80 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nnamm/go-health-tracker/internal/models"
+)
+
+const stepGoalColumns = `id, effective_from, step_count, created_at, updated_at`
+
+// UpsertStepGoal creates the step goal effective from the given date or replaces its step count
+func (db *PostgresDB) UpsertStepGoal(ctx context.Context, userID int64, g *models.StepGoal) (*models.StepGoal, error) {
+	query := `
+		INSERT INTO step_goals (user_id, effective_from, step_count, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, effective_from) DO UPDATE SET step_count = EXCLUDED.step_count, updated_at = EXCLUDED.updated_at
+		RETURNING ` + stepGoalColumns
+
+	now := time.Now()
+	stored, err := scanPostgresStepGoal(db.pool.QueryRow(ctx, query, userID, g.EffectiveFrom, g.StepCount, now, now))
+	if err != nil {
+		return nil, fmt.Errorf("failed to upsert step goal: %w", err)
+	}
+
+	return stored, nil
+}
+
+// ReadStepGoals reads all step goals of a user ordered by effective date
+func (db *PostgresDB) ReadStepGoals(ctx context.Context, userID int64) ([]models.StepGoal, error) {
+	query := `SELECT ` + stepGoalColumns + ` FROM step_goals WHERE user_id = $1 ORDER BY effective_from`
+
+	rows, err := db.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query step goals: %w", err)
+	}
+	defer rows.Close()
+
+	var goals []models.StepGoal
+	for rows.Next() {
+		g, err := scanPostgresStepGoal(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan step goal: %w", err)
+		}
+		goals = append(goals, *g)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating through rows: %w", err)
+	}
+
+	return goals, nil
+}
+
+// DeleteStepGoal deletes the step goal effective from the given date
+func (db *PostgresDB) DeleteStepGoal(ctx context.Context, userID int64, effectiveFrom time.Time) error {
+	query := `DELETE FROM step_goals WHERE user_id = $1 AND effective_from = $2`
+
+	tag, err := db.pool.Exec(ctx, query, userID, effectiveFrom)
+	if err != nil {
+		return fmt.Errorf("failed to delete step goal: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// scanPostgresStepGoal scans a step goal row
+func scanPostgresStepGoal(row pgx.Row) (*models.StepGoal, error) {
+	var g models.StepGoal
+	if err := row.Scan(&g.ID, &g.EffectiveFrom, &g.StepCount, &g.CreatedAt, &g.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
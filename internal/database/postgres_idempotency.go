@@ -0,0 +1,106 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nnamm/go-health-tracker/internal/models"
+)
+
+// ReserveIdempotencyKey reserves a key for the first request sent with it.
+// It returns ErrConflict while the key is reserved or holds a response that has not expired.
+func (db *PostgresDB) ReserveIdempotencyKey(ctx context.Context, userID int64, k *models.IdempotencyKey) error {
+	// A key can be reserved again once it has expired but not yet been purged
+	query := `
+		INSERT INTO idempotency_keys (user_id, idempotency_key, request_hash, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, idempotency_key) DO UPDATE
+		SET request_hash = EXCLUDED.request_hash, status_code = 0, headers = '{}', body = NULL,
+		    created_at = EXCLUDED.created_at, expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= EXCLUDED.created_at`
+
+	tag, err := db.pool.Exec(ctx, query, userID, k.Key, k.RequestHash, k.CreatedAt, k.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrConflict
+	}
+
+	return nil
+}
+
+// ReadIdempotencyKey reads a key that has not expired
+func (db *PostgresDB) ReadIdempotencyKey(ctx context.Context, userID int64, key string) (*models.IdempotencyKey, error) {
+	query := `
+		SELECT idempotency_key, request_hash, status_code, headers, body, created_at, expires_at
+		FROM idempotency_keys
+		WHERE user_id = $1 AND idempotency_key = $2 AND expires_at > $3`
+
+	var k models.IdempotencyKey
+	var header string
+	err := db.pool.QueryRow(ctx, query, userID, key, time.Now()).Scan(
+		&k.Key, &k.RequestHash, &k.StatusCode, &header, &k.Body, &k.CreatedAt, &k.ExpiresAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil // No key found, return nil without error
+		}
+		return nil, fmt.Errorf("failed to read idempotency key: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(header), &k.Header); err != nil {
+		return nil, fmt.Errorf("failed to decode headers: %w", err)
+	}
+
+	return &k, nil
+}
+
+// CompleteIdempotencyKey stores the request hash and the response of the request a key was reserved for
+func (db *PostgresDB) CompleteIdempotencyKey(ctx context.Context, userID int64, k *models.IdempotencyKey) error {
+	query := `UPDATE idempotency_keys SET request_hash = $1, status_code = $2, headers = $3, body = $4 WHERE user_id = $5 AND idempotency_key = $6`
+
+	header, err := json.Marshal(k.Header)
+	if err != nil {
+		return fmt.Errorf("failed to encode headers: %w", err)
+	}
+
+	tag, err := db.pool.Exec(ctx, query, k.RequestHash, k.StatusCode, string(header), k.Body, userID, k.Key)
+	if err != nil {
+		return fmt.Errorf("failed to complete idempotency key: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteIdempotencyKey releases a key, so that the request can be retried
+func (db *PostgresDB) DeleteIdempotencyKey(ctx context.Context, userID int64, key string) error {
+	query := `DELETE FROM idempotency_keys WHERE user_id = $1 AND idempotency_key = $2`
+
+	if _, err := db.pool.Exec(ctx, query, userID, key); err != nil {
+		return fmt.Errorf("failed to delete idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+// PurgeIdempotencyKeys deletes the keys of all users that have expired by now
+// and returns the number of deleted keys
+func (db *PostgresDB) PurgeIdempotencyKeys(ctx context.Context, now time.Time) (int64, error) {
+	query := `DELETE FROM idempotency_keys WHERE expires_at <= $1`
+
+	tag, err := db.pool.Exec(ctx, query, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge idempotency keys: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
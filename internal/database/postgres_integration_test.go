@@ -63,7 +63,7 @@ func TestPosgres_CreateHealthRecord(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			ptc.CleanupTestData(ctx, t)
 
-			result, err := ptc.DB.CreateHealthRecord(ctx, tt.input)
+			result, err := ptc.DB.CreateHealthRecord(ctx, testutils.TestUserID, tt.input)
 
 			if tt.wantError {
 				require.Error(t, err)
@@ -92,17 +92,16 @@ func TestPosgres_CreateHealthRecord_DuplicateConstraint(t *testing.T) {
 
 	// First insertion should succeed
 	firstRecord := testutils.CreateHealthRecord("2024-07-01", 8500)
-	result1, err := ptc.DB.CreateHealthRecord(ctx, firstRecord)
+	result1, err := ptc.DB.CreateHealthRecord(ctx, testutils.TestUserID, firstRecord)
 	require.NoError(t, err)
 	require.NotNil(t, result1)
 
 	// Second insertion with same date should fail
 	duplicateRecord := testutils.CreateHealthRecord("2024-07-01", 9000)
-	result2, err := ptc.DB.CreateHealthRecord(ctx, duplicateRecord)
+	result2, err := ptc.DB.CreateHealthRecord(ctx, testutils.TestUserID, duplicateRecord)
 
-	require.Error(t, err)
+	assert.ErrorIs(t, err, database.ErrConflict)
 	assert.Nil(t, result2)
-	assert.Contains(t, err.Error(), "duplicate key value violates unique constraint")
 }
 
 func TestPosgres_ReadHealthRecord(t *testing.T) {
@@ -144,7 +143,7 @@ func TestPosgres_ReadHealthRecord(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := ptc.DB.ReadHealthRecord(ctx, tt.date)
+			got, err := ptc.DB.ReadHealthRecord(ctx, testutils.TestUserID, tt.date)
 			require.NoError(t, err, "ReadHealthRecord should not return error for any valid date")
 
 			if tt.expectFound {
@@ -200,7 +199,7 @@ func TestPosgres_ReadHealthRecorsByYear(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := ptc.DB.ReadHealthRecordsByYear(ctx, tt.year)
+			got, err := ptc.DB.ReadHealthRecordsByYear(ctx, testutils.TestUserID, tt.year)
 			require.NoError(t, err, "ReadHealthRecordsByYear should not return error for any valid year")
 
 			if tt.expectFound {
@@ -261,7 +260,7 @@ func TestPosgres_ReadHealthRecorsByYearMonth(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := ptc.DB.ReadHealthRecordsByYearMonth(ctx, tt.year, tt.month)
+			got, err := ptc.DB.ReadHealthRecordsByYearMonth(ctx, testutils.TestUserID, tt.year, tt.month)
 			require.NoError(t, err, "ReadHealthRecordsByRange should not return error for any valid year")
 
 			if tt.expectFound {
@@ -275,6 +274,58 @@ func TestPosgres_ReadHealthRecorsByYearMonth(t *testing.T) {
 	}
 }
 
+func TestPosgres_ReadHealthRecordsByRange(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	ptc := testutils.SetupPostgresContainer(ctx, t)
+	defer ptc.Cleanup(ctx, t)
+
+	testRecords := testutils.CreateHealthRecords()
+	cleanup := testutils.SetupTestData(ctx, t, ptc, testRecords)
+	defer cleanup()
+
+	tests := []struct {
+		name          string
+		start         string
+		end           string // exclusive
+		expectedCount int
+	}{
+		{
+			name:          "range across month boundary",
+			start:         "2024-01-31",
+			end:           "2024-02-02",
+			expectedCount: 2,
+		},
+		{
+			name:          "range across year boundary",
+			start:         "2024-12-31",
+			end:           "2025-01-02",
+			expectedCount: 2,
+		},
+		{
+			name:          "end date is exclusive",
+			start:         "2024-02-14",
+			end:           "2024-03-01",
+			expectedCount: 1,
+		},
+		{
+			name:          "record not found",
+			start:         "2026-01-01",
+			end:           "2026-02-01",
+			expectedCount: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ptc.DB.ReadHealthRecordsByRange(ctx, testutils.TestUserID, testutils.CreateDate(tt.start), testutils.CreateDate(tt.end))
+			require.NoError(t, err, "ReadHealthRecordsByRange should not return error for a valid range")
+			assert.Len(t, got, tt.expectedCount)
+		})
+	}
+}
+
 func TestPosgres_UpdateHealthRecord(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
 	defer cancel()
@@ -330,7 +381,7 @@ func TestPosgres_UpdateHealthRecord(t *testing.T) {
 			setupRecord:  nil, // No initial record
 			updateRecord: testutils.CreateHealthRecord("2024-06-04", 8500),
 			wantError:    true,
-			errorMsg:     "record not found for date",
+			errorMsg:     "record not found",
 			description:  "Should fail when trying to update non-existing record",
 		},
 		{
@@ -364,7 +415,7 @@ func TestPosgres_UpdateHealthRecord(t *testing.T) {
 
 			// Setup initinal record if needed
 			if tt.setupRecord != nil {
-				_, err := ptc.DB.CreateHealthRecord(ctx, tt.setupRecord)
+				_, err := ptc.DB.CreateHealthRecord(ctx, testutils.TestUserID, tt.setupRecord)
 				require.NoError(t, err, "failed to setup initial record for test: %s", tt.description)
 			}
 
@@ -372,7 +423,7 @@ func TestPosgres_UpdateHealthRecord(t *testing.T) {
 			beforeUpdate := time.Now()
 
 			// Perform the update opration
-			err := ptc.DB.UpdateHealthRecord(ctx, tt.updateRecord)
+			err := ptc.DB.UpdateHealthRecord(ctx, testutils.TestUserID, tt.updateRecord)
 
 			if tt.wantError {
 				require.Error(t, err, "expected error for test case: %s", tt.description)
@@ -384,7 +435,7 @@ func TestPosgres_UpdateHealthRecord(t *testing.T) {
 
 				if tt.validate {
 					// Verify the record was updated correctly
-					updatedRecord, err := ptc.DB.ReadHealthRecord(ctx, tt.updateRecord.Date)
+					updatedRecord, err := ptc.DB.ReadHealthRecord(ctx, testutils.TestUserID, tt.updateRecord.Date)
 					require.NoError(t, err, "failed to read updated record")
 					require.NotNil(t, updatedRecord, "updated record should not be nil")
 
@@ -429,7 +480,7 @@ func TestPosgres_UpdateHealthRecord_ConcurrentUpdates(t *testing.T) {
 
 	// Setup initial record for concurrent testing
 	initialRecord := testutils.CreateHealthRecord("2024-07-01", 8500)
-	_, err := ptc.DB.CreateHealthRecord(ctx, initialRecord)
+	_, err := ptc.DB.CreateHealthRecord(ctx, testutils.TestUserID, initialRecord)
 	require.NoError(t, err, "failed to setup initial record for concurrent test")
 
 	// Create multiple update records with different step counts
@@ -455,7 +506,7 @@ func TestPosgres_UpdateHealthRecord_ConcurrentUpdates(t *testing.T) {
 		wg.Add(1)
 		go func(index int, updateRecord *models.HealthRecord) {
 			defer wg.Done()
-			errors[index] = ptc.DB.UpdateHealthRecord(ctx, updateRecord)
+			errors[index] = ptc.DB.UpdateHealthRecord(ctx, testutils.TestUserID, updateRecord)
 		}(i, update)
 	}
 
@@ -468,7 +519,7 @@ func TestPosgres_UpdateHealthRecord_ConcurrentUpdates(t *testing.T) {
 	}
 
 	// Verify final state - one of the update values should be the final value
-	finalRecord, err := ptc.DB.ReadHealthRecord(ctx, testutils.CreateDate("2024-07-01"))
+	finalRecord, err := ptc.DB.ReadHealthRecord(ctx, testutils.TestUserID, testutils.CreateDate("2024-07-01"))
 	require.NoError(t, err, "failed to read final record state")
 	require.NotNil(t, finalRecord, "final record should exist")
 
@@ -489,7 +540,7 @@ func TestPosgres_UpdateHealthRecord_ContextCancellation(t *testing.T) {
 
 	// Setup initial record
 	initialRecord := testutils.CreateHealthRecord("2024-08-01", 8500)
-	_, err := ptc.DB.CreateHealthRecord(context.Background(), initialRecord)
+	_, err := ptc.DB.CreateHealthRecord(context.Background(), testutils.TestUserID, initialRecord)
 	require.NoError(t, err, "failed to setup initial record")
 
 	// Create a context that gets canceled immediately
@@ -502,7 +553,7 @@ func TestPosgres_UpdateHealthRecord_ContextCancellation(t *testing.T) {
 	}
 
 	// Update should fail due to canceled context
-	err = ptc.DB.UpdateHealthRecord(ctx, updateRecord)
+	err = ptc.DB.UpdateHealthRecord(ctx, testutils.TestUserID, updateRecord)
 	assert.Error(t, err, "update should fail with canceled context")
 	assert.Contains(t, err.Error(), "context canceled",
 		"error should indicate context cancellation")
@@ -550,7 +601,7 @@ func TestPosgres_DeleteHealthRecord(t *testing.T) {
 			setupRecord: nil, // No initial record
 			deleteDate:  testutils.CreateDate("2024-06-04"),
 			wantError:   true,
-			errorMsg:    "record not found for date",
+			errorMsg:    "record not found",
 			description: "Should fail when trying to delete non-existing record",
 		},
 		{
@@ -558,7 +609,7 @@ func TestPosgres_DeleteHealthRecord(t *testing.T) {
 			setupRecord: testutils.CreateHealthRecord("2024-06-05", 8500),
 			deleteDate:  testutils.CreateDate("2024-06-05"),
 			wantError:   true,
-			errorMsg:    "record not found for date",
+			errorMsg:    "record not found",
 			description: "Should fail when trying to delete already deleted record (second attempt)",
 		},
 	}
@@ -570,29 +621,29 @@ func TestPosgres_DeleteHealthRecord(t *testing.T) {
 
 			// Setup initial record if needed
 			if tt.setupRecord != nil {
-				_, err := ptc.DB.CreateHealthRecord(ctx, tt.setupRecord)
+				_, err := ptc.DB.CreateHealthRecord(ctx, testutils.TestUserID, tt.setupRecord)
 				// _, err := ptc.DB.CreateHealthRecord(opCtx, tt.setupRecord)
 				require.NoError(t, err, "failed to setup initial record for test: %s", tt.description)
 
 				// Verify the record exists before description
-				existingRecord, err := ptc.DB.ReadHealthRecord(ctx, tt.setupRecord.Date)
+				existingRecord, err := ptc.DB.ReadHealthRecord(ctx, testutils.TestUserID, tt.setupRecord.Date)
 				require.NoError(t, err, "failed to verify setup record exists")
 				require.NotNil(t, existingRecord, "setup record should exist before deletion")
 			}
 
 			// For the "already deleted" test case, perform the first deletion
 			if tt.name == "fail delete record after already deleted" {
-				err := ptc.DB.DeleteHealthRecord(ctx, tt.deleteDate)
+				err := ptc.DB.DeleteHealthRecord(ctx, testutils.TestUserID, tt.deleteDate, 0)
 				require.NoError(t, err, "first deletion should succeed")
 
 				// Verify record was deleted
-				deletedRecord, err := ptc.DB.ReadHealthRecord(ctx, tt.deleteDate)
+				deletedRecord, err := ptc.DB.ReadHealthRecord(ctx, testutils.TestUserID, tt.deleteDate)
 				require.NoError(t, err, "should be able to query for deleted record")
 				assert.Nil(t, deletedRecord, "record should not exist after first deletion")
 			}
 
 			// Perform the delete operation
-			err := ptc.DB.DeleteHealthRecord(ctx, tt.deleteDate)
+			err := ptc.DB.DeleteHealthRecord(ctx, testutils.TestUserID, tt.deleteDate, 0)
 
 			if tt.wantError {
 				require.Error(t, err, "expected error for test case: %s", tt.description)
@@ -603,7 +654,7 @@ func TestPosgres_DeleteHealthRecord(t *testing.T) {
 
 				// Verify record strill doesn't exist (for non-existing record case)
 				if tt.setupRecord != nil {
-					nonExistentRecord, err := ptc.DB.ReadHealthRecord(ctx, tt.deleteDate)
+					nonExistentRecord, err := ptc.DB.ReadHealthRecord(ctx, testutils.TestUserID, tt.deleteDate)
 					require.NoError(t, err, "should be able to query for non-existent record")
 					assert.Nil(t, nonExistentRecord, "record should not exist")
 				}
@@ -611,13 +662,13 @@ func TestPosgres_DeleteHealthRecord(t *testing.T) {
 				require.NoError(t, err, "unexpected error for test case: %s", tt.description)
 
 				// Verify the record was actually deleted
-				deletedRecord, err := ptc.DB.ReadHealthRecord(ctx, tt.deleteDate)
+				deletedRecord, err := ptc.DB.ReadHealthRecord(ctx, testutils.TestUserID, tt.deleteDate)
 				require.NoError(t, err, "failed to verify record deletion")
 				assert.Nil(t, deletedRecord,
 					"record should not exist after successful deletion for test: %s", tt.description)
 
 				// Verify other records are not affected (if any exist)
-				allRecords, err := ptc.DB.ReadHealthRecordsByYear(ctx, tt.deleteDate.Year())
+				allRecords, err := ptc.DB.ReadHealthRecordsByYear(ctx, testutils.TestUserID, tt.deleteDate.Year())
 				require.NoError(t, err, "failed to read remaining records")
 
 				// Ensure the deleted record is not in the results
@@ -643,7 +694,7 @@ func TestPosgres_DeleteHealthRecord_ConcurrentDeletes(t *testing.T) {
 	testDates := []string{"2024-07-01", "2024-07-02", "2024-07-03"}
 	for _, dateStr := range testDates {
 		record := testutils.CreateHealthRecord(dateStr, 8500)
-		_, err := ptc.DB.CreateHealthRecord(ctx, record)
+		_, err := ptc.DB.CreateHealthRecord(ctx, testutils.TestUserID, record)
 		require.NoError(t, err, "failed to setup initial record for date: %s", dateStr)
 	}
 
@@ -656,7 +707,7 @@ func TestPosgres_DeleteHealthRecord_ConcurrentDeletes(t *testing.T) {
 		wg.Add(1)
 		go func(index int) {
 			defer wg.Done()
-			errors[index] = ptc.DB.DeleteHealthRecord(ctx, deleteDate)
+			errors[index] = ptc.DB.DeleteHealthRecord(ctx, testutils.TestUserID, deleteDate, 0)
 		}(i)
 	}
 
@@ -671,7 +722,7 @@ func TestPosgres_DeleteHealthRecord_ConcurrentDeletes(t *testing.T) {
 			successCount++
 		} else {
 			errorCount++
-			assert.Contains(t, err.Error(), "record not found for date",
+			assert.Contains(t, err.Error(), "record not found",
 				"concurrent deletion %d should fail with appropriate error", i+1)
 		}
 	}
@@ -680,12 +731,12 @@ func TestPosgres_DeleteHealthRecord_ConcurrentDeletes(t *testing.T) {
 	assert.Equal(t, 2, errorCount, "two concurrent deletions should fail")
 
 	// Verify the record was actually deleted
-	deletedRecord, err := ptc.DB.ReadHealthRecord(ctx, deleteDate)
+	deletedRecord, err := ptc.DB.ReadHealthRecord(ctx, testutils.TestUserID, deleteDate)
 	require.NoError(t, err, "should be able to query for deleted record")
 	assert.Nil(t, deletedRecord, "record should not exist after concurrent deletions")
 
 	// Verify other records are unaffected
-	remainingRecords, err := ptc.DB.ReadHealthRecordsByYear(ctx, 2024)
+	remainingRecords, err := ptc.DB.ReadHealthRecordsByYear(ctx, testutils.TestUserID, 2024)
 	require.NoError(t, err, "should be able to read remaining records")
 	assert.Len(t, remainingRecords, 2, "should have 2 remaining records")
 }
@@ -696,7 +747,7 @@ func TestPosgres_DeleteHealthRecord_ContextCancellation(t *testing.T) {
 
 	// Setup initial record
 	initialRecord := testutils.CreateHealthRecord("2024-08-01", 8500)
-	_, err := ptc.DB.CreateHealthRecord(context.Background(), initialRecord)
+	_, err := ptc.DB.CreateHealthRecord(context.Background(), testutils.TestUserID, initialRecord)
 	require.NoError(t, err, "failed to setup initial record")
 
 	// Create a context that gets canceled immediately
@@ -704,13 +755,13 @@ func TestPosgres_DeleteHealthRecord_ContextCancellation(t *testing.T) {
 	cancel()
 
 	// Delete should fail due to canceled context
-	err = ptc.DB.DeleteHealthRecord(ctx, initialRecord.Date)
+	err = ptc.DB.DeleteHealthRecord(ctx, testutils.TestUserID, initialRecord.Date, 0)
 	assert.Error(t, err, "delete should fail with canceled context")
 	assert.Contains(t, err.Error(), "context canceled",
 		"error should indicate context cancelation")
 
 	// Verify record still exists after failed deletion
-	existingRecord, err := ptc.DB.ReadHealthRecord(context.Background(), initialRecord.Date)
+	existingRecord, err := ptc.DB.ReadHealthRecord(context.Background(), testutils.TestUserID, initialRecord.Date)
 	require.NoError(t, err, "should be able to read record after failed deletion")
 	require.NotNil(t, existingRecord, "record should still exist after canceled deletion")
 	testutils.AssertHealthRecord(t, existingRecord, initialRecord)
@@ -732,28 +783,28 @@ func TestPosgres_DeleteHealthRecord_MultipulRecords(t *testing.T) {
 	}
 
 	for _, record := range testRecords {
-		_, err := ptc.DB.CreateHealthRecord(ctx, record)
+		_, err := ptc.DB.CreateHealthRecord(ctx, testutils.TestUserID, record)
 		require.NoError(t, err, "failed to setup record for date: %s",
 			record.Date.Format("2006-01-02"))
 	}
 
 	// Verify all records exist
-	allRecords, err := ptc.DB.ReadHealthRecordsByYearMonth(ctx, 2024, 9)
+	allRecords, err := ptc.DB.ReadHealthRecordsByYearMonth(ctx, testutils.TestUserID, 2024, 9)
 	require.NoError(t, err, "failed to read initial records")
 	assert.Len(t, allRecords, 5, "should have 5 initial records")
 
 	// Delete records on by one
 	for i, record := range testRecords {
-		err := ptc.DB.DeleteHealthRecord(ctx, record.Date)
+		err := ptc.DB.DeleteHealthRecord(ctx, testutils.TestUserID, record.Date, 0)
 		require.NoError(t, err, "failed to delete record %d", i+1)
 
 		// Verify this specific recorde was deleted
-		deletedRecord, err := ptc.DB.ReadHealthRecord(ctx, record.Date)
+		deletedRecord, err := ptc.DB.ReadHealthRecord(ctx, testutils.TestUserID, record.Date)
 		require.NoError(t, err, "should be able to query for deleted record")
 		assert.Nil(t, deletedRecord, "record %d should be deleted", i+1)
 
 		// Verify remaining count
-		remainingRecords, err := ptc.DB.ReadHealthRecordsByYearMonth(ctx, 2024, 9)
+		remainingRecords, err := ptc.DB.ReadHealthRecordsByYearMonth(ctx, testutils.TestUserID, 2024, 9)
 		require.NoError(t, err, "failed to read remaining records")
 		expectedCount := 5 - (i + 1)
 		assert.Len(t, remainingRecords, expectedCount,
@@ -761,7 +812,7 @@ func TestPosgres_DeleteHealthRecord_MultipulRecords(t *testing.T) {
 	}
 
 	// Verify no records remain
-	finalRecords, err := ptc.DB.ReadHealthRecordsByYearMonth(ctx, 2024, 9)
+	finalRecords, err := ptc.DB.ReadHealthRecordsByYearMonth(ctx, testutils.TestUserID, 2024, 9)
 	require.NoError(t, err, "failed to read final records")
 	assert.Empty(t, finalRecords, "should have no remaining records")
 }
@@ -868,7 +919,7 @@ func TestPosgres_Exec(t *testing.T) {
 
 	// Setup initial record for update/delete tests
 	initialRecord := testutils.CreateHealthRecord("2024-10-01", 1000)
-	_, err := ptc.DB.CreateHealthRecord(ctx, initialRecord)
+	_, err := ptc.DB.CreateHealthRecord(ctx, testutils.TestUserID, initialRecord)
 	require.NoError(t, err, "failed to setup initial record for exec test")
 
 	canceledCtx, cancelCtx := context.WithCancel(context.Background())
@@ -928,7 +979,7 @@ func TestPosgres_Exec(t *testing.T) {
 
 				// Additional verification for successful DML
 				if tt.name == "successful DML (UPDATE)" {
-					updatedRecord, readErr := ptc.DB.ReadHealthRecord(ctx, initialRecord.Date)
+					updatedRecord, readErr := ptc.DB.ReadHealthRecord(ctx, testutils.TestUserID, initialRecord.Date)
 					require.NoError(t, readErr, "Failed to read back record after update for verification")
 					assert.Equal(t, 2000, updatedRecord.StepCount, "Step count should be updated to 2000")
 				}
@@ -0,0 +1,171 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nnamm/go-health-tracker/internal/models"
+)
+
+const metricDefinitionColumns = `id, name, unit, value_type, min_value, max_value, enum_values, aggregation, created_at, updated_at`
+
+// CreateMetricDefinition creates a new custom metric definition
+func (db *PostgresDB) CreateMetricDefinition(ctx context.Context, userID int64, def *models.MetricDefinition) (*models.MetricDefinition, error) {
+	query := `
+		INSERT INTO metric_definitions (user_id, name, unit, value_type, min_value, max_value, enum_values, aggregation, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, created_at, updated_at`
+
+	// enum_values is NOT NULL, so store an empty array rather than NULL
+	enumValues := def.EnumValues
+	if enumValues == nil {
+		enumValues = []string{}
+	}
+
+	now := time.Now()
+	created := *def
+
+	err := db.pool.QueryRow(ctx, query, userID, def.Name, def.Unit, def.ValueType, def.Min, def.Max, enumValues, def.Aggregation, now, now).Scan(
+		&created.ID,
+		&created.CreatedAt,
+		&created.UpdatedAt,
+	)
+	if isPostgresUniqueViolation(err) {
+		return nil, ErrConflict
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create metric definition: %w", err)
+	}
+
+	return &created, nil
+}
+
+// ReadMetricDefinition reads a custom metric definition by name
+func (db *PostgresDB) ReadMetricDefinition(ctx context.Context, userID int64, name string) (*models.MetricDefinition, error) {
+	query := `SELECT ` + metricDefinitionColumns + ` FROM metric_definitions WHERE user_id = $1 AND name = $2`
+
+	def, err := scanPostgresMetricDefinition(db.pool.QueryRow(ctx, query, userID, name))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil // No definition found, return nil without error
+		}
+		return nil, fmt.Errorf("failed to read metric definition: %w", err)
+	}
+
+	return def, nil
+}
+
+// ReadMetricDefinitions reads all custom metric definitions ordered by name
+func (db *PostgresDB) ReadMetricDefinitions(ctx context.Context, userID int64) ([]models.MetricDefinition, error) {
+	query := `SELECT ` + metricDefinitionColumns + ` FROM metric_definitions WHERE user_id = $1 ORDER BY name`
+
+	rows, err := db.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query metric definitions: %w", err)
+	}
+	defer rows.Close()
+
+	var defs []models.MetricDefinition
+	for rows.Next() {
+		def, err := scanPostgresMetricDefinition(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan metric definition: %w", err)
+		}
+		defs = append(defs, *def)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating through rows: %w", err)
+	}
+
+	return defs, nil
+}
+
+// DeleteMetricDefinition deletes a custom metric definition; its values are removed by ON DELETE CASCADE
+func (db *PostgresDB) DeleteMetricDefinition(ctx context.Context, userID int64, id int64) error {
+	query := `DELETE FROM metric_definitions WHERE user_id = $1 AND id = $2`
+
+	tag, err := db.pool.Exec(ctx, query, userID, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete metric definition: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// CreateMetricValue creates a new value of a custom metric
+func (db *PostgresDB) CreateMetricValue(ctx context.Context, userID int64, mv *models.MetricValue) (*models.MetricValue, error) {
+	query := `
+		INSERT INTO metric_values (user_id, metric_id, date, recorded_at, value, enum_value, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at, updated_at`
+
+	now := time.Now()
+	created := *mv
+
+	err := db.pool.QueryRow(ctx, query, userID, mv.MetricID, mv.Date, mv.RecordedAt, mv.Value, mv.EnumValue, now, now).Scan(
+		&created.ID,
+		&created.CreatedAt,
+		&created.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create metric value: %w", err)
+	}
+
+	return &created, nil
+}
+
+// ReadMetricValuesByRange reads the values of a metric within [startDate, endDate), ordered by recording time
+func (db *PostgresDB) ReadMetricValuesByRange(ctx context.Context, userID int64, metricID int64, startDate, endDate time.Time) ([]models.MetricValue, error) {
+	query := `
+		SELECT id, metric_id, date, recorded_at, value, enum_value, created_at, updated_at
+		FROM metric_values
+		WHERE user_id = $1 AND metric_id = $2 AND date >= $3 AND date < $4
+		ORDER BY date, recorded_at`
+
+	rows, err := db.pool.Query(ctx, query, userID, metricID, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query metric values: %w", err)
+	}
+	defer rows.Close()
+
+	var values []models.MetricValue
+	for rows.Next() {
+		var mv models.MetricValue
+		if err := rows.Scan(
+			&mv.ID, &mv.MetricID, &mv.Date, &mv.RecordedAt, &mv.Value, &mv.EnumValue, &mv.CreatedAt, &mv.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan metric value: %w", err)
+		}
+		values = append(values, mv)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating through rows: %w", err)
+	}
+
+	return values, nil
+}
+
+// scanPostgresMetricDefinition scans a metric definition from a pgx row
+func scanPostgresMetricDefinition(row pgx.Row) (*models.MetricDefinition, error) {
+	var def models.MetricDefinition
+	err := row.Scan(
+		&def.ID, &def.Name, &def.Unit, &def.ValueType, &def.Min, &def.Max, &def.EnumValues, &def.Aggregation, &def.CreatedAt, &def.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(def.EnumValues) == 0 {
+		def.EnumValues = nil
+	}
+
+	return &def, nil
+}
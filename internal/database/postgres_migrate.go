@@ -0,0 +1,248 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nnamm/go-health-tracker/internal/config"
+)
+
+// migrationLockID is the key of the advisory lock held while migrating,
+// so that instances starting at the same time don't apply a migration twice
+const migrationLockID = 7_254_661_038
+
+// createPostgresMigrationsTable records the applied migrations
+const createPostgresMigrationsTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version INTEGER PRIMARY KEY,
+	name VARCHAR(255) NOT NULL,
+	applied_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+)`
+
+// MigrateUp applies all pending migrations in a single transaction under the migration lock.
+// Postgres DDL is transactional, so a failing migration leaves the schema unchanged.
+func (db *PostgresDB) MigrateUp(ctx context.Context) ([]Migration, error) {
+	var done []Migration
+	err := db.withMigrationLock(ctx, func(tx pgx.Tx, migrations []Migration, applied map[int]time.Time) error {
+		for _, m := range pendingMigrations(migrations, applied) {
+			if err := applyPostgresMigration(ctx, tx, m); err != nil {
+				return fmt.Errorf("failed to apply migration %d: %w", m.Version, err)
+			}
+			if _, err := tx.Exec(ctx, "INSERT INTO schema_migrations (version, name) VALUES ($1, $2)", m.Version, m.Name); err != nil {
+				return fmt.Errorf("failed to record migration %d: %w", m.Version, err)
+			}
+			done = append(done, m)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return done, nil
+}
+
+// MigrateDown reverts the last steps applied migrations in a single transaction under the migration lock
+func (db *PostgresDB) MigrateDown(ctx context.Context, steps int) ([]Migration, error) {
+	var done []Migration
+	err := db.withMigrationLock(ctx, func(tx pgx.Tx, migrations []Migration, applied map[int]time.Time) error {
+		revert, err := revertibleMigrations(migrations, applied, steps)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range revert {
+			if !m.Code {
+				if _, err := tx.Exec(ctx, m.Down); err != nil {
+					return fmt.Errorf("failed to revert migration %d: %w", m.Version, err)
+				}
+			}
+			if _, err := tx.Exec(ctx, "DELETE FROM schema_migrations WHERE version = $1", m.Version); err != nil {
+				return fmt.Errorf("failed to unrecord migration %d: %w", m.Version, err)
+			}
+			done = append(done, m)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return done, nil
+}
+
+// MigrationStatus lists all known migrations and when they were applied
+func (db *PostgresDB) MigrationStatus(ctx context.Context) ([]MigrationStatus, error) {
+	migrations, err := loadMigrations(dialectPostgres)
+	if err != nil {
+		return nil, err
+	}
+
+	// Before the first migration the table doesn't exist yet
+	var exists bool
+	if err := db.pool.QueryRow(ctx, "SELECT to_regclass('schema_migrations') IS NOT NULL").Scan(&exists); err != nil {
+		return nil, fmt.Errorf("failed to check migrations table: %w", err)
+	}
+	if !exists {
+		return migrationStatuses(migrations, nil), nil
+	}
+
+	rows, err := db.pool.Query(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query applied migrations: %w", err)
+	}
+	applied, err := scanAppliedMigrations(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return migrationStatuses(migrations, applied), nil
+}
+
+// withMigrationLock runs fn in a transaction holding the migration advisory lock,
+// with the Postgres migrations and the versions applied to the database
+func (db *PostgresDB) withMigrationLock(ctx context.Context, fn func(tx pgx.Tx, migrations []Migration, applied map[int]time.Time) error) error {
+	migrations, err := loadMigrations(dialectPostgres)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // no-op after commit
+
+	// Released with the transaction; a second instance waits here and then sees the migrations as applied
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", migrationLockID); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, createPostgresMigrationsTable); err != nil {
+		return fmt.Errorf("failed to create migrations table: %w", err)
+	}
+
+	rows, err := tx.Query(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return fmt.Errorf("failed to query applied migrations: %w", err)
+	}
+	applied, err := scanAppliedMigrations(rows)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx, migrations, applied); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// scanAppliedMigrations reads the applied versions and closes the rows
+func scanAppliedMigrations(rows pgx.Rows) (map[int]time.Time, error) {
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan applied migration: %w", err)
+		}
+		applied[version] = appliedAt
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate applied migrations: %w", err)
+	}
+
+	return applied, nil
+}
+
+// applyPostgresMigration runs the SQL of a migration, or its Go implementation for code migrations
+func applyPostgresMigration(ctx context.Context, tx pgx.Tx, m Migration) error {
+	if !m.Code {
+		_, err := tx.Exec(ctx, m.Up)
+		return err
+	}
+
+	switch m.Version {
+	case migrationLegacyHealthRecords:
+		return upgradePostgresLegacyHealthRecords(ctx, tx)
+	default:
+		return fmt.Errorf("code migration %d is not implemented", m.Version)
+	}
+}
+
+// upgradePostgresLegacyHealthRecords brings a health_records table created before schema migrations
+// to the shape of migration 1. A table without user_id belongs to the single-user schema: its records
+// are assigned to the default user and the unique date becomes unique per user.
+// A missing version column is added. New databases are left as they are.
+func upgradePostgresLegacyHealthRecords(ctx context.Context, tx pgx.Tx) error {
+	var columns, hasUserID, hasVersion int
+	err := tx.QueryRow(ctx, `
+		SELECT COUNT(*),
+			COUNT(*) FILTER (WHERE column_name = 'user_id'),
+			COUNT(*) FILTER (WHERE column_name = 'version')
+		FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = 'health_records'`).
+		Scan(&columns, &hasUserID, &hasVersion)
+	if err != nil {
+		return fmt.Errorf("failed to inspect health_records: %w", err)
+	}
+	if columns == 0 {
+		return nil
+	}
+
+	if hasUserID == 0 {
+		userID, err := postgresDefaultUser(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(ctx, `ALTER TABLE health_records ADD COLUMN user_id INTEGER REFERENCES users(id) ON DELETE CASCADE`); err != nil {
+			return fmt.Errorf("failed to add user_id column: %w", err)
+		}
+		if _, err := tx.Exec(ctx, `UPDATE health_records SET user_id = $1`, userID); err != nil {
+			return fmt.Errorf("failed to assign legacy health records: %w", err)
+		}
+
+		// The legacy unique constraint and index on date give way to one on user and date
+		statements := []string{
+			`ALTER TABLE health_records ALTER COLUMN user_id SET NOT NULL`,
+			`ALTER TABLE health_records DROP CONSTRAINT IF EXISTS health_records_date_key`,
+			`DROP INDEX IF EXISTS idx_health_records_date`,
+			`ALTER TABLE health_records ADD CONSTRAINT health_records_user_id_date_key UNIQUE (user_id, date)`,
+		}
+		for _, statement := range statements {
+			if _, err := tx.Exec(ctx, statement); err != nil {
+				return fmt.Errorf("failed to scope health_records by user: %w", err)
+			}
+		}
+	}
+
+	if hasVersion == 0 {
+		if _, err := tx.Exec(ctx, `ALTER TABLE health_records ADD COLUMN version BIGINT NOT NULL DEFAULT 1`); err != nil {
+			return fmt.Errorf("failed to add version column: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// postgresDefaultUser returns the ID of the user requests are attributed to without authentication,
+// creating it like the server does on start
+func postgresDefaultUser(ctx context.Context, tx pgx.Tx) (int64, error) {
+	if _, err := tx.Exec(ctx, `INSERT INTO users (email) VALUES ($1) ON CONFLICT (email) DO NOTHING`, config.DefaultUserEmail); err != nil {
+		return 0, fmt.Errorf("failed to create default user: %w", err)
+	}
+
+	var userID int64
+	if err := tx.QueryRow(ctx, `SELECT id FROM users WHERE email = $1`, config.DefaultUserEmail).Scan(&userID); err != nil {
+		return 0, fmt.Errorf("failed to read default user: %w", err)
+	}
+	return userID, nil
+}
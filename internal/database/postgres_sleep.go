@@ -0,0 +1,189 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nnamm/go-health-tracker/internal/models"
+)
+
+const sleepSessionColumns = `id, date, bed_time, wake_time, duration_minutes, quality, is_nap, created_at, updated_at`
+
+// CreateSleepSession creates a new sleep session.
+// ErrConflict is returned if it overlaps a session already stored.
+func (db *PostgresDB) CreateSleepSession(ctx context.Context, userID int64, s *models.SleepSession) (*models.SleepSession, error) {
+	query := `
+		INSERT INTO sleep_sessions (user_id, date, bed_time, wake_time, duration_minutes, quality, is_nap, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at, updated_at`
+
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // no-op after commit
+
+	if err := checkSleepSessionOverlap(ctx, tx, userID, 0, s); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	created := *s
+
+	err = tx.QueryRow(ctx, query, userID, s.Date, s.BedTime, s.WakeTime, s.DurationMinutes, s.Quality, s.IsNap, now, now).Scan(
+		&created.ID,
+		&created.CreatedAt,
+		&created.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sleep session: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &created, nil
+}
+
+// checkSleepSessionOverlap returns ErrConflict if a session other than id overlaps s.
+// It first locks the user's row, so concurrent sleep session writes of the user run one after another
+// and each sees the sessions committed before it.
+func checkSleepSessionOverlap(ctx context.Context, tx pgx.Tx, userID, id int64, s *models.SleepSession) error {
+	if _, err := tx.Exec(ctx, `SELECT 1 FROM users WHERE id = $1 FOR NO KEY UPDATE`, userID); err != nil {
+		return fmt.Errorf("failed to lock user: %w", err)
+	}
+
+	query := `
+		SELECT COUNT(*)
+		FROM sleep_sessions
+		WHERE user_id = $1 AND id != $2 AND bed_time < $3 AND wake_time > $4`
+
+	var overlapping int
+	if err := tx.QueryRow(ctx, query, userID, id, s.WakeTime, s.BedTime).Scan(&overlapping); err != nil {
+		return fmt.Errorf("failed to count overlapping sleep sessions: %w", err)
+	}
+	if overlapping > 0 {
+		return ErrConflict
+	}
+
+	return nil
+}
+
+// ReadSleepSession reads a sleep session by ID
+func (db *PostgresDB) ReadSleepSession(ctx context.Context, userID int64, id int64) (*models.SleepSession, error) {
+	query := `SELECT ` + sleepSessionColumns + ` FROM sleep_sessions WHERE user_id = $1 AND id = $2`
+
+	var s models.SleepSession
+	err := db.pool.QueryRow(ctx, query, userID, id).Scan(
+		&s.ID, &s.Date, &s.BedTime, &s.WakeTime, &s.DurationMinutes, &s.Quality, &s.IsNap, &s.CreatedAt, &s.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil // No session found, return nil without error
+		}
+		return nil, fmt.Errorf("failed to read sleep session: %w", err)
+	}
+
+	return &s, nil
+}
+
+// ReadSleepSessionsByRange reads sleep sessions whose date is within [startDate, endDate)
+func (db *PostgresDB) ReadSleepSessionsByRange(ctx context.Context, userID int64, startDate, endDate time.Time) ([]models.SleepSession, error) {
+	query := `
+		SELECT ` + sleepSessionColumns + `
+		FROM sleep_sessions
+		WHERE user_id = $1 AND date >= $2 AND date < $3
+		ORDER BY bed_time`
+
+	return db.querySleepSessions(ctx, query, userID, startDate, endDate)
+}
+
+// ReadOverlappingSleepSessions reads sleep sessions that overlap the period from bedTime to wakeTime
+func (db *PostgresDB) ReadOverlappingSleepSessions(ctx context.Context, userID int64, bedTime, wakeTime time.Time) ([]models.SleepSession, error) {
+	query := `
+		SELECT ` + sleepSessionColumns + `
+		FROM sleep_sessions
+		WHERE user_id = $1 AND bed_time < $2 AND wake_time > $3
+		ORDER BY bed_time`
+
+	return db.querySleepSessions(ctx, query, userID, wakeTime, bedTime)
+}
+
+// querySleepSessions runs a sleep session query and scans all rows
+func (db *PostgresDB) querySleepSessions(ctx context.Context, query string, args ...any) ([]models.SleepSession, error) {
+	rows, err := db.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sleep sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []models.SleepSession
+	for rows.Next() {
+		var s models.SleepSession
+		if err := rows.Scan(
+			&s.ID, &s.Date, &s.BedTime, &s.WakeTime, &s.DurationMinutes, &s.Quality, &s.IsNap, &s.CreatedAt, &s.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan sleep session: %w", err)
+		}
+		sessions = append(sessions, s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating through rows: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// UpdateSleepSession updates an existing sleep session.
+// ErrConflict is returned if the new times overlap another session.
+func (db *PostgresDB) UpdateSleepSession(ctx context.Context, userID int64, s *models.SleepSession) error {
+	query := `UPDATE sleep_sessions
+	          SET date = $1, bed_time = $2, wake_time = $3, duration_minutes = $4, quality = $5, is_nap = $6, updated_at = $7
+	          WHERE user_id = $8 AND id = $9`
+
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // no-op after commit
+
+	if err := checkSleepSessionOverlap(ctx, tx, userID, s.ID, s); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	tag, err := tx.Exec(ctx, query, s.Date, s.BedTime, s.WakeTime, s.DurationMinutes, s.Quality, s.IsNap, now, userID, s.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update sleep session: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteSleepSession deletes a sleep session
+func (db *PostgresDB) DeleteSleepSession(ctx context.Context, userID int64, id int64) error {
+	query := `DELETE FROM sleep_sessions WHERE user_id = $1 AND id = $2`
+
+	tag, err := db.pool.Exec(ctx, query, userID, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete sleep session: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
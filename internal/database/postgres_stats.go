@@ -0,0 +1,39 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/models"
+)
+
+// ReadStepCountStats computes the step count statistics of the health records within a date range
+// [startDate, endDate), per period of the grouping
+func (db *PostgresDB) ReadStepCountStats(ctx context.Context, userID int64, startDate, endDate time.Time, groupBy models.StatsGrouping) ([]models.StepCountStats, error) {
+	query, err := postgresStatsDialect.stepCountStatsQuery(groupBy)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.pool.Query(ctx, query, userID, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query step count stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []models.StepCountStats
+	for rows.Next() {
+		s, err := scanStepCountStats(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan step count stats: %w", err)
+		}
+		stats = append(stats, *s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating through rows: %w", err)
+	}
+
+	return stats, nil
+}
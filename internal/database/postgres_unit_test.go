@@ -110,6 +110,7 @@ func runCreateHealthRecordPostgresRollbackTests(t *testing.T, db database.DBInte
 			buildStubs: func(mock pgxmock.PgxPoolIface) {
 				mock.ExpectQuery("INSERT INTO health_records").
 					WithArgs(
+						testutils.TestUserID,
 						pgxmock.AnyArg(), // date
 						pgxmock.AnyArg(), // step_count
 						pgxmock.AnyArg(), // created_at
@@ -132,6 +133,7 @@ func runCreateHealthRecordPostgresRollbackTests(t *testing.T, db database.DBInte
 			buildStubs: func(mock pgxmock.PgxPoolIface) {
 				mock.ExpectQuery("INSERT INTO health_records").
 					WithArgs(
+						testutils.TestUserID,
 						pgxmock.AnyArg(), // date
 						pgxmock.AnyArg(), // step_count
 						pgxmock.AnyArg(), // created_at
@@ -158,6 +160,7 @@ func runCreateHealthRecordPostgresRollbackTests(t *testing.T, db database.DBInte
 			buildStubs: func(mock pgxmock.PgxPoolIface) {
 				mock.ExpectQuery("INSERT INTO health_records").
 					WithArgs(
+						testutils.TestUserID,
 						pgxmock.AnyArg(), // date
 						pgxmock.AnyArg(), // step_count
 						pgxmock.AnyArg(), // created_at
@@ -176,7 +179,7 @@ func runCreateHealthRecordPostgresRollbackTests(t *testing.T, db database.DBInte
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.buildStubs(mock)
-			_, err := db.CreateHealthRecord(context.Background(), tt.record)
+			_, err := db.CreateHealthRecord(context.Background(), testutils.TestUserID, tt.record)
 			tt.checkResult(t, err)
 			if err := mock.ExpectationsWereMet(); err != nil {
 				t.Errorf("there were unfulfilled expectations: %v", err)
@@ -205,7 +208,9 @@ func runUpdateHealthRecordPostgresRollbackTests(t *testing.T, db database.DBInte
 					WithArgs(
 						record.StepCount,
 						pgxmock.AnyArg(), // updated_at
+						testutils.TestUserID,
 						record.Date,
+						record.Version,
 					).
 					WillReturnError(context.Canceled)
 			},
@@ -222,7 +227,9 @@ func runUpdateHealthRecordPostgresRollbackTests(t *testing.T, db database.DBInte
 					WithArgs(
 						record.StepCount,
 						pgxmock.AnyArg(), // updated_at
+						testutils.TestUserID,
 						record.Date,
+						record.Version,
 					).
 					WillReturnError(errors.New("some database error"))
 			},
@@ -237,7 +244,7 @@ func runUpdateHealthRecordPostgresRollbackTests(t *testing.T, db database.DBInte
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.buildStubs(mock)
-			err := db.UpdateHealthRecord(context.Background(), record)
+			err := db.UpdateHealthRecord(context.Background(), testutils.TestUserID, record)
 			tt.checkResult(t, err)
 			if err := mock.ExpectationsWereMet(); err != nil {
 				t.Errorf("there were unfulfilled expectations: %v", err)
@@ -260,7 +267,7 @@ func runDeleteHealthRecordPostgresRollbackTests(t *testing.T, db database.DBInte
 			name: "delete rollback on context cancellation",
 			buildStubs: func(mock pgxmock.PgxPoolIface) {
 				mock.ExpectExec("DELETE FROM health_records").
-					WithArgs(date).
+					WithArgs(testutils.TestUserID, date, int64(0)).
 					WillReturnError(context.Canceled)
 			},
 			checkResult: func(t *testing.T, err error) {
@@ -273,7 +280,7 @@ func runDeleteHealthRecordPostgresRollbackTests(t *testing.T, db database.DBInte
 			name: "delete rollback on other database error during exec",
 			buildStubs: func(mock pgxmock.PgxPoolIface) {
 				mock.ExpectExec("DELETE FROM health_records").
-					WithArgs(date).
+					WithArgs(testutils.TestUserID, date, int64(0)).
 					WillReturnError(errors.New("some database error"))
 			},
 			checkResult: func(t *testing.T, err error) {
@@ -287,7 +294,7 @@ func runDeleteHealthRecordPostgresRollbackTests(t *testing.T, db database.DBInte
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.buildStubs(mock)
-			err := db.DeleteHealthRecord(context.Background(), date)
+			err := db.DeleteHealthRecord(context.Background(), testutils.TestUserID, date, 0)
 			tt.checkResult(t, err)
 			if err := mock.ExpectationsWereMet(); err != nil {
 				t.Errorf("there were unfulfilled expectations: %v", err)
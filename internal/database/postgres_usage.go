@@ -0,0 +1,24 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/models"
+)
+
+// ReadUsage returns the number of users and of health records written since the given time
+func (db *PostgresDB) ReadUsage(ctx context.Context, since time.Time) (*models.Usage, error) {
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM users),
+			(SELECT COUNT(*) FROM health_records WHERE updated_at >= $1)`
+
+	var usage models.Usage
+	if err := db.pool.QueryRow(ctx, query, since).Scan(&usage.Users, &usage.RecordsWritten); err != nil {
+		return nil, fmt.Errorf("failed to read usage: %w", err)
+	}
+
+	return &usage, nil
+}
@@ -0,0 +1,63 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nnamm/go-health-tracker/internal/models"
+)
+
+const userColumns = `id, email, password_hash, created_at, updated_at`
+
+// CreateUser creates a new user account
+func (db *PostgresDB) CreateUser(ctx context.Context, u *models.User) (*models.User, error) {
+	query := `
+		INSERT INTO users (email, password_hash, created_at, updated_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, updated_at`
+
+	now := time.Now()
+	created := *u
+
+	err := db.pool.QueryRow(ctx, query, u.Email, u.PasswordHash, now, now).Scan(
+		&created.ID,
+		&created.CreatedAt,
+		&created.UpdatedAt,
+	)
+	if isPostgresUniqueViolation(err) {
+		return nil, ErrConflict
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	return &created, nil
+}
+
+// ReadUser reads a user by ID
+func (db *PostgresDB) ReadUser(ctx context.Context, id int64) (*models.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = $1`
+	return db.queryUser(ctx, query, id)
+}
+
+// ReadUserByEmail reads a user by email address
+func (db *PostgresDB) ReadUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE email = $1`
+	return db.queryUser(ctx, query, email)
+}
+
+// queryUser runs a single-user query and scans the row
+func (db *PostgresDB) queryUser(ctx context.Context, query string, arg any) (*models.User, error) {
+	var u models.User
+	err := db.pool.QueryRow(ctx, query, arg).Scan(&u.ID, &u.Email, &u.PasswordHash, &u.CreatedAt, &u.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil // No user found, return nil without error
+		}
+		return nil, fmt.Errorf("failed to read user: %w", err)
+	}
+
+	return &u, nil
+}
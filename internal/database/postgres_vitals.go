@@ -0,0 +1,115 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/models"
+)
+
+// CreateBloodPressureReading creates a new blood pressure reading
+func (db *PostgresDB) CreateBloodPressureReading(ctx context.Context, userID int64, bp *models.BloodPressureReading) (*models.BloodPressureReading, error) {
+	query := `
+		INSERT INTO blood_pressure_readings (user_id, date, measured_at, systolic, diastolic, pulse, context, arm, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, created_at, updated_at`
+
+	now := time.Now()
+	created := *bp
+
+	err := db.pool.QueryRow(ctx, query, userID, bp.Date, bp.MeasuredAt, bp.Systolic, bp.Diastolic, bp.Pulse, bp.Context, bp.Arm, now, now).Scan(
+		&created.ID,
+		&created.CreatedAt,
+		&created.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create blood pressure reading: %w", err)
+	}
+
+	return &created, nil
+}
+
+// ReadBloodPressureReadingsByRange reads blood pressure readings within [startDate, endDate), ordered by measurement time
+func (db *PostgresDB) ReadBloodPressureReadingsByRange(ctx context.Context, userID int64, startDate, endDate time.Time) ([]models.BloodPressureReading, error) {
+	query := `
+		SELECT id, date, measured_at, systolic, diastolic, pulse, context, arm, created_at, updated_at
+		FROM blood_pressure_readings
+		WHERE user_id = $1 AND date >= $2 AND date < $3
+		ORDER BY date, measured_at`
+
+	rows, err := db.pool.Query(ctx, query, userID, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query blood pressure readings: %w", err)
+	}
+	defer rows.Close()
+
+	var readings []models.BloodPressureReading
+	for rows.Next() {
+		var bp models.BloodPressureReading
+		if err := rows.Scan(
+			&bp.ID, &bp.Date, &bp.MeasuredAt, &bp.Systolic, &bp.Diastolic, &bp.Pulse, &bp.Context, &bp.Arm, &bp.CreatedAt, &bp.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan blood pressure reading: %w", err)
+		}
+		readings = append(readings, bp)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating through rows: %w", err)
+	}
+
+	return readings, nil
+}
+
+// CreateHeartRateReading creates a new heart rate reading
+func (db *PostgresDB) CreateHeartRateReading(ctx context.Context, userID int64, hr *models.HeartRateReading) (*models.HeartRateReading, error) {
+	query := `
+		INSERT INTO heart_rate_readings (user_id, date, measured_at, bpm, context, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at, updated_at`
+
+	now := time.Now()
+	created := *hr
+
+	err := db.pool.QueryRow(ctx, query, userID, hr.Date, hr.MeasuredAt, hr.BPM, hr.Context, now, now).Scan(
+		&created.ID,
+		&created.CreatedAt,
+		&created.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create heart rate reading: %w", err)
+	}
+
+	return &created, nil
+}
+
+// ReadHeartRateReadingsByRange reads heart rate readings within [startDate, endDate), ordered by measurement time
+func (db *PostgresDB) ReadHeartRateReadingsByRange(ctx context.Context, userID int64, startDate, endDate time.Time) ([]models.HeartRateReading, error) {
+	query := `
+		SELECT id, date, measured_at, bpm, context, created_at, updated_at
+		FROM heart_rate_readings
+		WHERE user_id = $1 AND date >= $2 AND date < $3
+		ORDER BY date, measured_at`
+
+	rows, err := db.pool.Query(ctx, query, userID, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query heart rate readings: %w", err)
+	}
+	defer rows.Close()
+
+	var readings []models.HeartRateReading
+	for rows.Next() {
+		var hr models.HeartRateReading
+		if err := rows.Scan(&hr.ID, &hr.Date, &hr.MeasuredAt, &hr.BPM, &hr.Context, &hr.CreatedAt, &hr.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan heart rate reading: %w", err)
+		}
+		readings = append(readings, hr)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating through rows: %w", err)
+	}
+
+	return readings, nil
+}
@@ -0,0 +1,113 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nnamm/go-health-tracker/internal/models"
+)
+
+// CreateWeightReading creates a new weight reading
+func (db *PostgresDB) CreateWeightReading(ctx context.Context, userID int64, wr *models.WeightReading) (*models.WeightReading, error) {
+	query := `
+		INSERT INTO weight_readings (user_id, date, measured_at, weight_kg, body_fat_percent, muscle_mass_kg, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at, updated_at`
+
+	now := time.Now()
+	created := *wr
+
+	err := db.pool.QueryRow(ctx, query, userID, wr.Date, wr.MeasuredAt, wr.WeightKg, wr.BodyFatPercent, wr.MuscleMassKg, now, now).Scan(
+		&created.ID,
+		&created.CreatedAt,
+		&created.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create weight reading: %w", err)
+	}
+
+	return &created, nil
+}
+
+// ReadWeightReadingsByRange reads weight readings within [startDate, endDate), ordered by measurement time
+func (db *PostgresDB) ReadWeightReadingsByRange(ctx context.Context, userID int64, startDate, endDate time.Time) ([]models.WeightReading, error) {
+	query := `
+		SELECT id, date, measured_at, weight_kg, body_fat_percent, muscle_mass_kg, created_at, updated_at
+		FROM weight_readings
+		WHERE user_id = $1 AND date >= $2 AND date < $3
+		ORDER BY date, measured_at`
+
+	rows, err := db.pool.Query(ctx, query, userID, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query weight readings: %w", err)
+	}
+	defer rows.Close()
+
+	var readings []models.WeightReading
+	for rows.Next() {
+		var wr models.WeightReading
+		if err := rows.Scan(
+			&wr.ID, &wr.Date, &wr.MeasuredAt, &wr.WeightKg, &wr.BodyFatPercent, &wr.MuscleMassKg, &wr.CreatedAt, &wr.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan weight reading: %w", err)
+		}
+		readings = append(readings, wr)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating through rows: %w", err)
+	}
+
+	return readings, nil
+}
+
+// DeleteWeightReading deletes a weight reading
+func (db *PostgresDB) DeleteWeightReading(ctx context.Context, userID int64, id int64) error {
+	query := `DELETE FROM weight_readings WHERE user_id = $1 AND id = $2`
+
+	tag, err := db.pool.Exec(ctx, query, userID, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete weight reading: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// ReadHeightProfile reads the stored height profile
+func (db *PostgresDB) ReadHeightProfile(ctx context.Context, userID int64) (*models.HeightProfile, error) {
+	query := `SELECT height_cm, updated_at FROM height_profiles WHERE user_id = $1`
+
+	var hp models.HeightProfile
+	err := db.pool.QueryRow(ctx, query, userID).Scan(&hp.HeightCm, &hp.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil // No profile stored yet, return nil without error
+		}
+		return nil, fmt.Errorf("failed to read height profile: %w", err)
+	}
+
+	return &hp, nil
+}
+
+// UpsertHeightProfile creates or replaces the height profile
+func (db *PostgresDB) UpsertHeightProfile(ctx context.Context, userID int64, hp *models.HeightProfile) (*models.HeightProfile, error) {
+	query := `
+		INSERT INTO height_profiles (user_id, height_cm, updated_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE SET height_cm = EXCLUDED.height_cm, updated_at = EXCLUDED.updated_at
+		RETURNING height_cm, updated_at`
+
+	var stored models.HeightProfile
+	err := db.pool.QueryRow(ctx, query, userID, hp.HeightCm, time.Now()).Scan(&stored.HeightCm, &stored.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upsert height profile: %w", err)
+	}
+
+	return &stored, nil
+}
@@ -3,7 +3,9 @@ package database
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"iter"
 	"sync"
 	"time"
 
@@ -17,63 +19,125 @@ type SQLiteDB struct {
 	Mu    sync.RWMutex
 }
 
-// NewSQLiteDB opens the DB
+// NewSQLiteDB opens the DB and applies pending schema migrations
 func NewSQLiteDB(dataSourceName string) (*SQLiteDB, error) {
-	sqlDB, err := sql.Open("sqlite3", dataSourceName)
+	db, err := OpenSQLiteDB(dataSourceName)
 	if err != nil {
 		return nil, err
 	}
-	if err = sqlDB.Ping(); err != nil {
-		return nil, err
-	}
 
-	db := &SQLiteDB{
-		DB:    sqlDB,
-		Stmts: make(map[string]*sql.Stmt),
-		Mu:    sync.RWMutex{},
-	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
 
-	if err := db.CreateTable(); err != nil {
-		return nil, fmt.Errorf("creating table: %w", err)
+	if _, err := db.MigrateUp(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrating schema: %w", err)
 	}
 
 	if err := db.prepareStatements(); err != nil {
+		db.Close()
 		return nil, fmt.Errorf("preparing statements: %w", err)
 	}
 
 	return db, nil
 }
 
-// CreateTable inisializes the table
-func (db *SQLiteDB) CreateTable() error {
-	queries := []string{
-		`CREATE TABLE IF NOT EXISTS health_records (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			date DATE NOT NULL UNIQUE,
-			step_count INTEGER NOT NULL,
-			created_at DATETIME NOT NULL,
-			updated_at DATETIME NOT NULL
-	    )`,
-		`CREATE UNIQUE INDEX IF NOT EXISTS idx_health_records_date
-         on health_records(date)`,
-	}
-
-	for _, query := range queries {
-		if _, err := db.Exec(query); err != nil {
-			return err
-		}
+// OpenSQLiteDB opens the DB without touching the schema, e.g. to run migrations
+func OpenSQLiteDB(dataSourceName string) (*SQLiteDB, error) {
+	sqlDB, err := sql.Open("sqlite3", dataSourceName)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	if err = sqlDB.Ping(); err != nil {
+		sqlDB.Close()
+		return nil, err
+	}
+
+	return &SQLiteDB{
+		DB:    sqlDB,
+		Stmts: make(map[string]*sql.Stmt),
+		Mu:    sync.RWMutex{},
+	}, nil
 }
 
 // PrepareStatements prepares SQL statements
 func (db *SQLiteDB) prepareStatements() error {
 	queries := map[string]string{
-		"insert_health_record":       `INSERT INTO health_records (date, step_count, created_at, updated_at) VALUES (?, ?, ?, ?)`,
-		"select_health_record":       `SELECT id, date, step_count, created_at, updated_at FROM health_records WHERE date = ?`,
-		"select_range_health_record": `SELECT id, date, step_count, created_at, updated_at FROM health_records WHERE date >= ? AND date < ? ORDER BY date`,
-		"update_health_record":       `UPDATE health_records SET step_count = ?, updated_at = ? WHERE date = ?`,
-		"delete_health_record":       `DELETE FROM health_records WHERE date = ?`,
+		"insert_user":          `INSERT INTO users (email, password_hash, created_at, updated_at) VALUES (?, ?, ?, ?)`,
+		"select_user":          `SELECT id, email, password_hash, created_at, updated_at FROM users WHERE id = ?`,
+		"select_user_by_email": `SELECT id, email, password_hash, created_at, updated_at FROM users WHERE email = ?`,
+
+		"insert_api_key":         `INSERT INTO api_keys (user_id, name, prefix, key_hash, scope, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		"select_api_keys":        `SELECT id, user_id, name, prefix, key_hash, scope, last_used_at, revoked_at, created_at, updated_at FROM api_keys WHERE user_id = ? ORDER BY id`,
+		"select_api_key_by_hash": `SELECT id, user_id, name, prefix, key_hash, scope, last_used_at, revoked_at, created_at, updated_at FROM api_keys WHERE key_hash = ?`,
+		"update_api_key_name":    `UPDATE api_keys SET name = ?, updated_at = ? WHERE user_id = ? AND id = ?`,
+		"revoke_api_key":         `UPDATE api_keys SET revoked_at = ?, updated_at = ? WHERE user_id = ? AND id = ? AND revoked_at IS NULL`,
+		"touch_api_key":          `UPDATE api_keys SET last_used_at = ? WHERE id = ?`,
+
+		"insert_health_record":       `INSERT INTO health_records (user_id, date, step_count, created_at, updated_at) VALUES (?, ?, ?, ?, ?)`,
+		"select_health_record":       `SELECT id, date, step_count, version, created_at, updated_at FROM health_records WHERE user_id = ? AND date = ?`,
+		"select_range_health_record": `SELECT id, date, step_count, version, created_at, updated_at FROM health_records WHERE user_id = ? AND date >= ? AND date < ? ORDER BY date`,
+		"count_range_health_record":  `SELECT COUNT(*) FROM health_records WHERE user_id = ? AND date >= ? AND date < ?`,
+		"update_health_record":       `UPDATE health_records SET step_count = ?, updated_at = ?, version = version + 1 WHERE user_id = ? AND date = ? AND (? = 0 OR version = ?)`,
+		"upsert_health_record":       `INSERT INTO health_records (user_id, date, step_count, created_at, updated_at) VALUES (?, ?, ?, ?, ?) ON CONFLICT (user_id, date) DO UPDATE SET step_count = excluded.step_count, updated_at = excluded.updated_at, version = health_records.version + 1 RETURNING id, date, step_count, version, created_at, updated_at`,
+		"insert_new_health_record":   `INSERT INTO health_records (user_id, date, step_count, created_at, updated_at) VALUES (?, ?, ?, ?, ?) ON CONFLICT (user_id, date) DO NOTHING RETURNING id, date, step_count, version, created_at, updated_at`,
+		"delete_health_record":       `DELETE FROM health_records WHERE user_id = ? AND date = ? AND (? = 0 OR version = ?)`,
+
+		"upsert_step_goal":  `INSERT INTO step_goals (user_id, effective_from, step_count, created_at, updated_at) VALUES (?, ?, ?, ?, ?) ON CONFLICT (user_id, effective_from) DO UPDATE SET step_count = excluded.step_count, updated_at = excluded.updated_at RETURNING id, effective_from, step_count, created_at, updated_at`,
+		"select_step_goals": `SELECT id, effective_from, step_count, created_at, updated_at FROM step_goals WHERE user_id = ? ORDER BY effective_from`,
+		"delete_step_goal":  `DELETE FROM step_goals WHERE user_id = ? AND effective_from = ?`,
+
+		"insert_sleep_session":         `INSERT INTO sleep_sessions (user_id, date, bed_time, wake_time, duration_minutes, quality, is_nap, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		"select_sleep_session":         `SELECT id, date, bed_time, wake_time, duration_minutes, quality, is_nap, created_at, updated_at FROM sleep_sessions WHERE user_id = ? AND id = ?`,
+		"select_range_sleep_session":   `SELECT id, date, bed_time, wake_time, duration_minutes, quality, is_nap, created_at, updated_at FROM sleep_sessions WHERE user_id = ? AND date >= ? AND date < ? ORDER BY bed_time`,
+		"select_overlap_sleep_session": `SELECT id, date, bed_time, wake_time, duration_minutes, quality, is_nap, created_at, updated_at FROM sleep_sessions WHERE user_id = ? AND bed_time < ? AND wake_time > ? ORDER BY bed_time`,
+		"count_overlap_sleep_session":  `SELECT COUNT(*) FROM sleep_sessions WHERE user_id = ? AND id != ? AND bed_time < ? AND wake_time > ?`,
+		"update_sleep_session":         `UPDATE sleep_sessions SET date = ?, bed_time = ?, wake_time = ?, duration_minutes = ?, quality = ?, is_nap = ?, updated_at = ? WHERE user_id = ? AND id = ?`,
+		"delete_sleep_session":         `DELETE FROM sleep_sessions WHERE user_id = ? AND id = ?`,
+
+		"insert_weight_reading":       `INSERT INTO weight_readings (user_id, date, measured_at, weight_kg, body_fat_percent, muscle_mass_kg, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		"select_range_weight_reading": `SELECT id, date, measured_at, weight_kg, body_fat_percent, muscle_mass_kg, created_at, updated_at FROM weight_readings WHERE user_id = ? AND date >= ? AND date < ? ORDER BY date, measured_at`,
+		"delete_weight_reading":       `DELETE FROM weight_readings WHERE user_id = ? AND id = ?`,
+		"select_height_profile":       `SELECT height_cm, updated_at FROM height_profiles WHERE user_id = ?`,
+		"upsert_height_profile":       `INSERT INTO height_profiles (user_id, height_cm, updated_at) VALUES (?, ?, ?) ON CONFLICT (user_id) DO UPDATE SET height_cm = excluded.height_cm, updated_at = excluded.updated_at`,
+
+		"insert_blood_pressure_reading":       `INSERT INTO blood_pressure_readings (user_id, date, measured_at, systolic, diastolic, pulse, context, arm, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		"select_range_blood_pressure_reading": `SELECT id, date, measured_at, systolic, diastolic, pulse, context, arm, created_at, updated_at FROM blood_pressure_readings WHERE user_id = ? AND date >= ? AND date < ? ORDER BY date, measured_at`,
+		"insert_heart_rate_reading":           `INSERT INTO heart_rate_readings (user_id, date, measured_at, bpm, context, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		"select_range_heart_rate_reading":     `SELECT id, date, measured_at, bpm, context, created_at, updated_at FROM heart_rate_readings WHERE user_id = ? AND date >= ? AND date < ? ORDER BY date, measured_at`,
+
+		"insert_metric_definition":   `INSERT INTO metric_definitions (user_id, name, unit, value_type, min_value, max_value, enum_values, aggregation, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		"select_metric_definition":   `SELECT id, name, unit, value_type, min_value, max_value, enum_values, aggregation, created_at, updated_at FROM metric_definitions WHERE user_id = ? AND name = ?`,
+		"select_metric_definitions":  `SELECT id, name, unit, value_type, min_value, max_value, enum_values, aggregation, created_at, updated_at FROM metric_definitions WHERE user_id = ? ORDER BY name`,
+		"delete_metric_definition":   `DELETE FROM metric_definitions WHERE user_id = ? AND id = ?`,
+		"delete_metric_values":       `DELETE FROM metric_values WHERE user_id = ? AND metric_id = ?`,
+		"insert_metric_value":        `INSERT INTO metric_values (user_id, metric_id, date, recorded_at, value, enum_value, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		"select_range_metric_values": `SELECT id, metric_id, date, recorded_at, value, enum_value, created_at, updated_at FROM metric_values WHERE user_id = ? AND metric_id = ? AND date >= ? AND date < ? ORDER BY date, recorded_at`,
+
+		// A key can be reserved again once it has expired but not yet been purged
+		"reserve_idempotency_key":  `INSERT INTO idempotency_keys (user_id, idempotency_key, request_hash, created_at, expires_at) VALUES (?, ?, ?, ?, ?) ON CONFLICT (user_id, idempotency_key) DO UPDATE SET request_hash = excluded.request_hash, status_code = 0, headers = '{}', body = NULL, created_at = excluded.created_at, expires_at = excluded.expires_at WHERE idempotency_keys.expires_at <= excluded.created_at`,
+		"select_idempotency_key":   `SELECT idempotency_key, request_hash, status_code, headers, body, created_at, expires_at FROM idempotency_keys WHERE user_id = ? AND idempotency_key = ? AND expires_at > ?`,
+		"complete_idempotency_key": `UPDATE idempotency_keys SET request_hash = ?, status_code = ?, headers = ?, body = ? WHERE user_id = ? AND idempotency_key = ?`,
+		"delete_idempotency_key":   `DELETE FROM idempotency_keys WHERE user_id = ? AND idempotency_key = ?`,
+		"purge_idempotency_keys":   `DELETE FROM idempotency_keys WHERE expires_at <= ?`,
+
+		"select_usage": `SELECT (SELECT COUNT(*) FROM users), (SELECT COUNT(*) FROM health_records WHERE updated_at >= ?)`,
+	}
+
+	// One statement per sort order, for the first and the following pages
+	for sort, order := range recordPageOrders {
+		base := `SELECT id, date, step_count, version, created_at, updated_at FROM health_records WHERE user_id = ? AND date >= ? AND date < ?`
+		queries[pageStmtName(sort, false)] = base + ` ORDER BY ` + order.orderBy + ` LIMIT ?`
+		queries[pageStmtName(sort, true)] = base + ` AND ` + order.sqliteAfter + ` ORDER BY ` + order.orderBy + ` LIMIT ?`
+	}
+
+	// One statistics statement per grouping
+	for _, groupBy := range []models.StatsGrouping{models.StatsGroupNone, models.StatsGroupDay, models.StatsGroupWeek, models.StatsGroupMonth, models.StatsGroupYear} {
+		query, err := sqliteStatsDialect.stepCountStatsQuery(groupBy)
+		if err != nil {
+			return err
+		}
+		queries[statsStmtName(groupBy)] = query
 	}
 
 	db.Mu.Lock()
@@ -118,6 +182,35 @@ func (db *SQLiteDB) Close() error {
 	return db.DB.Close()
 }
 
+// HealthCheck checks that the DB can execute a query.
+// It returns ErrPoolExhausted while a connection limit is set and every connection is in use.
+func (db *SQLiteDB) HealthCheck(ctx context.Context) error {
+	if stats := db.DB.Stats(); stats.MaxOpenConnections > 0 && stats.InUse >= stats.MaxOpenConnections {
+		return ErrPoolExhausted
+	}
+
+	var result int
+	if err := db.QueryRowContext(ctx, "SELECT 1").Scan(&result); err != nil {
+		return fmt.Errorf("database query test failed: %w", err)
+	}
+
+	return nil
+}
+
+// GetPoolInfo returns the connection statistics of the DB for debugging
+func (db *SQLiteDB) GetPoolInfo() map[string]any {
+	stats := db.DB.Stats()
+	return map[string]any{
+		"status":               "active",
+		"open_connections":     stats.OpenConnections,
+		"in_use_connections":   stats.InUse,
+		"idle_connections":     stats.Idle,
+		"max_open_connections": stats.MaxOpenConnections,
+		"wait_count":           stats.WaitCount,
+		"wait_duration":        stats.WaitDuration,
+	}
+}
+
 // withTxContext executes a function with a transaction and context
 func (db *SQLiteDB) withTxContext(ctx context.Context, fn func(*sql.Tx) error) error {
 	// Start a transaction for the context
@@ -154,7 +247,7 @@ func (db *SQLiteDB) withTxContext(ctx context.Context, fn func(*sql.Tx) error) e
 }
 
 // CreateHealthRecord inserts a new record
-func (db *SQLiteDB) CreateHealthRecord(ctx context.Context, hr *models.HealthRecord) (*models.HealthRecord, error) {
+func (db *SQLiteDB) CreateHealthRecord(ctx context.Context, userID int64, hr *models.HealthRecord) (*models.HealthRecord, error) {
 	insertStmt, err := db.getStmt("insert_health_record")
 	if err != nil {
 		return nil, fmt.Errorf("getting insert statement: %w", err)
@@ -165,7 +258,10 @@ func (db *SQLiteDB) CreateHealthRecord(ctx context.Context, hr *models.HealthRec
 		stmt := tx.StmtContext(ctx, insertStmt)
 
 		now := time.Now()
-		result, err := stmt.ExecContext(ctx, hr.Date, hr.StepCount, now, now)
+		result, err := stmt.ExecContext(ctx, userID, hr.Date, hr.StepCount, now, now)
+		if isSQLiteUniqueViolation(err) {
+			return ErrConflict
+		}
 		if err != nil {
 			return fmt.Errorf("insert record: %w", err)
 		}
@@ -179,6 +275,7 @@ func (db *SQLiteDB) CreateHealthRecord(ctx context.Context, hr *models.HealthRec
 			ID:        id,
 			Date:      hr.Date,
 			StepCount: hr.StepCount,
+			Version:   1,
 			CreatedAt: now,
 			UpdatedAt: now,
 		}
@@ -193,14 +290,14 @@ func (db *SQLiteDB) CreateHealthRecord(ctx context.Context, hr *models.HealthRec
 }
 
 // ReadHealthRecord retrieves a health record by date
-func (db *SQLiteDB) ReadHealthRecord(ctx context.Context, date time.Time) (*models.HealthRecord, error) {
+func (db *SQLiteDB) ReadHealthRecord(ctx context.Context, userID int64, date time.Time) (*models.HealthRecord, error) {
 	selectStmt, err := db.getStmt("select_health_record")
 	if err != nil {
 		return nil, fmt.Errorf("getting select statement: %w", err)
 	}
 
 	hr := &models.HealthRecord{}
-	err = selectStmt.QueryRowContext(ctx, date).Scan(&hr.ID, &hr.Date, &hr.StepCount, &hr.CreatedAt, &hr.UpdatedAt)
+	err = selectStmt.QueryRowContext(ctx, userID, date).Scan(&hr.ID, &hr.Date, &hr.StepCount, &hr.Version, &hr.CreatedAt, &hr.UpdatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil // No error, but no record found
@@ -212,27 +309,27 @@ func (db *SQLiteDB) ReadHealthRecord(ctx context.Context, date time.Time) (*mode
 }
 
 // ReadHealthRecordsByYear retrieves record(s) by year
-func (db *SQLiteDB) ReadHealthRecordsByYear(ctx context.Context, year int) ([]models.HealthRecord, error) {
+func (db *SQLiteDB) ReadHealthRecordsByYear(ctx context.Context, userID int64, year int) ([]models.HealthRecord, error) {
 	startDate := time.Date(year, time.Month(1), 1, 0, 0, 0, 0, time.UTC)
 	endDate := startDate.AddDate(1, 0, 0)
-	return db.readHealthRecordsByRange(ctx, startDate, endDate)
+	return db.ReadHealthRecordsByRange(ctx, userID, startDate, endDate)
 }
 
 // ReadHealthRecordsByYearMonth retrieves record(s) by year and month
-func (db *SQLiteDB) ReadHealthRecordsByYearMonth(ctx context.Context, year, month int) ([]models.HealthRecord, error) {
+func (db *SQLiteDB) ReadHealthRecordsByYearMonth(ctx context.Context, userID int64, year, month int) ([]models.HealthRecord, error) {
 	startDate := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
 	endDate := startDate.AddDate(0, 1, 0)
-	return db.readHealthRecordsByRange(ctx, startDate, endDate)
+	return db.ReadHealthRecordsByRange(ctx, userID, startDate, endDate)
 }
 
-// readHealthRecordsByRange retrieves records between startDate and endDate
-func (db *SQLiteDB) readHealthRecordsByRange(ctx context.Context, startDate, endDate time.Time) ([]models.HealthRecord, error) {
+// ReadHealthRecordsByRange retrieves records between startDate (inclusive) and endDate (exclusive)
+func (db *SQLiteDB) ReadHealthRecordsByRange(ctx context.Context, userID int64, startDate, endDate time.Time) ([]models.HealthRecord, error) {
 	selectStmt, err := db.getStmt("select_range_health_record")
 	if err != nil {
 		return nil, fmt.Errorf("getting select_range statement: %w", err)
 	}
 
-	rows, err := selectStmt.QueryContext(ctx, startDate, endDate)
+	rows, err := selectStmt.QueryContext(ctx, userID, startDate, endDate)
 	if err != nil {
 		return nil, fmt.Errorf("query records: %w", err)
 	}
@@ -241,7 +338,7 @@ func (db *SQLiteDB) readHealthRecordsByRange(ctx context.Context, startDate, end
 	var records []models.HealthRecord
 	for rows.Next() {
 		var hr models.HealthRecord
-		if err := rows.Scan(&hr.ID, &hr.Date, &hr.StepCount, &hr.CreatedAt, &hr.UpdatedAt); err != nil {
+		if err := rows.Scan(&hr.ID, &hr.Date, &hr.StepCount, &hr.Version, &hr.CreatedAt, &hr.UpdatedAt); err != nil {
 			return nil, fmt.Errorf("scan record: %w", err)
 		}
 		records = append(records, hr)
@@ -254,8 +351,66 @@ func (db *SQLiteDB) readHealthRecordsByRange(ctx context.Context, startDate, end
 	return records, nil
 }
 
-// UpdateHealthRecord updates an existing health record
-func (db *SQLiteDB) UpdateHealthRecord(ctx context.Context, hr *models.HealthRecord) error {
+// ReadHealthRecordsPage retrieves one page of the records between startDate (inclusive) and endDate (exclusive)
+func (db *SQLiteDB) ReadHealthRecordsPage(ctx context.Context, userID int64, startDate, endDate time.Time, page models.PageRequest) (*models.HealthRecordPage, error) {
+	order, ok := recordPageOrders[page.Sort]
+	if !ok {
+		return nil, fmt.Errorf("unsupported sort: %s", page.Sort)
+	}
+
+	countStmt, err := db.getStmt("count_range_health_record")
+	if err != nil {
+		return nil, fmt.Errorf("getting count statement: %w", err)
+	}
+	selectStmt, err := db.getStmt(pageStmtName(page.Sort, page.After != nil))
+	if err != nil {
+		return nil, fmt.Errorf("getting select_page statement: %w", err)
+	}
+
+	var total int
+	if err := countStmt.QueryRowContext(ctx, userID, startDate, endDate).Scan(&total); err != nil {
+		return nil, fmt.Errorf("count records: %w", err)
+	}
+
+	args := []any{userID, startDate, endDate}
+	if page.After != nil {
+		args = append(args, order.cursorArgs(page.After)...)
+	}
+	args = append(args, page.Limit+1)
+
+	rows, err := selectStmt.QueryContext(ctx, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query records: %w", err)
+	}
+	defer rows.Close()
+
+	var records []models.HealthRecord
+	for rows.Next() {
+		var hr models.HealthRecord
+		if err := rows.Scan(&hr.ID, &hr.Date, &hr.StepCount, &hr.Version, &hr.CreatedAt, &hr.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan record: %w", err)
+		}
+		records = append(records, hr)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating through rows: %w", err)
+	}
+
+	return pageRecords(records, page, total), nil
+}
+
+// pageStmtName returns the name of the prepared page statement for a sort order
+func pageStmtName(sort models.RecordSort, afterCursor bool) string {
+	if afterCursor {
+		return "select_page_after_health_record_" + string(sort)
+	}
+	return "select_page_health_record_" + string(sort)
+}
+
+// UpdateHealthRecord updates an existing health record.
+// A non-zero hr.Version must match the stored version, otherwise ErrVersionMismatch is returned.
+func (db *SQLiteDB) UpdateHealthRecord(ctx context.Context, userID int64, hr *models.HealthRecord) error {
 	updateStmt, err := db.getStmt("update_health_record")
 	if err != nil {
 		return fmt.Errorf("getting update statement: %w", err)
@@ -264,28 +419,175 @@ func (db *SQLiteDB) UpdateHealthRecord(ctx context.Context, hr *models.HealthRec
 	return db.withTxContext(ctx, func(tx *sql.Tx) error {
 		// check if record exists
 		var exists bool
-		err := tx.QueryRowContext(ctx, "SELECT 1 FROM health_records WHERE date = ?", hr.Date).Scan(&exists)
+		err := tx.QueryRowContext(ctx, "SELECT 1 FROM health_records WHERE user_id = ? AND date = ?", userID, hr.Date).Scan(&exists)
+		if err == sql.ErrNoRows {
+			return ErrNotFound
+		}
 		if err != nil {
 			return fmt.Errorf("check existence: %w", err)
 		}
-		if !exists {
-			return sql.ErrNoRows
-		}
 
 		// Update
 		stmt := tx.StmtContext(ctx, updateStmt)
 		now := time.Now()
-		_, err = stmt.ExecContext(ctx, hr.StepCount, now, hr.Date)
+		result, err := stmt.ExecContext(ctx, hr.StepCount, now, userID, hr.Date, hr.Version, hr.Version)
 		if err != nil {
 			return fmt.Errorf("execute update %w", err)
 		}
 
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("get rows affected: %w", err)
+		}
+		if affected == 0 {
+			return ErrVersionMismatch
+		}
+
+		return nil
+	})
+}
+
+// UpsertHealthRecord creates the record of a date or replaces the step count of the stored one.
+// created reports whether the record was created.
+func (db *SQLiteDB) UpsertHealthRecord(ctx context.Context, userID int64, hr *models.HealthRecord) (*models.HealthRecord, bool, error) {
+	upsertStmt, err := db.getStmt("upsert_health_record")
+	if err != nil {
+		return nil, false, fmt.Errorf("getting upsert statement: %w", err)
+	}
+
+	var stored models.HealthRecord
+	err = db.withTxContext(ctx, func(tx *sql.Tx) error {
+		stmt := tx.StmtContext(ctx, upsertStmt)
+
+		now := time.Now()
+		err := stmt.QueryRowContext(ctx, userID, hr.Date, hr.StepCount, now, now).Scan(
+			&stored.ID,
+			&stored.Date,
+			&stored.StepCount,
+			&stored.Version,
+			&stored.CreatedAt,
+			&stored.UpdatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("upsert health record: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Every replace increments the version, so only a new record is at version 1
+	return &stored, stored.Version == 1, nil
+}
+
+// UpsertHealthRecords creates or replaces the records of their dates in a single transaction.
+// If any record fails, none are stored.
+func (db *SQLiteDB) UpsertHealthRecords(ctx context.Context, userID int64, records []models.HealthRecord) ([]models.HealthRecordUpsert, error) {
+	upsertStmt, err := db.getStmt("upsert_health_record")
+	if err != nil {
+		return nil, fmt.Errorf("getting upsert statement: %w", err)
+	}
+
+	upserts := make([]models.HealthRecordUpsert, 0, len(records))
+	err = db.withTxContext(ctx, func(tx *sql.Tx) error {
+		stmt := tx.StmtContext(ctx, upsertStmt)
+
+		now := time.Now()
+		for _, hr := range records {
+			var stored models.HealthRecord
+			err := stmt.QueryRowContext(ctx, userID, hr.Date, hr.StepCount, now, now).Scan(
+				&stored.ID,
+				&stored.Date,
+				&stored.StepCount,
+				&stored.Version,
+				&stored.CreatedAt,
+				&stored.UpdatedAt,
+			)
+			if err != nil {
+				return fmt.Errorf("upsert health record of %s: %w", hr.Date.Format("2006-01-02"), err)
+			}
+
+			// Every replace increments the version, so only a new record is at version 1
+			upserts = append(upserts, models.HealthRecordUpsert{Record: stored, Created: stored.Version == 1})
+		}
+
 		return nil
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	return upserts, nil
 }
 
-// DeleteHealthRecord deletes a health record by date
-func (db *SQLiteDB) DeleteHealthRecord(ctx context.Context, date time.Time) error {
+// errDryRun rolls back the transaction of a dry run import
+var errDryRun = errors.New("dry run")
+
+// ImportHealthRecords writes the yielded records in a single transaction, applying the conflict policy to dates that already have a record.
+// Unless overwriting, a record is written by an insert that does nothing on conflict, so the existence check and the write are one statement.
+func (db *SQLiteDB) ImportHealthRecords(ctx context.Context, userID int64, records iter.Seq2[models.HealthRecord, error], opts models.ImportOptions) ([]models.HealthRecordImport, error) {
+	writeStmt, err := db.getStmt("insert_new_health_record")
+	if opts.OnConflict == models.ConflictOverwrite {
+		writeStmt, err = db.getStmt("upsert_health_record")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting write statement: %w", err)
+	}
+	selectStmt, err := db.getStmt("select_health_record")
+	if err != nil {
+		return nil, fmt.Errorf("getting select statement: %w", err)
+	}
+
+	var imports []models.HealthRecordImport
+	err = db.withTxContext(ctx, func(tx *sql.Tx) error {
+		write := tx.StmtContext(ctx, writeStmt)
+		read := tx.StmtContext(ctx, selectStmt)
+
+		now := time.Now()
+		for hr, err := range records {
+			if err != nil {
+				return err
+			}
+
+			imported := models.HealthRecordImport{Action: models.ImportCreated}
+			stored := &imported.Record
+			err := write.QueryRowContext(ctx, userID, hr.Date, hr.StepCount, now, now).Scan(&stored.ID, &stored.Date, &stored.StepCount, &stored.Version, &stored.CreatedAt, &stored.UpdatedAt)
+			switch {
+			case err == sql.ErrNoRows:
+				// Nothing was inserted, so the date already has a record
+				if opts.OnConflict == models.ConflictFail {
+					return fmt.Errorf("health record of %s: %w", hr.Date.Format("2006-01-02"), ErrConflict)
+				}
+				imported.Action = models.ImportSkipped
+				err = read.QueryRowContext(ctx, userID, hr.Date).Scan(&stored.ID, &stored.Date, &stored.StepCount, &stored.Version, &stored.CreatedAt, &stored.UpdatedAt)
+			case err == nil && stored.Version > 1:
+				// Every replace increments the version, so only a new record is at version 1
+				imported.Action = models.ImportOverwritten
+			}
+			if err != nil {
+				return fmt.Errorf("import health record of %s: %w", hr.Date.Format("2006-01-02"), err)
+			}
+
+			imports = append(imports, imported)
+		}
+
+		if opts.DryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return nil, err
+	}
+
+	return imports, nil
+}
+
+// DeleteHealthRecord deletes a health record by date.
+// A non-zero version must match the stored version, otherwise ErrVersionMismatch is returned.
+func (db *SQLiteDB) DeleteHealthRecord(ctx context.Context, userID int64, date time.Time, version int64) error {
 	dleleteStmt, err := db.getStmt("delete_health_record")
 	if err != nil {
 		return fmt.Errorf("getting delete statement: %w", err)
@@ -294,21 +596,29 @@ func (db *SQLiteDB) DeleteHealthRecord(ctx context.Context, date time.Time) erro
 	return db.withTxContext(ctx, func(tx *sql.Tx) error {
 		// Check if record exists
 		var exists bool
-		err := tx.QueryRowContext(ctx, "SELECT 1 FROM health_records WHERE date = ?", date).Scan(&exists)
+		err := tx.QueryRowContext(ctx, "SELECT 1 FROM health_records WHERE user_id = ? AND date = ?", userID, date).Scan(&exists)
+		if err == sql.ErrNoRows {
+			return ErrNotFound
+		}
 		if err != nil {
 			return fmt.Errorf("check existence: %w", err)
 		}
-		if !exists {
-			return sql.ErrNoRows
-		}
 
 		// Delete
 		stmt := tx.StmtContext(ctx, dleleteStmt)
-		_, err = stmt.ExecContext(ctx, date)
+		result, err := stmt.ExecContext(ctx, userID, date, version, version)
 		if err != nil {
 			return fmt.Errorf("execute delete: %w", err)
 		}
 
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("get rows affected: %w", err)
+		}
+		if affected == 0 {
+			return ErrVersionMismatch
+		}
+
 		return nil
 	})
 }
@@ -0,0 +1,155 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/models"
+)
+
+// CreateAPIKey inserts a new API key
+func (db *SQLiteDB) CreateAPIKey(ctx context.Context, userID int64, k *models.APIKey) (*models.APIKey, error) {
+	insertStmt, err := db.getStmt("insert_api_key")
+	if err != nil {
+		return nil, fmt.Errorf("getting insert statement: %w", err)
+	}
+
+	var created *models.APIKey
+	err = db.withTxContext(ctx, func(tx *sql.Tx) error {
+		stmt := tx.StmtContext(ctx, insertStmt)
+
+		now := time.Now()
+		result, err := stmt.ExecContext(ctx, userID, k.Name, k.Prefix, k.KeyHash, k.Scope, now, now)
+		if isSQLiteUniqueViolation(err) {
+			return ErrConflict
+		}
+		if err != nil {
+			return fmt.Errorf("insert api key: %w", err)
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("get last insert id: %w", err)
+		}
+
+		key := *k
+		key.ID = id
+		key.UserID = userID
+		key.CreatedAt = now
+		key.UpdatedAt = now
+		created = &key
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// ReadAPIKeys retrieves all API keys of a user, including revoked ones
+func (db *SQLiteDB) ReadAPIKeys(ctx context.Context, userID int64) ([]models.APIKey, error) {
+	selectStmt, err := db.getStmt("select_api_keys")
+	if err != nil {
+		return nil, fmt.Errorf("getting select statement: %w", err)
+	}
+
+	rows, err := selectStmt.QueryContext(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("execute query: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []models.APIKey
+	for rows.Next() {
+		k, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		keys = append(keys, *k)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating through rows: %w", err)
+	}
+
+	return keys, nil
+}
+
+// ReadAPIKeyByHash retrieves the API key with the given hash
+func (db *SQLiteDB) ReadAPIKeyByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	selectStmt, err := db.getStmt("select_api_key_by_hash")
+	if err != nil {
+		return nil, fmt.Errorf("getting select statement: %w", err)
+	}
+
+	k, err := scanAPIKey(selectStmt.QueryRowContext(ctx, keyHash))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // No error, but no key found
+		}
+		return nil, fmt.Errorf("scan api key: %w", err)
+	}
+
+	return k, nil
+}
+
+// UpdateAPIKeyName renames an API key
+func (db *SQLiteDB) UpdateAPIKeyName(ctx context.Context, userID int64, id int64, name string) error {
+	return db.execAPIKeyUpdate(ctx, "update_api_key_name", name, time.Now(), userID, id)
+}
+
+// RevokeAPIKey revokes an API key. Revoking an already revoked key returns ErrNotFound.
+func (db *SQLiteDB) RevokeAPIKey(ctx context.Context, userID int64, id int64) error {
+	now := time.Now()
+	return db.execAPIKeyUpdate(ctx, "revoke_api_key", now, now, userID, id)
+}
+
+// TouchAPIKey records the time an API key was last used
+func (db *SQLiteDB) TouchAPIKey(ctx context.Context, id int64, usedAt time.Time) error {
+	return db.execAPIKeyUpdate(ctx, "touch_api_key", usedAt, id)
+}
+
+// execAPIKeyUpdate runs the named API key update statement and returns ErrNotFound if no key was changed
+func (db *SQLiteDB) execAPIKeyUpdate(ctx context.Context, stmtName string, args ...any) error {
+	updateStmt, err := db.getStmt(stmtName)
+	if err != nil {
+		return fmt.Errorf("getting update statement: %w", err)
+	}
+
+	return db.withTxContext(ctx, func(tx *sql.Tx) error {
+		result, err := tx.StmtContext(ctx, updateStmt).ExecContext(ctx, args...)
+		if err != nil {
+			return fmt.Errorf("execute update: %w", err)
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("get rows affected: %w", err)
+		}
+		if affected == 0 {
+			return ErrNotFound
+		}
+
+		return nil
+	})
+}
+
+// scanAPIKey scans an API key row
+func scanAPIKey(row rowScanner) (*models.APIKey, error) {
+	var k models.APIKey
+	var lastUsedAt, revokedAt sql.NullTime
+	if err := row.Scan(&k.ID, &k.UserID, &k.Name, &k.Prefix, &k.KeyHash, &k.Scope, &lastUsedAt, &revokedAt, &k.CreatedAt, &k.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if lastUsedAt.Valid {
+		k.LastUsedAt = &lastUsedAt.Time
+	}
+	if revokedAt.Valid {
+		k.RevokedAt = &revokedAt.Time
+	}
+	return &k, nil
+}
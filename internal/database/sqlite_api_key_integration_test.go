@@ -0,0 +1,81 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/database"
+	"github.com/nnamm/go-health-tracker/internal/models"
+	"github.com/nnamm/go-health-tracker/testutils"
+)
+
+func TestSQLite_APIKeys(t *testing.T) {
+	testDB, cleanup := testutils.SetupSQLiteTester(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	created, err := testDB.CreateAPIKey(ctx, testutils.TestUserID, &models.APIKey{
+		Name:    "step counter",
+		Prefix:  "ht_abcdefgh",
+		KeyHash: "hash-1",
+		Scope:   models.APIKeyScopeReadOnly,
+	})
+	if err != nil {
+		t.Fatalf("CreateAPIKey() error = %v", err)
+	}
+	if created.ID == 0 || created.UserID != testutils.TestUserID {
+		t.Errorf("CreateAPIKey() = %+v, want an ID and the test user", created)
+	}
+
+	byHash, err := testDB.ReadAPIKeyByHash(ctx, "hash-1")
+	if err != nil {
+		t.Fatalf("ReadAPIKeyByHash() error = %v", err)
+	}
+	if byHash == nil || byHash.ID != created.ID || byHash.UserID != testutils.TestUserID || byHash.LastUsedAt != nil {
+		t.Errorf("ReadAPIKeyByHash() = %+v, want the unused created key", byHash)
+	}
+	if missing, err := testDB.ReadAPIKeyByHash(ctx, "unknown"); err != nil || missing != nil {
+		t.Errorf("ReadAPIKeyByHash() for unknown hash = %+v, %v, want nil, nil", missing, err)
+	}
+
+	usedAt := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	if err := testDB.TouchAPIKey(ctx, created.ID, usedAt); err != nil {
+		t.Fatalf("TouchAPIKey() error = %v", err)
+	}
+	if err := testDB.UpdateAPIKeyName(ctx, testutils.TestUserID, created.ID, "pedometer"); err != nil {
+		t.Fatalf("UpdateAPIKeyName() error = %v", err)
+	}
+
+	keys, err := testDB.ReadAPIKeys(ctx, testutils.TestUserID)
+	if err != nil {
+		t.Fatalf("ReadAPIKeys() error = %v", err)
+	}
+	if len(keys) != 1 || keys[0].Name != "pedometer" || keys[0].LastUsedAt == nil || !keys[0].LastUsedAt.Equal(usedAt) {
+		t.Fatalf("ReadAPIKeys() = %+v, want the renamed key used at %v", keys, usedAt)
+	}
+
+	// Other users can neither see nor revoke the key
+	other, err := testDB.CreateUser(ctx, &models.User{Email: "other@example.com"})
+	if err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+	if keys, _ := testDB.ReadAPIKeys(ctx, other.ID); len(keys) != 0 {
+		t.Errorf("ReadAPIKeys() for other user returned %d keys, want 0", len(keys))
+	}
+	if err := testDB.RevokeAPIKey(ctx, other.ID, created.ID); !errors.Is(err, database.ErrNotFound) {
+		t.Errorf("RevokeAPIKey() of another user's key error = %v, want %v", err, database.ErrNotFound)
+	}
+
+	if err := testDB.RevokeAPIKey(ctx, testutils.TestUserID, created.ID); err != nil {
+		t.Fatalf("RevokeAPIKey() error = %v", err)
+	}
+	if err := testDB.RevokeAPIKey(ctx, testutils.TestUserID, created.ID); !errors.Is(err, database.ErrNotFound) {
+		t.Errorf("RevokeAPIKey() of a revoked key error = %v, want %v", err, database.ErrNotFound)
+	}
+	if revoked, _ := testDB.ReadAPIKeyByHash(ctx, "hash-1"); revoked == nil || !revoked.IsRevoked() {
+		t.Errorf("ReadAPIKeyByHash() after revoke = %+v, want a revoked key", revoked)
+	}
+}
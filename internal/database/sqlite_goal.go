@@ -0,0 +1,101 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/models"
+)
+
+// UpsertStepGoal creates the step goal effective from the given date or replaces its step count
+func (db *SQLiteDB) UpsertStepGoal(ctx context.Context, userID int64, g *models.StepGoal) (*models.StepGoal, error) {
+	upsertStmt, err := db.getStmt("upsert_step_goal")
+	if err != nil {
+		return nil, fmt.Errorf("getting upsert statement: %w", err)
+	}
+
+	var stored *models.StepGoal
+	err = db.withTxContext(ctx, func(tx *sql.Tx) error {
+		stmt := tx.StmtContext(ctx, upsertStmt)
+
+		now := time.Now()
+		goal, err := scanStepGoal(stmt.QueryRowContext(ctx, userID, g.EffectiveFrom, g.StepCount, now, now))
+		if err != nil {
+			return fmt.Errorf("upsert step goal: %w", err)
+		}
+		stored = goal
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return stored, nil
+}
+
+// ReadStepGoals retrieves all step goals of a user ordered by effective date
+func (db *SQLiteDB) ReadStepGoals(ctx context.Context, userID int64) ([]models.StepGoal, error) {
+	selectStmt, err := db.getStmt("select_step_goals")
+	if err != nil {
+		return nil, fmt.Errorf("getting select statement: %w", err)
+	}
+
+	rows, err := selectStmt.QueryContext(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("execute query: %w", err)
+	}
+	defer rows.Close()
+
+	var goals []models.StepGoal
+	for rows.Next() {
+		g, err := scanStepGoal(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		goals = append(goals, *g)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating through rows: %w", err)
+	}
+
+	return goals, nil
+}
+
+// DeleteStepGoal deletes the step goal effective from the given date
+func (db *SQLiteDB) DeleteStepGoal(ctx context.Context, userID int64, effectiveFrom time.Time) error {
+	deleteStmt, err := db.getStmt("delete_step_goal")
+	if err != nil {
+		return fmt.Errorf("getting delete statement: %w", err)
+	}
+
+	return db.withTxContext(ctx, func(tx *sql.Tx) error {
+		stmt := tx.StmtContext(ctx, deleteStmt)
+		result, err := stmt.ExecContext(ctx, userID, effectiveFrom)
+		if err != nil {
+			return fmt.Errorf("execute delete: %w", err)
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("get rows affected: %w", err)
+		}
+		if affected == 0 {
+			return ErrNotFound
+		}
+
+		return nil
+	})
+}
+
+// scanStepGoal scans a step goal row
+func scanStepGoal(row rowScanner) (*models.StepGoal, error) {
+	var g models.StepGoal
+	if err := row.Scan(&g.ID, &g.EffectiveFrom, &g.StepCount, &g.CreatedAt, &g.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
@@ -0,0 +1,89 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/nnamm/go-health-tracker/internal/database"
+	"github.com/nnamm/go-health-tracker/internal/models"
+	"github.com/nnamm/go-health-tracker/testutils"
+)
+
+func TestSQLite_StepGoals(t *testing.T) {
+	testDB, cleanup := testutils.SetupSQLiteTester(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	march, err := testDB.UpsertStepGoal(ctx, testutils.TestUserID, &models.StepGoal{EffectiveFrom: testutils.CreateDate("2024-03-01"), StepCount: 8000})
+	if err != nil {
+		t.Fatalf("UpsertStepGoal() error = %v", err)
+	}
+	if march.ID == 0 || march.StepCount != 8000 {
+		t.Errorf("UpsertStepGoal() = %+v", march)
+	}
+	if _, err := testDB.UpsertStepGoal(ctx, testutils.TestUserID, &models.StepGoal{EffectiveFrom: testutils.CreateDate("2024-01-01"), StepCount: 6000}); err != nil {
+		t.Fatalf("UpsertStepGoal() error = %v", err)
+	}
+
+	// Setting a goal for the same date replaces it
+	replaced, err := testDB.UpsertStepGoal(ctx, testutils.TestUserID, &models.StepGoal{EffectiveFrom: testutils.CreateDate("2024-03-01"), StepCount: 10000})
+	if err != nil {
+		t.Fatalf("UpsertStepGoal() replace error = %v", err)
+	}
+	if replaced.ID != march.ID || replaced.StepCount != 10000 {
+		t.Errorf("UpsertStepGoal() replace = %+v, want goal %d with 10000 steps", replaced, march.ID)
+	}
+
+	goals, err := testDB.ReadStepGoals(ctx, testutils.TestUserID)
+	if err != nil {
+		t.Fatalf("ReadStepGoals() error = %v", err)
+	}
+	if len(goals) != 2 {
+		t.Fatalf("got %d goals, want 2", len(goals))
+	}
+	if !goals[0].EffectiveFrom.Equal(testutils.CreateDate("2024-01-01")) || goals[1].StepCount != 10000 {
+		t.Errorf("ReadStepGoals() = %+v, want ordered by effective date", goals)
+	}
+
+	// Goals are evaluated against the stored health records
+	records := []models.HealthRecord{
+		{Date: testutils.CreateDate("2024-02-28"), StepCount: 7000},
+		{Date: testutils.CreateDate("2024-02-29"), StepCount: 7000},
+		{Date: testutils.CreateDate("2024-03-01"), StepCount: 7000},
+		{Date: testutils.CreateDate("2024-03-03"), StepCount: 12000},
+	}
+	testutils.CreateTestRecords(ctx, t, testDB.DB, records)
+
+	start, end := testutils.CreateDate("2024-02-28"), testutils.CreateDate("2024-03-04")
+	stored, err := testDB.ReadHealthRecordsByRange(ctx, testutils.TestUserID, start, end)
+	if err != nil {
+		t.Fatalf("ReadHealthRecordsByRange() error = %v", err)
+	}
+
+	days := models.EvaluateGoalDays(stored, goals, start, end)
+	met := make([]bool, len(days))
+	for i, d := range days {
+		met[i] = d.Met
+	}
+	want := []bool{true, true, false, false, true}
+	if len(met) != len(want) {
+		t.Fatalf("got %d days, want %d", len(met), len(want))
+	}
+	for i := range want {
+		if met[i] != want[i] {
+			t.Errorf("day %s met = %v, want %v", days[i].Date.Format("2006-01-02"), met[i], want[i])
+		}
+	}
+
+	if err := testDB.DeleteStepGoal(ctx, testutils.TestUserID, testutils.CreateDate("2024-03-01")); err != nil {
+		t.Fatalf("DeleteStepGoal() error = %v", err)
+	}
+	if err := testDB.DeleteStepGoal(ctx, testutils.TestUserID, testutils.CreateDate("2024-03-01")); !errors.Is(err, database.ErrNotFound) {
+		t.Errorf("DeleteStepGoal() of a missing goal error = %v, want %v", err, database.ErrNotFound)
+	}
+	if goals, _ := testDB.ReadStepGoals(ctx, testutils.TestUserID); len(goals) != 1 {
+		t.Errorf("ReadStepGoals() after delete returned %d goals, want 1", len(goals))
+	}
+}
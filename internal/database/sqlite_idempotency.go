@@ -0,0 +1,138 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/models"
+)
+
+// ReserveIdempotencyKey reserves a key for the first request sent with it.
+// It returns ErrConflict while the key is reserved or holds a response that has not expired.
+func (db *SQLiteDB) ReserveIdempotencyKey(ctx context.Context, userID int64, k *models.IdempotencyKey) error {
+	reserveStmt, err := db.getStmt("reserve_idempotency_key")
+	if err != nil {
+		return fmt.Errorf("getting reserve statement: %w", err)
+	}
+
+	return db.withTxContext(ctx, func(tx *sql.Tx) error {
+		// Times are stored in UTC, so that they compare as text
+		result, err := tx.StmtContext(ctx, reserveStmt).ExecContext(ctx, userID, k.Key, k.RequestHash, k.CreatedAt.UTC(), k.ExpiresAt.UTC())
+		if err != nil {
+			return fmt.Errorf("reserve idempotency key: %w", err)
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("get rows affected: %w", err)
+		}
+		if affected == 0 {
+			return ErrConflict
+		}
+
+		return nil
+	})
+}
+
+// ReadIdempotencyKey retrieves a key that has not expired
+func (db *SQLiteDB) ReadIdempotencyKey(ctx context.Context, userID int64, key string) (*models.IdempotencyKey, error) {
+	selectStmt, err := db.getStmt("select_idempotency_key")
+	if err != nil {
+		return nil, fmt.Errorf("getting select statement: %w", err)
+	}
+
+	var k models.IdempotencyKey
+	var header string
+	err = selectStmt.QueryRowContext(ctx, userID, key, time.Now().UTC()).Scan(
+		&k.Key, &k.RequestHash, &k.StatusCode, &header, &k.Body, &k.CreatedAt, &k.ExpiresAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // No key found, return nil without error
+		}
+		return nil, fmt.Errorf("scan idempotency key: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(header), &k.Header); err != nil {
+		return nil, fmt.Errorf("decode headers: %w", err)
+	}
+
+	return &k, nil
+}
+
+// CompleteIdempotencyKey stores the request hash and the response of the request a key was reserved for
+func (db *SQLiteDB) CompleteIdempotencyKey(ctx context.Context, userID int64, k *models.IdempotencyKey) error {
+	completeStmt, err := db.getStmt("complete_idempotency_key")
+	if err != nil {
+		return fmt.Errorf("getting update statement: %w", err)
+	}
+
+	header, err := json.Marshal(k.Header)
+	if err != nil {
+		return fmt.Errorf("encode headers: %w", err)
+	}
+
+	return db.withTxContext(ctx, func(tx *sql.Tx) error {
+		result, err := tx.StmtContext(ctx, completeStmt).ExecContext(ctx, k.RequestHash, k.StatusCode, string(header), k.Body, userID, k.Key)
+		if err != nil {
+			return fmt.Errorf("execute update: %w", err)
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("get rows affected: %w", err)
+		}
+		if affected == 0 {
+			return ErrNotFound
+		}
+
+		return nil
+	})
+}
+
+// DeleteIdempotencyKey releases a key, so that the request can be retried
+func (db *SQLiteDB) DeleteIdempotencyKey(ctx context.Context, userID int64, key string) error {
+	deleteStmt, err := db.getStmt("delete_idempotency_key")
+	if err != nil {
+		return fmt.Errorf("getting delete statement: %w", err)
+	}
+
+	return db.withTxContext(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.StmtContext(ctx, deleteStmt).ExecContext(ctx, userID, key); err != nil {
+			return fmt.Errorf("execute delete: %w", err)
+		}
+		return nil
+	})
+}
+
+// PurgeIdempotencyKeys deletes the keys of all users that have expired by now
+// and returns the number of deleted keys
+func (db *SQLiteDB) PurgeIdempotencyKeys(ctx context.Context, now time.Time) (int64, error) {
+	purgeStmt, err := db.getStmt("purge_idempotency_keys")
+	if err != nil {
+		return 0, fmt.Errorf("getting purge statement: %w", err)
+	}
+
+	var purged int64
+	err = db.withTxContext(ctx, func(tx *sql.Tx) error {
+		result, err := tx.StmtContext(ctx, purgeStmt).ExecContext(ctx, now.UTC())
+		if err != nil {
+			return fmt.Errorf("execute purge: %w", err)
+		}
+
+		purged, err = result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("get rows affected: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return purged, nil
+}
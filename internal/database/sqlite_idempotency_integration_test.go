@@ -0,0 +1,112 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/database"
+	"github.com/nnamm/go-health-tracker/internal/models"
+	"github.com/nnamm/go-health-tracker/testutils"
+)
+
+func TestSQLite_IdempotencyKeys(t *testing.T) {
+	testDB, cleanup := testutils.SetupSQLiteTester(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	now := time.Now()
+	k := &models.IdempotencyKey{Key: "key-1", RequestHash: "hash-1", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+
+	if err := testDB.ReserveIdempotencyKey(ctx, testutils.TestUserID, k); err != nil {
+		t.Fatalf("ReserveIdempotencyKey() error = %v", err)
+	}
+	if err := testDB.ReserveIdempotencyKey(ctx, testutils.TestUserID, k); !errors.Is(err, database.ErrConflict) {
+		t.Errorf("ReserveIdempotencyKey() of a reserved key error = %v, want %v", err, database.ErrConflict)
+	}
+
+	reserved, err := testDB.ReadIdempotencyKey(ctx, testutils.TestUserID, "key-1")
+	if err != nil {
+		t.Fatalf("ReadIdempotencyKey() error = %v", err)
+	}
+	if reserved == nil || reserved.RequestHash != "hash-1" || reserved.Completed() {
+		t.Fatalf("ReadIdempotencyKey() = %+v, want the reserved key in progress", reserved)
+	}
+
+	// The request hash is known once the body has been read
+	k.RequestHash = "hash-2"
+	k.StatusCode = 201
+	k.Header = map[string]string{"Content-Type": "application/json", "Etag": `"1"`}
+	k.Body = []byte(`{"step_count":8000}`)
+	if err := testDB.CompleteIdempotencyKey(ctx, testutils.TestUserID, k); err != nil {
+		t.Fatalf("CompleteIdempotencyKey() error = %v", err)
+	}
+
+	completed, err := testDB.ReadIdempotencyKey(ctx, testutils.TestUserID, "key-1")
+	if err != nil {
+		t.Fatalf("ReadIdempotencyKey() error = %v", err)
+	}
+	if completed == nil || completed.StatusCode != 201 || string(completed.Body) != `{"step_count":8000}` || completed.Header["Etag"] != `"1"` || completed.RequestHash != "hash-2" {
+		t.Errorf("ReadIdempotencyKey() = %+v, want the stored response", completed)
+	}
+
+	// Keys are scoped to the user
+	other, err := testDB.ReadIdempotencyKey(ctx, testutils.TestUserID+1, "key-1")
+	if err != nil || other != nil {
+		t.Errorf("ReadIdempotencyKey() of another user = %+v, %v, want nil, nil", other, err)
+	}
+
+	// A released key can be reserved again
+	if err := testDB.DeleteIdempotencyKey(ctx, testutils.TestUserID, "key-1"); err != nil {
+		t.Fatalf("DeleteIdempotencyKey() error = %v", err)
+	}
+	if err := testDB.ReserveIdempotencyKey(ctx, testutils.TestUserID, k); err != nil {
+		t.Errorf("ReserveIdempotencyKey() of a released key error = %v", err)
+	}
+}
+
+func TestSQLite_IdempotencyKeyExpiry(t *testing.T) {
+	testDB, cleanup := testutils.SetupSQLiteTester(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	now := time.Now()
+	expired := &models.IdempotencyKey{Key: "expired", RequestHash: "hash-1", CreatedAt: now.Add(-2 * time.Hour), ExpiresAt: now.Add(-time.Hour)}
+	stale := &models.IdempotencyKey{Key: "stale", RequestHash: "hash-1", CreatedAt: now.Add(-2 * time.Hour), ExpiresAt: now.Add(-time.Hour)}
+	live := &models.IdempotencyKey{Key: "live", RequestHash: "hash-2", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+
+	for _, k := range []*models.IdempotencyKey{expired, stale, live} {
+		if err := testDB.ReserveIdempotencyKey(ctx, testutils.TestUserID, k); err != nil {
+			t.Fatalf("ReserveIdempotencyKey(%s) error = %v", k.Key, err)
+		}
+	}
+
+	got, err := testDB.ReadIdempotencyKey(ctx, testutils.TestUserID, "expired")
+	if err != nil || got != nil {
+		t.Errorf("ReadIdempotencyKey() of an expired key = %+v, %v, want nil, nil", got, err)
+	}
+
+	// An expired key is reserved again instead of conflicting
+	stale.RequestHash = "hash-3"
+	stale.CreatedAt = now
+	stale.ExpiresAt = now.Add(time.Hour)
+	if err := testDB.ReserveIdempotencyKey(ctx, testutils.TestUserID, stale); err != nil {
+		t.Errorf("ReserveIdempotencyKey() of an expired key error = %v", err)
+	}
+	if got, _ := testDB.ReadIdempotencyKey(ctx, testutils.TestUserID, "stale"); got == nil || got.RequestHash != "hash-3" {
+		t.Errorf("ReadIdempotencyKey() of a reserved again key = %+v, want the new reservation", got)
+	}
+
+	purged, err := testDB.PurgeIdempotencyKeys(ctx, now)
+	if err != nil {
+		t.Fatalf("PurgeIdempotencyKeys() error = %v", err)
+	}
+	if purged != 1 {
+		t.Errorf("PurgeIdempotencyKeys() = %d, want 1", purged)
+	}
+
+	if got, _ := testDB.ReadIdempotencyKey(ctx, testutils.TestUserID, "live"); got == nil {
+		t.Error("PurgeIdempotencyKeys() deleted a live key")
+	}
+}
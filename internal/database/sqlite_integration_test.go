@@ -2,8 +2,8 @@ package database_test
 
 import (
 	"context"
-	"database/sql"
 	"errors"
+	"iter"
 	"testing"
 	"time"
 
@@ -53,11 +53,11 @@ func TestSQLite_HealthRecordCRUDScenarios(t *testing.T) {
 				Date:      testutils.CreateDate("2024-01-02"),
 				StepCount: 15000,
 			},
-			wantUpdateErr: sql.ErrNoRows,
+			wantUpdateErr: database.ErrNotFound,
 		},
 		{
 			name:          "error scenerio - delete non-existence record",
-			wantDeleteErr: sql.ErrNoRows,
+			wantDeleteErr: database.ErrNotFound,
 		},
 	}
 
@@ -68,7 +68,7 @@ func TestSQLite_HealthRecordCRUDScenarios(t *testing.T) {
 
 			// create
 			if tt.initial != nil {
-				created, err := testDB.CreateHealthRecord(ctx, tt.initial)
+				created, err := testDB.CreateHealthRecord(ctx, testutils.TestUserID, tt.initial)
 				if !errors.Is(err, tt.wantCreateErr) {
 					t.Errorf("CreateHealthRecord() error = %v, want %v", err, tt.wantCreateErr)
 				}
@@ -79,23 +79,23 @@ func TestSQLite_HealthRecordCRUDScenarios(t *testing.T) {
 
 			// update
 			if tt.update != nil {
-				err := testDB.UpdateHealthRecord(ctx, tt.update)
+				err := testDB.UpdateHealthRecord(ctx, testutils.TestUserID, tt.update)
 				if !errors.Is(err, tt.wantUpdateErr) {
 					t.Errorf("UpdateHealthRecord() error = %v, want %v", err, tt.wantUpdateErr)
 				}
 				if tt.wantAfterUpdate != nil && err == nil {
-					retrieved, _ := testDB.ReadHealthRecord(ctx, tt.update.Date)
+					retrieved, _ := testDB.ReadHealthRecord(ctx, testutils.TestUserID, tt.update.Date)
 					testutils.AssertHealthRecordEqual(t, retrieved, tt.wantAfterUpdate)
 				}
 			}
 
 			// delete
 			if tt.initial != nil {
-				err := testDB.DeleteHealthRecord(ctx, tt.initial.Date)
+				err := testDB.DeleteHealthRecord(ctx, testutils.TestUserID, tt.initial.Date, 0)
 				if !errors.Is(err, tt.wantDeleteErr) {
 					t.Errorf("DeleteHealthRecord() error = %v, want %v", err, tt.wantDeleteErr)
 				}
-				retrieved, _ := testDB.ReadHealthRecord(ctx, tt.initial.Date)
+				retrieved, _ := testDB.ReadHealthRecord(ctx, testutils.TestUserID, tt.initial.Date)
 				if retrieved != tt.wantAfterDelete {
 					t.Errorf("after delete, got record = %v, want %v", retrieved, tt.wantAfterDelete)
 				}
@@ -178,9 +178,9 @@ func TestSQLite_ReadHealthRecords(t *testing.T) {
 			var got []models.HealthRecord
 			var err error
 			if tt.month == nil {
-				got, err = testDB.ReadHealthRecordsByYear(ctx, tt.year)
+				got, err = testDB.ReadHealthRecordsByYear(ctx, testutils.TestUserID, tt.year)
 			} else {
-				got, err = testDB.ReadHealthRecordsByYearMonth(ctx, tt.year, *tt.month)
+				got, err = testDB.ReadHealthRecordsByYearMonth(ctx, testutils.TestUserID, tt.year, *tt.month)
 			}
 
 			if !errors.Is(err, tt.wantErr) {
@@ -194,6 +194,140 @@ func TestSQLite_ReadHealthRecords(t *testing.T) {
 	}
 }
 
+func TestSQLite_ReadHealthRecordsByRange(t *testing.T) {
+	testDB, cleanup := testutils.SetupSQLiteTester(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	records := []models.HealthRecord{
+		{Date: testutils.CreateDate("2024-12-30"), StepCount: 9000},
+		{Date: testutils.CreateDate("2024-12-31"), StepCount: 10000},
+		{Date: testutils.CreateDate("2025-01-01"), StepCount: 11000},
+		{Date: testutils.CreateDate("2025-01-05"), StepCount: 12000},
+	}
+	testutils.CreateTestRecords(ctx, t, testDB.DB, records)
+
+	tests := []struct {
+		name  string
+		start string
+		end   string // exclusive
+		want  []models.HealthRecord
+	}{
+		{
+			name:  "range across year boundary",
+			start: "2024-12-31",
+			end:   "2025-01-02",
+			want: []models.HealthRecord{
+				{Date: testutils.CreateDate("2024-12-31"), StepCount: 10000},
+				{Date: testutils.CreateDate("2025-01-01"), StepCount: 11000},
+			},
+		},
+		{
+			name:  "end date is exclusive",
+			start: "2025-01-01",
+			end:   "2025-01-05",
+			want: []models.HealthRecord{
+				{Date: testutils.CreateDate("2025-01-01"), StepCount: 11000},
+			},
+		},
+		{
+			name:  "empty result",
+			start: "2025-01-02",
+			end:   "2025-01-05",
+			want:  []models.HealthRecord{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := testDB.ReadHealthRecordsByRange(ctx, testutils.TestUserID, testutils.CreateDate(tt.start), testutils.CreateDate(tt.end))
+			if err != nil {
+				t.Fatalf("ReadHealthRecordsByRange() error = %v", err)
+			}
+			testutils.AssertHealthRecordsEqual(t, got, tt.want)
+		})
+	}
+}
+
+func TestSQLite_ReadHealthRecordsPage(t *testing.T) {
+	testDB, cleanup := testutils.SetupSQLiteTester(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	records := []models.HealthRecord{
+		{Date: testutils.CreateDate("2024-03-01"), StepCount: 9000},
+		{Date: testutils.CreateDate("2024-03-02"), StepCount: 7000},
+		{Date: testutils.CreateDate("2024-03-03"), StepCount: 9000},
+		{Date: testutils.CreateDate("2024-03-04"), StepCount: 12000},
+		{Date: testutils.CreateDate("2024-03-05"), StepCount: 5000},
+		{Date: testutils.CreateDate("2024-04-01"), StepCount: 8000}, // outside the range
+	}
+	testutils.CreateTestRecords(ctx, t, testDB.DB, records)
+
+	start, end := testutils.CreateDate("2024-03-01"), testutils.CreateDate("2024-04-01")
+
+	tests := []struct {
+		sort models.RecordSort
+		want []string // dates in page order; equal step counts are ordered by date
+	}{
+		{models.RecordSortDate, []string{"2024-03-01", "2024-03-02", "2024-03-03", "2024-03-04", "2024-03-05"}},
+		{models.RecordSortDateDesc, []string{"2024-03-05", "2024-03-04", "2024-03-03", "2024-03-02", "2024-03-01"}},
+		{models.RecordSortStepCount, []string{"2024-03-05", "2024-03-02", "2024-03-01", "2024-03-03", "2024-03-04"}},
+		{models.RecordSortStepCountDesc, []string{"2024-03-04", "2024-03-03", "2024-03-01", "2024-03-02", "2024-03-05"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.sort), func(t *testing.T) {
+			page := models.PageRequest{Limit: 2, Sort: tt.sort}
+			var got []string
+			pages := 0
+
+			for {
+				result, err := testDB.ReadHealthRecordsPage(ctx, testutils.TestUserID, start, end, page)
+				if err != nil {
+					t.Fatalf("ReadHealthRecordsPage() error = %v", err)
+				}
+				if result.Total != 5 {
+					t.Errorf("Total = %d, want 5", result.Total)
+				}
+				for _, hr := range result.Records {
+					got = append(got, hr.Date.Format("2006-01-02"))
+				}
+				pages++
+
+				if result.NextCursor == "" {
+					break
+				}
+				if page.After, err = models.DecodeHealthRecordCursor(result.NextCursor); err != nil {
+					t.Fatalf("DecodeHealthRecordCursor() error = %v", err)
+				}
+			}
+
+			if pages != 3 {
+				t.Errorf("got %d pages, want 3", pages)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got dates %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("got dates %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+
+	t.Run("exact last page has no cursor", func(t *testing.T) {
+		result, err := testDB.ReadHealthRecordsPage(ctx, testutils.TestUserID, start, end, models.PageRequest{Limit: 5, Sort: models.RecordSortDate})
+		if err != nil {
+			t.Fatalf("ReadHealthRecordsPage() error = %v", err)
+		}
+		if len(result.Records) != 5 || result.NextCursor != "" {
+			t.Errorf("got %d records and cursor %q, want 5 records and no cursor", len(result.Records), result.NextCursor)
+		}
+	})
+}
+
 func TestSQLite_UpdateHealthRecord(t *testing.T) {
 	testDB, cleanup := testutils.SetupSQLiteTester(t)
 	defer cleanup()
@@ -275,7 +409,7 @@ func TestSQLite_UpdateHealthRecord(t *testing.T) {
 				Date:      testutils.CreateDate("2024-01-01"),
 				StepCount: 10000,
 			},
-			wantErr: sql.ErrNoRows,
+			wantErr: database.ErrNotFound,
 		},
 		{
 			name: "error - update with different date (future)",
@@ -290,7 +424,7 @@ func TestSQLite_UpdateHealthRecord(t *testing.T) {
 				Date:      testutils.CreateDate("2024-02-01"),
 				StepCount: 12000,
 			},
-			wantErr: sql.ErrNoRows,
+			wantErr: database.ErrNotFound,
 		},
 		{
 			name: "error - update with different date (past)",
@@ -305,7 +439,7 @@ func TestSQLite_UpdateHealthRecord(t *testing.T) {
 				Date:      testutils.CreateDate("2020-01-01"),
 				StepCount: 12000,
 			},
-			wantErr: sql.ErrNoRows,
+			wantErr: database.ErrNotFound,
 		},
 		{
 			name: "error - update with improbable step count",
@@ -320,7 +454,7 @@ func TestSQLite_UpdateHealthRecord(t *testing.T) {
 				Date:      testutils.CreateDate("2020-01-01"),
 				StepCount: 100001,
 			},
-			wantErr: sql.ErrNoRows,
+			wantErr: database.ErrNotFound,
 		},
 	}
 
@@ -333,23 +467,248 @@ func TestSQLite_UpdateHealthRecord(t *testing.T) {
 				tt.setup(t, ctx, testDB)
 			}
 
-			err := testDB.UpdateHealthRecord(ctx, tt.update)
+			err := testDB.UpdateHealthRecord(ctx, testutils.TestUserID, tt.update)
 			if !errors.Is(err, tt.wantErr) {
 				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
 			}
 
 			if err == nil {
-				retrieved, _ := testDB.ReadHealthRecord(ctx, tt.update.Date)
+				retrieved, _ := testDB.ReadHealthRecord(ctx, testutils.TestUserID, tt.update.Date)
 				testutils.AssertHealthRecordEqual(t, retrieved, tt.update)
 			}
 			if tt.nonUpdate != nil {
-				nonAffectRecord, _ := testDB.ReadHealthRecord(ctx, tt.nonUpdate.Date)
+				nonAffectRecord, _ := testDB.ReadHealthRecord(ctx, testutils.TestUserID, tt.nonUpdate.Date)
 				testutils.AssertHealthRecordEqual(t, nonAffectRecord, tt.nonUpdate)
 			}
 		})
 	}
 }
 
+func TestSQLite_UpsertHealthRecord(t *testing.T) {
+	testDB, cleanup := testutils.SetupSQLiteTester(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	date := testutils.CreateDate("2024-01-01")
+
+	created, isNew, err := testDB.UpsertHealthRecord(ctx, testutils.TestUserID, &models.HealthRecord{Date: date, StepCount: 8000})
+	if err != nil {
+		t.Fatalf("UpsertHealthRecord() error = %v", err)
+	}
+	if !isNew || created.ID == 0 || created.StepCount != 8000 {
+		t.Errorf("UpsertHealthRecord() = %+v, created %v, want a new record with 8000 steps", created, isNew)
+	}
+
+	replaced, isNew, err := testDB.UpsertHealthRecord(ctx, testutils.TestUserID, &models.HealthRecord{Date: date, StepCount: 9500})
+	if err != nil {
+		t.Fatalf("UpsertHealthRecord() replace error = %v", err)
+	}
+	if isNew {
+		t.Error("UpsertHealthRecord() of an existing date reported created")
+	}
+	if replaced.ID != created.ID || replaced.StepCount != 9500 || !replaced.CreatedAt.Equal(created.CreatedAt) {
+		t.Errorf("UpsertHealthRecord() replace = %+v, want record %d with 9500 steps and the original creation time", replaced, created.ID)
+	}
+
+	got, err := testDB.ReadHealthRecord(ctx, testutils.TestUserID, date)
+	if err != nil {
+		t.Fatalf("ReadHealthRecord() error = %v", err)
+	}
+	if got == nil || got.StepCount != 9500 {
+		t.Errorf("ReadHealthRecord() = %+v, want 9500 steps", got)
+	}
+}
+
+func TestSQLite_UpsertHealthRecords(t *testing.T) {
+	testDB, cleanup := testutils.SetupSQLiteTester(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	existing, err := testDB.CreateHealthRecord(ctx, testutils.TestUserID, &models.HealthRecord{Date: testutils.CreateDate("2024-01-01"), StepCount: 8000})
+	if err != nil {
+		t.Fatalf("CreateHealthRecord() error = %v", err)
+	}
+
+	upserts, err := testDB.UpsertHealthRecords(ctx, testutils.TestUserID, []models.HealthRecord{
+		{Date: testutils.CreateDate("2024-01-01"), StepCount: 9500},
+		{Date: testutils.CreateDate("2024-01-02"), StepCount: 7000},
+	})
+	if err != nil {
+		t.Fatalf("UpsertHealthRecords() error = %v", err)
+	}
+	if len(upserts) != 2 {
+		t.Fatalf("UpsertHealthRecords() returned %d upserts, want 2", len(upserts))
+	}
+
+	if replaced := upserts[0]; replaced.Created || replaced.Record.ID != existing.ID || replaced.Record.StepCount != 9500 || replaced.Record.Version != 2 {
+		t.Errorf("UpsertHealthRecords()[0] = %+v, want record %d replaced with 9500 steps at version 2", replaced, existing.ID)
+	}
+	if created := upserts[1]; !created.Created || created.Record.ID == 0 || created.Record.StepCount != 7000 {
+		t.Errorf("UpsertHealthRecords()[1] = %+v, want a new record with 7000 steps", created)
+	}
+
+	records, err := testDB.ReadHealthRecordsByRange(ctx, testutils.TestUserID, testutils.CreateDate("2024-01-01"), testutils.CreateDate("2024-01-03"))
+	if err != nil {
+		t.Fatalf("ReadHealthRecordsByRange() error = %v", err)
+	}
+	if len(records) != 2 {
+		t.Errorf("ReadHealthRecordsByRange() returned %d records, want 2", len(records))
+	}
+
+	// All records of a batch share one timestamp, so a date written twice is created and then replaced
+	// with equal creation and update times
+	twice, err := testDB.UpsertHealthRecords(ctx, testutils.TestUserID, []models.HealthRecord{
+		{Date: testutils.CreateDate("2024-02-01"), StepCount: 1000},
+		{Date: testutils.CreateDate("2024-02-01"), StepCount: 2000},
+	})
+	if err != nil {
+		t.Fatalf("UpsertHealthRecords() of a date twice error = %v", err)
+	}
+	if !twice[0].Created || twice[1].Created {
+		t.Errorf("UpsertHealthRecords() of a date twice reported created = %v, %v, want true, false", twice[0].Created, twice[1].Created)
+	}
+}
+
+// importSeq yields the records for ImportHealthRecords, followed by err when it is set
+func importSeq(err error, records ...models.HealthRecord) iter.Seq2[models.HealthRecord, error] {
+	return func(yield func(models.HealthRecord, error) bool) {
+		for _, hr := range records {
+			if !yield(hr, nil) {
+				return
+			}
+		}
+		if err != nil {
+			yield(models.HealthRecord{}, err)
+		}
+	}
+}
+
+func TestSQLite_ImportHealthRecords(t *testing.T) {
+	testDB, cleanup := testutils.SetupSQLiteTester(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	existing, err := testDB.CreateHealthRecord(ctx, testutils.TestUserID, &models.HealthRecord{Date: testutils.CreateDate("2024-01-01"), StepCount: 8000})
+	if err != nil {
+		t.Fatalf("CreateHealthRecord() error = %v", err)
+	}
+
+	stepCount := func(date string) int {
+		t.Helper()
+		hr, err := testDB.ReadHealthRecord(ctx, testutils.TestUserID, testutils.CreateDate(date))
+		if err != nil {
+			t.Fatalf("ReadHealthRecord() error = %v", err)
+		}
+		if hr == nil {
+			return 0
+		}
+		return hr.StepCount
+	}
+
+	records := []models.HealthRecord{
+		{Date: testutils.CreateDate("2024-01-02"), StepCount: 7000},
+		{Date: testutils.CreateDate("2024-01-01"), StepCount: 9500},
+	}
+
+	// Failing, a dry run and an error while reading the records all roll back
+	_, err = testDB.ImportHealthRecords(ctx, testutils.TestUserID, importSeq(nil, records...), models.ImportOptions{OnConflict: models.ConflictFail})
+	if !errors.Is(err, database.ErrConflict) {
+		t.Errorf("ImportHealthRecords() with the fail policy error = %v, want %v", err, database.ErrConflict)
+	}
+	readErr := errors.New("unexpected EOF")
+	if _, err := testDB.ImportHealthRecords(ctx, testutils.TestUserID, importSeq(readErr, records[0]), models.ImportOptions{OnConflict: models.ConflictSkip}); !errors.Is(err, readErr) {
+		t.Errorf("ImportHealthRecords() with a read error error = %v, want %v", err, readErr)
+	}
+	dryRun, err := testDB.ImportHealthRecords(ctx, testutils.TestUserID, importSeq(nil, records...), models.ImportOptions{OnConflict: models.ConflictOverwrite, DryRun: true})
+	if err != nil {
+		t.Fatalf("ImportHealthRecords() dry run error = %v", err)
+	}
+	if len(dryRun) != 2 || dryRun[0].Action != models.ImportCreated || dryRun[1].Action != models.ImportOverwritten {
+		t.Errorf("ImportHealthRecords() dry run = %+v, want created and overwritten", dryRun)
+	}
+	if got := stepCount("2024-01-02"); got != 0 {
+		t.Errorf("record of 2024-01-02 has %d steps after the rolled back imports, want none", got)
+	}
+	if got := stepCount("2024-01-01"); got != 8000 {
+		t.Errorf("record of 2024-01-01 has %d steps after the rolled back imports, want 8000", got)
+	}
+
+	// Skipping keeps the stored record and reports it
+	skipped, err := testDB.ImportHealthRecords(ctx, testutils.TestUserID, importSeq(nil, records...), models.ImportOptions{OnConflict: models.ConflictSkip})
+	if err != nil {
+		t.Fatalf("ImportHealthRecords() with the skip policy error = %v", err)
+	}
+	if len(skipped) != 2 {
+		t.Fatalf("ImportHealthRecords() returned %d imports, want 2", len(skipped))
+	}
+	if created := skipped[0]; created.Action != models.ImportCreated || created.Record.ID == 0 || created.Record.StepCount != 7000 {
+		t.Errorf("ImportHealthRecords()[0] = %+v, want a new record with 7000 steps", created)
+	}
+	if kept := skipped[1]; kept.Action != models.ImportSkipped || kept.Record.ID != existing.ID || kept.Record.StepCount != 8000 {
+		t.Errorf("ImportHealthRecords()[1] = %+v, want record %d skipped with its 8000 steps", kept, existing.ID)
+	}
+
+	// Overwriting replaces it
+	overwritten, err := testDB.ImportHealthRecords(ctx, testutils.TestUserID, importSeq(nil, records[1]), models.ImportOptions{OnConflict: models.ConflictOverwrite})
+	if err != nil {
+		t.Fatalf("ImportHealthRecords() with the overwrite policy error = %v", err)
+	}
+	if len(overwritten) != 1 || overwritten[0].Action != models.ImportOverwritten || overwritten[0].Record.Version != 2 {
+		t.Errorf("ImportHealthRecords() with the overwrite policy = %+v, want the record overwritten at version 2", overwritten)
+	}
+	if got := stepCount("2024-01-01"); got != 9500 {
+		t.Errorf("record of 2024-01-01 has %d steps after overwriting, want 9500", got)
+	}
+}
+
+func TestSQLite_HealthRecordVersion(t *testing.T) {
+	testDB, cleanup := testutils.SetupSQLiteTester(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	date := testutils.CreateDate("2024-01-01")
+
+	created, err := testDB.CreateHealthRecord(ctx, testutils.TestUserID, &models.HealthRecord{Date: date, StepCount: 8000})
+	if err != nil {
+		t.Fatalf("CreateHealthRecord() error = %v", err)
+	}
+	if created.Version != 1 {
+		t.Errorf("CreateHealthRecord() version = %d, want 1", created.Version)
+	}
+
+	// An update with the current version succeeds and bumps the version
+	if err := testDB.UpdateHealthRecord(ctx, testutils.TestUserID, &models.HealthRecord{Date: date, StepCount: 9000, Version: 1}); err != nil {
+		t.Fatalf("UpdateHealthRecord() error = %v", err)
+	}
+	if got, _ := testDB.ReadHealthRecord(ctx, testutils.TestUserID, date); got == nil || got.Version != 2 {
+		t.Errorf("ReadHealthRecord() = %+v, want version 2", got)
+	}
+
+	// Writes with the stale version are rejected
+	if err := testDB.UpdateHealthRecord(ctx, testutils.TestUserID, &models.HealthRecord{Date: date, StepCount: 1000, Version: 1}); !errors.Is(err, database.ErrVersionMismatch) {
+		t.Errorf("UpdateHealthRecord() with a stale version error = %v, want %v", err, database.ErrVersionMismatch)
+	}
+	if err := testDB.DeleteHealthRecord(ctx, testutils.TestUserID, date, 1); !errors.Is(err, database.ErrVersionMismatch) {
+		t.Errorf("DeleteHealthRecord() with a stale version error = %v, want %v", err, database.ErrVersionMismatch)
+	}
+	if got, _ := testDB.ReadHealthRecord(ctx, testutils.TestUserID, date); got == nil || got.StepCount != 9000 {
+		t.Errorf("ReadHealthRecord() = %+v, want the record untouched with 9000 steps", got)
+	}
+
+	// Upserts bump the version too
+	stored, _, err := testDB.UpsertHealthRecord(ctx, testutils.TestUserID, &models.HealthRecord{Date: date, StepCount: 9500})
+	if err != nil {
+		t.Fatalf("UpsertHealthRecord() error = %v", err)
+	}
+	if stored.Version != 3 {
+		t.Errorf("UpsertHealthRecord() version = %d, want 3", stored.Version)
+	}
+
+	if err := testDB.DeleteHealthRecord(ctx, testutils.TestUserID, date, 3); err != nil {
+		t.Errorf("DeleteHealthRecord() with the current version error = %v", err)
+	}
+}
+
 func TestSQLite_DeleteHealthRecord(t *testing.T) {
 	testDB, cleanup := testutils.SetupSQLiteTester(t)
 	defer cleanup()
@@ -393,7 +752,7 @@ func TestSQLite_DeleteHealthRecord(t *testing.T) {
 			name:       "error - delete non-existence record",
 			setup:      nil,
 			deleteDate: testutils.CreateDate("2024-01-01"),
-			wantErr:    sql.ErrNoRows,
+			wantErr:    database.ErrNotFound,
 		},
 		{
 			name: "error - delete with different date (future)",
@@ -405,7 +764,7 @@ func TestSQLite_DeleteHealthRecord(t *testing.T) {
 				testutils.CreateTestRecords(ctx, t, db.DB, []models.HealthRecord{*record})
 			},
 			deleteDate: testutils.CreateDate("2025-02-01"),
-			wantErr:    sql.ErrNoRows,
+			wantErr:    database.ErrNotFound,
 		},
 		{
 			name: "error - delete with different date (past)",
@@ -417,7 +776,7 @@ func TestSQLite_DeleteHealthRecord(t *testing.T) {
 				testutils.CreateTestRecords(ctx, t, db.DB, []models.HealthRecord{*record})
 			},
 			deleteDate: testutils.CreateDate("2023-12-31"),
-			wantErr:    sql.ErrNoRows,
+			wantErr:    database.ErrNotFound,
 		},
 	}
 
@@ -430,19 +789,19 @@ func TestSQLite_DeleteHealthRecord(t *testing.T) {
 				tt.setup(t, ctx, testDB)
 			}
 
-			err := testDB.DeleteHealthRecord(ctx, tt.deleteDate)
+			err := testDB.DeleteHealthRecord(ctx, testutils.TestUserID, tt.deleteDate, 0)
 			if !errors.Is(err, tt.wantErr) {
 				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
 			}
 
 			if err == nil {
-				retrieved, _ := testDB.ReadHealthRecord(ctx, tt.deleteDate)
+				retrieved, _ := testDB.ReadHealthRecord(ctx, testutils.TestUserID, tt.deleteDate)
 				if retrieved != nil {
 					t.Errorf("record still exists after deletion")
 				}
 			}
 			if tt.nonDelete != nil {
-				nonAffectRecord, _ := testDB.ReadHealthRecord(ctx, tt.nonDelete.Date)
+				nonAffectRecord, _ := testDB.ReadHealthRecord(ctx, testutils.TestUserID, tt.nonDelete.Date)
 				testutils.AssertHealthRecordEqual(t, nonAffectRecord, tt.nonDelete)
 			}
 		})
@@ -459,7 +818,7 @@ func TestSQLite_ContextCancellation(t *testing.T) {
 		StepCount: 10000,
 	}
 	ctx := context.Background()
-	_, err := testDB.CreateHealthRecord(ctx, record)
+	_, err := testDB.CreateHealthRecord(ctx, testutils.TestUserID, record)
 	if err != nil {
 		t.Fatalf("failed to create test record: %v", err)
 	}
@@ -468,13 +827,47 @@ func TestSQLite_ContextCancellation(t *testing.T) {
 
 	cancel()
 
-	err = testDB.UpdateHealthRecord(ctx, record)
+	err = testDB.UpdateHealthRecord(ctx, testutils.TestUserID, record)
 	if !errors.Is(err, context.Canceled) {
 		t.Errorf("expected context.Canceled, got %v", err)
 	}
 
-	err = testDB.DeleteHealthRecord(ctx, date)
+	err = testDB.DeleteHealthRecord(ctx, testutils.TestUserID, date, 0)
 	if !errors.Is(err, context.Canceled) {
 		t.Errorf("expected context.Canceled, got %v", err)
 	}
 }
+
+func TestSQLite_HealthCheck(t *testing.T) {
+	testDB, cleanup := testutils.SetupSQLiteTester(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	if err := testDB.HealthCheck(ctx); err != nil {
+		t.Errorf("HealthCheck() error = %v", err)
+	}
+	if info := testDB.GetPoolInfo(); info["status"] != "active" {
+		t.Errorf("GetPoolInfo() = %v, want an active pool", info)
+	}
+
+	// Holding the only connection exhausts the pool
+	testDB.SetMaxOpenConns(1)
+	tx, err := testDB.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("BeginTx() error = %v", err)
+	}
+	if err := testDB.HealthCheck(ctx); !errors.Is(err, database.ErrPoolExhausted) {
+		t.Errorf("HealthCheck() with every connection in use error = %v, want %v", err, database.ErrPoolExhausted)
+	}
+	tx.Rollback()
+
+	if err := testDB.HealthCheck(ctx); err != nil {
+		t.Errorf("HealthCheck() after the connection was released error = %v", err)
+	}
+
+	testDB.DB.Close()
+	if err := testDB.HealthCheck(ctx); err == nil {
+		t.Error("HealthCheck() of a closed database returned no error")
+	}
+}
@@ -0,0 +1,238 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/models"
+)
+
+// CreateMetricDefinition inserts a new custom metric definition
+func (db *SQLiteDB) CreateMetricDefinition(ctx context.Context, userID int64, def *models.MetricDefinition) (*models.MetricDefinition, error) {
+	insertStmt, err := db.getStmt("insert_metric_definition")
+	if err != nil {
+		return nil, fmt.Errorf("getting insert statement: %w", err)
+	}
+
+	// enum values are stored as a JSON array
+	enumValues, err := json.Marshal(def.EnumValues)
+	if err != nil {
+		return nil, fmt.Errorf("encode enum values: %w", err)
+	}
+	if def.EnumValues == nil {
+		enumValues = []byte("[]")
+	}
+
+	var created *models.MetricDefinition
+	err = db.withTxContext(ctx, func(tx *sql.Tx) error {
+		stmt := tx.StmtContext(ctx, insertStmt)
+
+		now := time.Now()
+		result, err := stmt.ExecContext(ctx, userID, def.Name, def.Unit, def.ValueType, def.Min, def.Max, string(enumValues), def.Aggregation, now, now)
+		if isSQLiteUniqueViolation(err) {
+			return ErrConflict
+		}
+		if err != nil {
+			return fmt.Errorf("insert metric definition: %w", err)
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("get last insert id: %w", err)
+		}
+
+		d := *def
+		d.ID = id
+		d.CreatedAt = now
+		d.UpdatedAt = now
+		created = &d
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// ReadMetricDefinition retrieves a custom metric definition by name
+func (db *SQLiteDB) ReadMetricDefinition(ctx context.Context, userID int64, name string) (*models.MetricDefinition, error) {
+	selectStmt, err := db.getStmt("select_metric_definition")
+	if err != nil {
+		return nil, fmt.Errorf("getting select statement: %w", err)
+	}
+
+	def, err := scanMetricDefinition(selectStmt.QueryRowContext(ctx, userID, name))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // No error, but no definition found
+		}
+		return nil, err
+	}
+
+	return def, nil
+}
+
+// ReadMetricDefinitions retrieves all custom metric definitions ordered by name
+func (db *SQLiteDB) ReadMetricDefinitions(ctx context.Context, userID int64) ([]models.MetricDefinition, error) {
+	selectStmt, err := db.getStmt("select_metric_definitions")
+	if err != nil {
+		return nil, fmt.Errorf("getting select statement: %w", err)
+	}
+
+	rows, err := selectStmt.QueryContext(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("query metric definitions: %w", err)
+	}
+	defer rows.Close()
+
+	var defs []models.MetricDefinition
+	for rows.Next() {
+		def, err := scanMetricDefinition(rows)
+		if err != nil {
+			return nil, err
+		}
+		defs = append(defs, *def)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating through rows: %w", err)
+	}
+
+	return defs, nil
+}
+
+// DeleteMetricDefinition deletes a custom metric definition together with its recorded values
+func (db *SQLiteDB) DeleteMetricDefinition(ctx context.Context, userID int64, id int64) error {
+	deleteValuesStmt, err := db.getStmt("delete_metric_values")
+	if err != nil {
+		return fmt.Errorf("getting delete values statement: %w", err)
+	}
+	deleteStmt, err := db.getStmt("delete_metric_definition")
+	if err != nil {
+		return fmt.Errorf("getting delete statement: %w", err)
+	}
+
+	return db.withTxContext(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.StmtContext(ctx, deleteValuesStmt).ExecContext(ctx, userID, id); err != nil {
+			return fmt.Errorf("delete metric values: %w", err)
+		}
+
+		result, err := tx.StmtContext(ctx, deleteStmt).ExecContext(ctx, userID, id)
+		if err != nil {
+			return fmt.Errorf("execute delete: %w", err)
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("get rows affected: %w", err)
+		}
+		if affected == 0 {
+			return ErrNotFound
+		}
+
+		return nil
+	})
+}
+
+// CreateMetricValue inserts a new value of a custom metric
+func (db *SQLiteDB) CreateMetricValue(ctx context.Context, userID int64, mv *models.MetricValue) (*models.MetricValue, error) {
+	insertStmt, err := db.getStmt("insert_metric_value")
+	if err != nil {
+		return nil, fmt.Errorf("getting insert statement: %w", err)
+	}
+
+	var created *models.MetricValue
+	err = db.withTxContext(ctx, func(tx *sql.Tx) error {
+		stmt := tx.StmtContext(ctx, insertStmt)
+
+		now := time.Now()
+		result, err := stmt.ExecContext(ctx, userID, mv.MetricID, mv.Date, mv.RecordedAt.UTC(), mv.Value, mv.EnumValue, now, now)
+		if err != nil {
+			return fmt.Errorf("insert metric value: %w", err)
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("get last insert id: %w", err)
+		}
+
+		v := *mv
+		v.ID = id
+		v.CreatedAt = now
+		v.UpdatedAt = now
+		created = &v
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// ReadMetricValuesByRange retrieves the values of a metric between startDate (inclusive) and endDate (exclusive),
+// ordered by recording time
+func (db *SQLiteDB) ReadMetricValuesByRange(ctx context.Context, userID int64, metricID int64, startDate, endDate time.Time) ([]models.MetricValue, error) {
+	selectStmt, err := db.getStmt("select_range_metric_values")
+	if err != nil {
+		return nil, fmt.Errorf("getting select_range statement: %w", err)
+	}
+
+	rows, err := selectStmt.QueryContext(ctx, userID, metricID, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("query metric values: %w", err)
+	}
+	defer rows.Close()
+
+	var values []models.MetricValue
+	for rows.Next() {
+		var mv models.MetricValue
+		if err := rows.Scan(
+			&mv.ID, &mv.MetricID, &mv.Date, &mv.RecordedAt, &mv.Value, &mv.EnumValue, &mv.CreatedAt, &mv.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan metric value: %w", err)
+		}
+		values = append(values, mv)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating through rows: %w", err)
+	}
+
+	return values, nil
+}
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanMetricDefinition scans a metric definition row, decoding the JSON enum values
+func scanMetricDefinition(row rowScanner) (*models.MetricDefinition, error) {
+	var def models.MetricDefinition
+	var enumValues string
+	err := row.Scan(
+		&def.ID, &def.Name, &def.Unit, &def.ValueType, &def.Min, &def.Max, &enumValues, &def.Aggregation, &def.CreatedAt, &def.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("scan metric definition: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(enumValues), &def.EnumValues); err != nil {
+		return nil, fmt.Errorf("decode enum values: %w", err)
+	}
+	if len(def.EnumValues) == 0 {
+		def.EnumValues = nil
+	}
+
+	return &def, nil
+}
@@ -0,0 +1,93 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/database"
+	"github.com/nnamm/go-health-tracker/internal/models"
+	"github.com/nnamm/go-health-tracker/testutils"
+)
+
+func TestSQLite_MetricDefinitions(t *testing.T) {
+	testDB, cleanup := testutils.SetupSQLiteTester(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	maxGlasses := 30.0
+
+	for _, def := range []*models.MetricDefinition{
+		{Name: "water_glasses", Unit: "glass", ValueType: models.MetricValueTypeInt, Max: &maxGlasses, Aggregation: models.MetricAggregationSum},
+		{Name: "mood", ValueType: models.MetricValueTypeEnum, EnumValues: []string{"low", "ok", "high"}, Aggregation: models.MetricAggregationLast},
+	} {
+		if _, err := testDB.CreateMetricDefinition(ctx, testutils.TestUserID, def); err != nil {
+			t.Fatalf("CreateMetricDefinition(%s) error = %v", def.Name, err)
+		}
+	}
+
+	// duplicate names are rejected by the unique constraint
+	if _, err := testDB.CreateMetricDefinition(ctx, testutils.TestUserID, &models.MetricDefinition{Name: "mood", ValueType: models.MetricValueTypeFloat, Aggregation: models.MetricAggregationAvg}); !errors.Is(err, database.ErrConflict) {
+		t.Errorf("CreateMetricDefinition() with a duplicate name error = %v, want %v", err, database.ErrConflict)
+	}
+
+	defs, err := testDB.ReadMetricDefinitions(ctx, testutils.TestUserID)
+	if err != nil {
+		t.Fatalf("ReadMetricDefinitions() error = %v", err)
+	}
+	if len(defs) != 2 || defs[0].Name != "mood" || defs[1].Name != "water_glasses" {
+		t.Fatalf("definitions = %+v, want mood and water_glasses ordered by name", defs)
+	}
+	if len(defs[0].EnumValues) != 3 || defs[0].Min != nil {
+		t.Errorf("mood = %+v, want 3 enum values and no range", defs[0])
+	}
+	if defs[1].Max == nil || *defs[1].Max != maxGlasses || defs[1].EnumValues != nil {
+		t.Errorf("water_glasses = %+v, want max 30 and no enum values", defs[1])
+	}
+
+	missing, err := testDB.ReadMetricDefinition(ctx, testutils.TestUserID, "steps")
+	if err != nil || missing != nil {
+		t.Errorf("ReadMetricDefinition() of an unknown metric = %v, %v, want nil, nil", missing, err)
+	}
+}
+
+func TestSQLite_MetricValues(t *testing.T) {
+	testDB, cleanup := testutils.SetupSQLiteTester(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	def, err := testDB.CreateMetricDefinition(ctx, testutils.TestUserID, &models.MetricDefinition{Name: "water_glasses", ValueType: models.MetricValueTypeInt, Aggregation: models.MetricAggregationSum})
+	if err != nil {
+		t.Fatalf("CreateMetricDefinition() error = %v", err)
+	}
+
+	day := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	for i, v := range []float64{3, 2, 4} {
+		mv := &models.MetricValue{MetricID: def.ID, RecordedAt: day.Add(time.Duration(i) * 12 * time.Hour), Value: &v}
+		mv.SetDerivedFields()
+		if _, err := testDB.CreateMetricValue(ctx, testutils.TestUserID, mv); err != nil {
+			t.Fatalf("CreateMetricValue() error = %v", err)
+		}
+	}
+
+	values, err := testDB.ReadMetricValuesByRange(ctx, testutils.TestUserID, def.ID, day.Truncate(24*time.Hour), day.Truncate(24*time.Hour).AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("ReadMetricValuesByRange() error = %v", err)
+	}
+	if len(values) != 2 || *values[0].Value != 3 || *values[1].Value != 2 {
+		t.Errorf("values = %+v, want 3 then 2 on the first day", values)
+	}
+
+	// deleting the definition removes its values
+	if err := testDB.DeleteMetricDefinition(ctx, testutils.TestUserID, def.ID); err != nil {
+		t.Fatalf("DeleteMetricDefinition() error = %v", err)
+	}
+	values, err = testDB.ReadMetricValuesByRange(ctx, testutils.TestUserID, def.ID, day.AddDate(0, 0, -1), day.AddDate(0, 0, 2))
+	if err != nil || len(values) != 0 {
+		t.Errorf("values after deletion = %v, %v, want none", values, err)
+	}
+	if err := testDB.DeleteMetricDefinition(ctx, testutils.TestUserID, def.ID); !errors.Is(err, database.ErrNotFound) {
+		t.Errorf("DeleteMetricDefinition() on missing row error = %v, want %v", err, database.ErrNotFound)
+	}
+}
@@ -0,0 +1,204 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/config"
+)
+
+// createSQLiteMigrationsTable records the applied migrations
+const createSQLiteMigrationsTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version INTEGER PRIMARY KEY,
+	name TEXT NOT NULL,
+	applied_at DATETIME NOT NULL
+)`
+
+// MigrateUp applies all pending migrations, each in its own transaction
+func (db *SQLiteDB) MigrateUp(ctx context.Context) ([]Migration, error) {
+	migrations, applied, err := db.migrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, m := range pendingMigrations(migrations, applied) {
+		err := db.withTxContext(ctx, func(tx *sql.Tx) error {
+			if err := applySQLiteMigration(ctx, tx, m); err != nil {
+				return fmt.Errorf("apply migration %d: %w", m.Version, err)
+			}
+			if _, err := tx.ExecContext(ctx, "INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)", m.Version, m.Name, time.Now().UTC()); err != nil {
+				return fmt.Errorf("record migration %d: %w", m.Version, err)
+			}
+			return nil
+		})
+		if err != nil {
+			return done, err
+		}
+		done = append(done, m)
+	}
+
+	return done, nil
+}
+
+// MigrateDown reverts the last steps applied migrations, each in its own transaction
+func (db *SQLiteDB) MigrateDown(ctx context.Context, steps int) ([]Migration, error) {
+	migrations, applied, err := db.migrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	revert, err := revertibleMigrations(migrations, applied, steps)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, m := range revert {
+		err := db.withTxContext(ctx, func(tx *sql.Tx) error {
+			if !m.Code {
+				if _, err := tx.ExecContext(ctx, m.Down); err != nil {
+					return fmt.Errorf("revert migration %d: %w", m.Version, err)
+				}
+			}
+			if _, err := tx.ExecContext(ctx, "DELETE FROM schema_migrations WHERE version = ?", m.Version); err != nil {
+				return fmt.Errorf("unrecord migration %d: %w", m.Version, err)
+			}
+			return nil
+		})
+		if err != nil {
+			return done, err
+		}
+		done = append(done, m)
+	}
+
+	return done, nil
+}
+
+// MigrationStatus lists all known migrations and when they were applied
+func (db *SQLiteDB) MigrationStatus(ctx context.Context) ([]MigrationStatus, error) {
+	migrations, applied, err := db.migrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return migrationStatuses(migrations, applied), nil
+}
+
+// migrations loads the SQLite migrations and the versions applied to the database
+func (db *SQLiteDB) migrations(ctx context.Context) ([]Migration, map[int]time.Time, error) {
+	migrations, err := loadMigrations(dialectSQLite)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, err := db.ExecContext(ctx, createSQLiteMigrationsTable); err != nil {
+		return nil, nil, fmt.Errorf("create migrations table: %w", err)
+	}
+
+	rows, err := db.QueryContext(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, nil, fmt.Errorf("query applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, nil, fmt.Errorf("scan applied migration: %w", err)
+		}
+		applied[version] = appliedAt
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("iterate applied migrations: %w", err)
+	}
+
+	return migrations, applied, nil
+}
+
+// applySQLiteMigration runs the SQL of a migration, or its Go implementation for code migrations
+func applySQLiteMigration(ctx context.Context, tx *sql.Tx, m Migration) error {
+	if !m.Code {
+		_, err := tx.ExecContext(ctx, m.Up)
+		return err
+	}
+
+	switch m.Version {
+	case migrationLegacyHealthRecords:
+		return upgradeSQLiteLegacyHealthRecords(ctx, tx)
+	default:
+		return fmt.Errorf("code migration %d is not implemented", m.Version)
+	}
+}
+
+// upgradeSQLiteLegacyHealthRecords brings a health_records table created before schema migrations
+// to the shape of migration 1. A table without user_id belongs to the single-user schema: it is rebuilt
+// with its records assigned to the default user, since SQLite can't change a UNIQUE constraint in place.
+// A table that is only missing the version column gets it added. New databases are left as they are.
+func upgradeSQLiteLegacyHealthRecords(ctx context.Context, tx *sql.Tx) error {
+	var columns, hasUserID, hasVersion int
+	err := tx.QueryRowContext(ctx, `SELECT COUNT(*), COALESCE(SUM(name = 'user_id'), 0), COALESCE(SUM(name = 'version'), 0) FROM pragma_table_info('health_records')`).
+		Scan(&columns, &hasUserID, &hasVersion)
+	if err != nil {
+		return fmt.Errorf("inspect health_records: %w", err)
+	}
+
+	switch {
+	case columns == 0 || (hasUserID > 0 && hasVersion > 0):
+		return nil
+	case hasUserID > 0:
+		if _, err := tx.ExecContext(ctx, `ALTER TABLE health_records ADD COLUMN version INTEGER NOT NULL DEFAULT 1`); err != nil {
+			return fmt.Errorf("add version column: %w", err)
+		}
+		return nil
+	}
+
+	userID, err := sqliteDefaultUser(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `ALTER TABLE health_records RENAME TO legacy_health_records`); err != nil {
+		return fmt.Errorf("rename legacy health_records: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `CREATE TABLE health_records (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL REFERENCES users(id),
+		date DATE NOT NULL,
+		step_count INTEGER NOT NULL,
+		version INTEGER NOT NULL DEFAULT 1,
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL,
+		UNIQUE (user_id, date)
+	)`); err != nil {
+		return fmt.Errorf("create health_records: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO health_records (id, user_id, date, step_count, version, created_at, updated_at)
+		SELECT id, ?, date, step_count, 1, created_at, updated_at FROM legacy_health_records`, userID); err != nil {
+		return fmt.Errorf("copy legacy health records: %w", err)
+	}
+	// Also drops the legacy unique index on date
+	if _, err := tx.ExecContext(ctx, `DROP TABLE legacy_health_records`); err != nil {
+		return fmt.Errorf("drop legacy health_records: %w", err)
+	}
+
+	return nil
+}
+
+// sqliteDefaultUser returns the ID of the user requests are attributed to without authentication,
+// creating it like the server does on start
+func sqliteDefaultUser(ctx context.Context, tx *sql.Tx) (int64, error) {
+	now := time.Now()
+	if _, err := tx.ExecContext(ctx, `INSERT INTO users (email, created_at, updated_at) VALUES (?, ?, ?) ON CONFLICT (email) DO NOTHING`, config.DefaultUserEmail, now, now); err != nil {
+		return 0, fmt.Errorf("create default user: %w", err)
+	}
+
+	var userID int64
+	if err := tx.QueryRowContext(ctx, `SELECT id FROM users WHERE email = ?`, config.DefaultUserEmail).Scan(&userID); err != nil {
+		return 0, fmt.Errorf("read default user: %w", err)
+	}
+	return userID, nil
+}
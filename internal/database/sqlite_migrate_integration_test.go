@@ -0,0 +1,166 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/config"
+	"github.com/nnamm/go-health-tracker/internal/database"
+	"github.com/nnamm/go-health-tracker/internal/models"
+	"github.com/nnamm/go-health-tracker/testutils"
+)
+
+func TestSQLite_Migrations(t *testing.T) {
+	db, err := database.OpenSQLiteDB(filepath.Join(t.TempDir(), "migrate.db"))
+	if err != nil {
+		t.Fatalf("OpenSQLiteDB() error = %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+
+	statuses, err := db.MigrationStatus(ctx)
+	if err != nil {
+		t.Fatalf("MigrationStatus() error = %v", err)
+	}
+	if len(statuses) == 0 {
+		t.Fatal("MigrationStatus() returned no migrations")
+	}
+	for _, s := range statuses {
+		if s.AppliedAt != nil {
+			t.Errorf("migration %d is applied before MigrateUp()", s.Version)
+		}
+	}
+
+	applied, err := db.MigrateUp(ctx)
+	if err != nil {
+		t.Fatalf("MigrateUp() error = %v", err)
+	}
+	if len(applied) != len(statuses) {
+		t.Errorf("MigrateUp() applied %d migrations, want %d", len(applied), len(statuses))
+	}
+	if _, err := db.Exec("SELECT count(*) FROM health_records"); err != nil {
+		t.Errorf("health_records is missing after MigrateUp(): %v", err)
+	}
+
+	// Applying again is a no-op
+	if again, err := db.MigrateUp(ctx); err != nil || len(again) != 0 {
+		t.Errorf("second MigrateUp() = %d migrations, %v, want none", len(again), err)
+	}
+
+	statuses, err = db.MigrationStatus(ctx)
+	if err != nil {
+		t.Fatalf("MigrationStatus() error = %v", err)
+	}
+	for _, s := range statuses {
+		if s.AppliedAt == nil {
+			t.Errorf("migration %d is pending after MigrateUp()", s.Version)
+		}
+	}
+
+	latest := statuses[len(statuses)-1]
+	reverted, err := db.MigrateDown(ctx, 1)
+	if err != nil {
+		t.Fatalf("MigrateDown() error = %v", err)
+	}
+	if len(reverted) != 1 || reverted[0].Version != latest.Version {
+		t.Errorf("MigrateDown(1) reverted %+v, want migration %d", reverted, latest.Version)
+	}
+
+	statuses, err = db.MigrationStatus(ctx)
+	if err != nil {
+		t.Fatalf("MigrationStatus() error = %v", err)
+	}
+	if s := statuses[len(statuses)-1]; s.AppliedAt != nil {
+		t.Errorf("migration %d is still applied after MigrateDown()", s.Version)
+	}
+
+	// Reverting more steps than applied stops at the first migration
+	if _, err := db.MigrateDown(ctx, len(statuses)+1); err != nil {
+		t.Fatalf("MigrateDown() of all migrations error = %v", err)
+	}
+	if _, err := db.Exec("SELECT count(*) FROM health_records"); err == nil {
+		t.Error("health_records still exists after reverting all migrations")
+	}
+}
+
+func TestSQLite_MigrateLegacySchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "legacy.db")
+
+	// The single-user schema created before schema migrations existed
+	legacy, err := database.OpenSQLiteDB(path)
+	if err != nil {
+		t.Fatalf("OpenSQLiteDB() error = %v", err)
+	}
+	created := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS health_records (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			date DATE NOT NULL UNIQUE,
+			step_count INTEGER NOT NULL,
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL
+		)`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_health_records_date on health_records(date)`,
+	}
+	for _, statement := range statements {
+		if _, err := legacy.Exec(statement); err != nil {
+			t.Fatalf("create legacy schema: %v", err)
+		}
+	}
+	for i, date := range []string{"2024-01-01", "2024-01-02"} {
+		if _, err := legacy.Exec(`INSERT INTO health_records (date, step_count, created_at, updated_at) VALUES (?, ?, ?, ?)`,
+			testutils.CreateDate(date), 1000*(i+1), created, created); err != nil {
+			t.Fatalf("insert legacy record: %v", err)
+		}
+	}
+	legacy.Close()
+
+	// Opening it with the current code migrates the schema and prepares the statements
+	db, err := database.NewSQLiteDB(path)
+	if err != nil {
+		t.Fatalf("NewSQLiteDB() of a legacy database error = %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+
+	owner, err := db.ReadUserByEmail(ctx, config.DefaultUserEmail)
+	if err != nil || owner == nil {
+		t.Fatalf("ReadUserByEmail() of the default user = %+v, %v, want the user the records were assigned to", owner, err)
+	}
+
+	records, err := db.ReadHealthRecordsByRange(ctx, owner.ID, testutils.CreateDate("2024-01-01"), testutils.CreateDate("2024-01-03"))
+	if err != nil {
+		t.Fatalf("ReadHealthRecordsByRange() error = %v", err)
+	}
+	if len(records) != 2 || records[0].StepCount != 1000 || records[1].StepCount != 2000 {
+		t.Fatalf("ReadHealthRecordsByRange() = %+v, want the two legacy records", records)
+	}
+	for _, r := range records {
+		if r.Version != 1 {
+			t.Errorf("legacy record %s has version %d, want 1", r.Date.Format("2006-01-02"), r.Version)
+		}
+	}
+
+	// The date is unique per user now, not per database
+	date := testutils.CreateDate("2024-01-01")
+	if _, err := db.CreateHealthRecord(ctx, owner.ID, &models.HealthRecord{Date: date, StepCount: 1}); !errors.Is(err, database.ErrConflict) {
+		t.Errorf("CreateHealthRecord() of a legacy date error = %v, want %v", err, database.ErrConflict)
+	}
+	other, err := db.CreateUser(ctx, &models.User{Email: "other@example.com"})
+	if err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+	if _, err := db.CreateHealthRecord(ctx, other.ID, &models.HealthRecord{Date: date, StepCount: 1}); err != nil {
+		t.Errorf("CreateHealthRecord() of a legacy date for another user error = %v", err)
+	}
+
+	// Writes use the version column
+	if err := db.UpdateHealthRecord(ctx, owner.ID, &models.HealthRecord{Date: date, StepCount: 1500, Version: 1}); err != nil {
+		t.Errorf("UpdateHealthRecord() with the legacy version error = %v", err)
+	}
+}
@@ -0,0 +1,195 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/models"
+)
+
+// CreateSleepSession inserts a new sleep session.
+// ErrConflict is returned if it overlaps a session already stored.
+func (db *SQLiteDB) CreateSleepSession(ctx context.Context, userID int64, s *models.SleepSession) (*models.SleepSession, error) {
+	insertStmt, err := db.getStmt("insert_sleep_session")
+	if err != nil {
+		return nil, fmt.Errorf("getting insert statement: %w", err)
+	}
+
+	var created *models.SleepSession
+	err = db.withTxContext(ctx, func(tx *sql.Tx) error {
+		stmt := tx.StmtContext(ctx, insertStmt)
+
+		// Times are stored in UTC so that range comparisons on the text column stay ordered
+		now := time.Now()
+		result, err := stmt.ExecContext(ctx, userID, s.Date, s.BedTime.UTC(), s.WakeTime.UTC(), s.DurationMinutes, s.Quality, s.IsNap, now, now)
+		if err != nil {
+			return fmt.Errorf("insert sleep session: %w", err)
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("get last insert id: %w", err)
+		}
+
+		if err := db.checkSleepSessionOverlap(ctx, tx, userID, id, s); err != nil {
+			return err
+		}
+
+		created = &models.SleepSession{
+			ID:              id,
+			Date:            s.Date,
+			BedTime:         s.BedTime,
+			WakeTime:        s.WakeTime,
+			DurationMinutes: s.DurationMinutes,
+			Quality:         s.Quality,
+			IsNap:           s.IsNap,
+			CreatedAt:       now,
+			UpdatedAt:       now,
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// ReadSleepSession retrieves a sleep session by ID
+func (db *SQLiteDB) ReadSleepSession(ctx context.Context, userID int64, id int64) (*models.SleepSession, error) {
+	selectStmt, err := db.getStmt("select_sleep_session")
+	if err != nil {
+		return nil, fmt.Errorf("getting select statement: %w", err)
+	}
+
+	s := &models.SleepSession{}
+	err = selectStmt.QueryRowContext(ctx, userID, id).Scan(
+		&s.ID, &s.Date, &s.BedTime, &s.WakeTime, &s.DurationMinutes, &s.Quality, &s.IsNap, &s.CreatedAt, &s.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // No error, but no session found
+		}
+		return nil, fmt.Errorf("scan sleep session: %w", err)
+	}
+
+	return s, nil
+}
+
+// ReadSleepSessionsByRange retrieves sleep sessions whose date is between startDate (inclusive) and endDate (exclusive)
+func (db *SQLiteDB) ReadSleepSessionsByRange(ctx context.Context, userID int64, startDate, endDate time.Time) ([]models.SleepSession, error) {
+	return db.querySleepSessions(ctx, "select_range_sleep_session", userID, startDate, endDate)
+}
+
+// ReadOverlappingSleepSessions retrieves sleep sessions that overlap the period from bedTime to wakeTime
+func (db *SQLiteDB) ReadOverlappingSleepSessions(ctx context.Context, userID int64, bedTime, wakeTime time.Time) ([]models.SleepSession, error) {
+	return db.querySleepSessions(ctx, "select_overlap_sleep_session", userID, wakeTime.UTC(), bedTime.UTC())
+}
+
+// querySleepSessions runs a prepared sleep session query and scans all rows
+func (db *SQLiteDB) querySleepSessions(ctx context.Context, stmtName string, args ...any) ([]models.SleepSession, error) {
+	selectStmt, err := db.getStmt(stmtName)
+	if err != nil {
+		return nil, fmt.Errorf("getting %s statement: %w", stmtName, err)
+	}
+
+	rows, err := selectStmt.QueryContext(ctx, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query sleep sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []models.SleepSession
+	for rows.Next() {
+		var s models.SleepSession
+		if err := rows.Scan(
+			&s.ID, &s.Date, &s.BedTime, &s.WakeTime, &s.DurationMinutes, &s.Quality, &s.IsNap, &s.CreatedAt, &s.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan sleep session: %w", err)
+		}
+		sessions = append(sessions, s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating through rows: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// checkSleepSessionOverlap returns ErrConflict if a session other than id overlaps s.
+// It runs after the write in the same transaction: the write takes the database's write lock,
+// so a concurrent write of an overlapping session waits for this transaction and then sees its session.
+func (db *SQLiteDB) checkSleepSessionOverlap(ctx context.Context, tx *sql.Tx, userID, id int64, s *models.SleepSession) error {
+	countStmt, err := db.getStmt("count_overlap_sleep_session")
+	if err != nil {
+		return fmt.Errorf("getting count overlap statement: %w", err)
+	}
+
+	var overlapping int
+	if err := tx.StmtContext(ctx, countStmt).QueryRowContext(ctx, userID, id, s.WakeTime.UTC(), s.BedTime.UTC()).Scan(&overlapping); err != nil {
+		return fmt.Errorf("count overlapping sleep sessions: %w", err)
+	}
+	if overlapping > 0 {
+		return ErrConflict
+	}
+
+	return nil
+}
+
+// UpdateSleepSession updates an existing sleep session.
+// ErrConflict is returned if the new times overlap another session.
+func (db *SQLiteDB) UpdateSleepSession(ctx context.Context, userID int64, s *models.SleepSession) error {
+	updateStmt, err := db.getStmt("update_sleep_session")
+	if err != nil {
+		return fmt.Errorf("getting update statement: %w", err)
+	}
+
+	return db.withTxContext(ctx, func(tx *sql.Tx) error {
+		stmt := tx.StmtContext(ctx, updateStmt)
+		now := time.Now()
+		result, err := stmt.ExecContext(ctx, s.Date, s.BedTime.UTC(), s.WakeTime.UTC(), s.DurationMinutes, s.Quality, s.IsNap, now, userID, s.ID)
+		if err != nil {
+			return fmt.Errorf("execute update: %w", err)
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("get rows affected: %w", err)
+		}
+		if affected == 0 {
+			return ErrNotFound
+		}
+
+		return db.checkSleepSessionOverlap(ctx, tx, userID, s.ID, s)
+	})
+}
+
+// DeleteSleepSession deletes a sleep session by ID
+func (db *SQLiteDB) DeleteSleepSession(ctx context.Context, userID int64, id int64) error {
+	deleteStmt, err := db.getStmt("delete_sleep_session")
+	if err != nil {
+		return fmt.Errorf("getting delete statement: %w", err)
+	}
+
+	return db.withTxContext(ctx, func(tx *sql.Tx) error {
+		stmt := tx.StmtContext(ctx, deleteStmt)
+		result, err := stmt.ExecContext(ctx, userID, id)
+		if err != nil {
+			return fmt.Errorf("execute delete: %w", err)
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("get rows affected: %w", err)
+		}
+		if affected == 0 {
+			return ErrNotFound
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,191 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/database"
+	"github.com/nnamm/go-health-tracker/internal/models"
+	"github.com/nnamm/go-health-tracker/testutils"
+)
+
+func newSleepSession(bed, wake time.Time) *models.SleepSession {
+	s := &models.SleepSession{BedTime: bed, WakeTime: wake}
+	s.SetDerivedFields()
+	return s
+}
+
+func TestSQLite_SleepSessionCRUD(t *testing.T) {
+	testDB, cleanup := testutils.SetupSQLiteTester(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	bed := time.Date(2024, 5, 1, 23, 0, 0, 0, time.UTC)
+
+	created, err := testDB.CreateSleepSession(ctx, testutils.TestUserID, newSleepSession(bed, bed.Add(7*time.Hour)))
+	if err != nil {
+		t.Fatalf("CreateSleepSession() error = %v", err)
+	}
+	if created.ID == 0 || created.DurationMinutes != 420 {
+		t.Errorf("created session = %+v, want id set and 420 minutes", created)
+	}
+
+	// update
+	update := newSleepSession(bed, bed.Add(8*time.Hour))
+	update.ID = created.ID
+	if err := testDB.UpdateSleepSession(ctx, testutils.TestUserID, update); err != nil {
+		t.Fatalf("UpdateSleepSession() error = %v", err)
+	}
+	got, err := testDB.ReadSleepSession(ctx, testutils.TestUserID, created.ID)
+	if err != nil {
+		t.Fatalf("ReadSleepSession() error = %v", err)
+	}
+	if got.DurationMinutes != 480 || !got.WakeTime.Equal(update.WakeTime) {
+		t.Errorf("updated session = %+v, want 480 minutes", got)
+	}
+
+	// delete
+	if err := testDB.DeleteSleepSession(ctx, testutils.TestUserID, created.ID); err != nil {
+		t.Fatalf("DeleteSleepSession() error = %v", err)
+	}
+	if got, _ := testDB.ReadSleepSession(ctx, testutils.TestUserID, created.ID); got != nil {
+		t.Errorf("session still exists after deletion")
+	}
+
+	// missing rows
+	if err := testDB.UpdateSleepSession(ctx, testutils.TestUserID, update); !errors.Is(err, database.ErrNotFound) {
+		t.Errorf("UpdateSleepSession() on missing row error = %v, want %v", err, database.ErrNotFound)
+	}
+	if err := testDB.DeleteSleepSession(ctx, testutils.TestUserID, created.ID); !errors.Is(err, database.ErrNotFound) {
+		t.Errorf("DeleteSleepSession() on missing row error = %v, want %v", err, database.ErrNotFound)
+	}
+}
+
+func TestSQLite_ReadSleepSessions(t *testing.T) {
+	testDB, cleanup := testutils.SetupSQLiteTester(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	night := time.Date(2024, 5, 1, 23, 0, 0, 0, time.UTC)
+	sessions := []*models.SleepSession{
+		newSleepSession(night, night.Add(7*time.Hour)),                      // woke 2024-05-02
+		newSleepSession(night.Add(14*time.Hour), night.Add(15*time.Hour)),   // nap 2024-05-02
+		newSleepSession(night.Add(24*time.Hour), night.Add(31*time.Hour)),   // woke 2024-05-03
+		newSleepSession(night.Add(-24*time.Hour), night.Add(-17*time.Hour)), // woke 2024-05-01
+	}
+	for _, s := range sessions {
+		if _, err := testDB.CreateSleepSession(ctx, testutils.TestUserID, s); err != nil {
+			t.Fatalf("failed to create sleep session: %v", err)
+		}
+	}
+
+	t.Run("by wake date includes naps", func(t *testing.T) {
+		got, err := testDB.ReadSleepSessionsByRange(ctx, testutils.TestUserID, testutils.CreateDate("2024-05-02"), testutils.CreateDate("2024-05-03"))
+		if err != nil {
+			t.Fatalf("ReadSleepSessionsByRange() error = %v", err)
+		}
+		if len(got) != 2 {
+			t.Fatalf("session count = %d, want 2", len(got))
+		}
+		if got[0].DurationMinutes != 420 || got[1].DurationMinutes != 60 {
+			t.Errorf("sessions not ordered by bed time: %+v", got)
+		}
+	})
+
+	t.Run("overlapping sessions", func(t *testing.T) {
+		got, err := testDB.ReadOverlappingSleepSessions(ctx, testutils.TestUserID, night.Add(6*time.Hour), night.Add(14*time.Hour+30*time.Minute))
+		if err != nil {
+			t.Fatalf("ReadOverlappingSleepSessions() error = %v", err)
+		}
+		if len(got) != 2 {
+			t.Errorf("overlapping count = %d, want 2", len(got))
+		}
+	})
+
+	t.Run("touching sessions do not overlap", func(t *testing.T) {
+		got, err := testDB.ReadOverlappingSleepSessions(ctx, testutils.TestUserID, night.Add(7*time.Hour), night.Add(14*time.Hour))
+		if err != nil {
+			t.Fatalf("ReadOverlappingSleepSessions() error = %v", err)
+		}
+		if len(got) != 0 {
+			t.Errorf("overlapping count = %d, want 0", len(got))
+		}
+	})
+}
+
+func TestSQLite_SleepSessionOverlap(t *testing.T) {
+	testDB, cleanup := testutils.SetupSQLiteTester(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	base := time.Date(2024, 5, 1, 23, 0, 0, 0, time.UTC)
+	existing, err := testDB.CreateSleepSession(ctx, testutils.TestUserID, newSleepSession(base, base.Add(8*time.Hour)))
+	if err != nil {
+		t.Fatalf("failed to create sleep session: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		session *models.SleepSession
+		wantErr error
+	}{
+		{
+			name:    "session touching the existing one",
+			session: newSleepSession(base.Add(8*time.Hour), base.Add(9*time.Hour)),
+		},
+		{
+			name:    "overlapping session",
+			session: newSleepSession(base.Add(7*time.Hour), base.Add(9*time.Hour)),
+			wantErr: database.ErrConflict,
+		},
+		{
+			name:    "session containing the existing one",
+			session: newSleepSession(base.Add(-time.Hour), base.Add(10*time.Hour)),
+			wantErr: database.ErrConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created, err := testDB.CreateSleepSession(ctx, testutils.TestUserID, tt.session)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreateSleepSession() error = %v, want %v", err, tt.wantErr)
+			}
+			if created != nil {
+				testDB.DeleteSleepSession(ctx, testutils.TestUserID, created.ID)
+			}
+		})
+	}
+
+	t.Run("rejected session is not stored", func(t *testing.T) {
+		got, err := testDB.ReadSleepSessionsByRange(ctx, testutils.TestUserID, testutils.CreateDate("2024-05-01"), testutils.CreateDate("2024-05-04"))
+		if err != nil {
+			t.Fatalf("ReadSleepSessionsByRange() error = %v", err)
+		}
+		if len(got) != 1 {
+			t.Errorf("session count = %d, want 1", len(got))
+		}
+	})
+
+	t.Run("update of the same session", func(t *testing.T) {
+		update := newSleepSession(base.Add(time.Hour), base.Add(9*time.Hour))
+		update.ID = existing.ID
+		if err := testDB.UpdateSleepSession(ctx, testutils.TestUserID, update); err != nil {
+			t.Errorf("UpdateSleepSession() error = %v", err)
+		}
+	})
+
+	t.Run("update overlapping another session", func(t *testing.T) {
+		nap, err := testDB.CreateSleepSession(ctx, testutils.TestUserID, newSleepSession(base.Add(14*time.Hour), base.Add(15*time.Hour)))
+		if err != nil {
+			t.Fatalf("failed to create sleep session: %v", err)
+		}
+		update := newSleepSession(base.Add(8*time.Hour), base.Add(14*time.Hour+30*time.Minute))
+		update.ID = nap.ID
+		if err := testDB.UpdateSleepSession(ctx, testutils.TestUserID, update); !errors.Is(err, database.ErrConflict) {
+			t.Errorf("UpdateSleepSession() error = %v, want %v", err, database.ErrConflict)
+		}
+	})
+}
@@ -0,0 +1,47 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/models"
+)
+
+// ReadStepCountStats computes the step count statistics of the records between startDate (inclusive)
+// and endDate (exclusive), per period of the grouping
+func (db *SQLiteDB) ReadStepCountStats(ctx context.Context, userID int64, startDate, endDate time.Time, groupBy models.StatsGrouping) ([]models.StepCountStats, error) {
+	selectStmt, err := db.getStmt(statsStmtName(groupBy))
+	if err != nil {
+		return nil, fmt.Errorf("getting stats statement: %w", err)
+	}
+
+	rows, err := selectStmt.QueryContext(ctx, userID, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("query stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []models.StepCountStats
+	for rows.Next() {
+		s, err := scanStepCountStats(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan stats: %w", err)
+		}
+		stats = append(stats, *s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating through rows: %w", err)
+	}
+
+	return stats, nil
+}
+
+// statsStmtName returns the name of the prepared statistics statement for a grouping
+func statsStmtName(groupBy models.StatsGrouping) string {
+	if groupBy == models.StatsGroupNone {
+		return "select_stats_health_record"
+	}
+	return "select_stats_health_record_" + string(groupBy)
+}
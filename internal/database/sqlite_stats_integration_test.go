@@ -0,0 +1,112 @@
+package database_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/nnamm/go-health-tracker/internal/models"
+	"github.com/nnamm/go-health-tracker/testutils"
+)
+
+func TestSQLite_ReadStepCountStats(t *testing.T) {
+	testDB, cleanup := testutils.SetupSQLiteTester(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	records := []models.HealthRecord{
+		{Date: testutils.CreateDate("2024-01-01"), StepCount: 4000}, // Monday, ISO week 2024-W01
+		{Date: testutils.CreateDate("2024-01-02"), StepCount: 10000},
+		{Date: testutils.CreateDate("2024-01-03"), StepCount: 6000},
+		{Date: testutils.CreateDate("2024-01-04"), StepCount: 10000},
+		{Date: testutils.CreateDate("2024-01-08"), StepCount: 8000}, // ISO week 2024-W02
+		{Date: testutils.CreateDate("2024-02-01"), StepCount: 2000}, // outside the range
+	}
+	testutils.CreateTestRecords(ctx, t, testDB.DB, records)
+
+	start, end := testutils.CreateDate("2024-01-01"), testutils.CreateDate("2024-02-01")
+
+	t.Run("whole range", func(t *testing.T) {
+		stats, err := testDB.ReadStepCountStats(ctx, testutils.TestUserID, start, end, models.StatsGroupNone)
+		if err != nil {
+			t.Fatalf("ReadStepCountStats() error = %v", err)
+		}
+		if len(stats) != 1 {
+			t.Fatalf("got %d groups, want 1", len(stats))
+		}
+
+		s := stats[0]
+		if s.Count != 5 || s.Sum != 38000 || s.Mean != 7600 {
+			t.Errorf("count, sum, mean = %d, %d, %v, want 5, 38000, 7600", s.Count, s.Sum, s.Mean)
+		}
+		if s.Median != 8000 {
+			t.Errorf("median = %v, want 8000", s.Median)
+		}
+		if s.Min.StepCount != 4000 || !s.Min.Date.Equal(testutils.CreateDate("2024-01-01")) {
+			t.Errorf("min = %+v, want 4000 on 2024-01-01", s.Min)
+		}
+		// Ties report the earliest date
+		if s.Max.StepCount != 10000 || !s.Max.Date.Equal(testutils.CreateDate("2024-01-02")) {
+			t.Errorf("max = %+v, want 10000 on 2024-01-02", s.Max)
+		}
+		// population standard deviation of 4000, 6000, 8000, 10000, 10000
+		if want := math.Sqrt(5440000); math.Abs(s.StdDev-want) > 1e-6 {
+			t.Errorf("stddev = %v, want %v", s.StdDev, want)
+		}
+		// percentiles are interpolated between ranks: p25 at rank 2, p90 between ranks 4 and 5
+		for name, want := range map[string]float64{"p25": 6000, "p50": 8000, "p75": 10000, "p90": 10000, "p95": 10000} {
+			if got := s.Percentiles[name]; got != want {
+				t.Errorf("%s = %v, want %v", name, got, want)
+			}
+		}
+	})
+
+	t.Run("interpolated percentile", func(t *testing.T) {
+		stats, err := testDB.ReadStepCountStats(ctx, testutils.TestUserID, start, testutils.CreateDate("2024-01-04"), models.StatsGroupNone)
+		if err != nil {
+			t.Fatalf("ReadStepCountStats() error = %v", err)
+		}
+		// 4000, 6000, 10000: p25 lies halfway between the first and second value
+		if got := stats[0].Percentiles["p25"]; got != 5000 {
+			t.Errorf("p25 = %v, want 5000", got)
+		}
+	})
+
+	tests := []struct {
+		groupBy     models.StatsGrouping
+		wantPeriods []string
+		wantCounts  []int
+	}{
+		{models.StatsGroupDay, []string{"2024-01-01", "2024-01-02", "2024-01-03", "2024-01-04", "2024-01-08"}, []int{1, 1, 1, 1, 1}},
+		{models.StatsGroupWeek, []string{"2024-W01", "2024-W02"}, []int{4, 1}},
+		{models.StatsGroupMonth, []string{"2024-01"}, []int{5}},
+		{models.StatsGroupYear, []string{"2024"}, []int{5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.groupBy), func(t *testing.T) {
+			stats, err := testDB.ReadStepCountStats(ctx, testutils.TestUserID, start, end, tt.groupBy)
+			if err != nil {
+				t.Fatalf("ReadStepCountStats() error = %v", err)
+			}
+			if len(stats) != len(tt.wantPeriods) {
+				t.Fatalf("got %d groups, want %d", len(stats), len(tt.wantPeriods))
+			}
+			for i, s := range stats {
+				if s.Period != tt.wantPeriods[i] || s.Count != tt.wantCounts[i] {
+					t.Errorf("group %d = %s with %d records, want %s with %d", i, s.Period, s.Count, tt.wantPeriods[i], tt.wantCounts[i])
+				}
+			}
+		})
+	}
+
+	t.Run("empty range", func(t *testing.T) {
+		stats, err := testDB.ReadStepCountStats(ctx, testutils.TestUserID, testutils.CreateDate("2023-01-01"), testutils.CreateDate("2023-02-01"), models.StatsGroupNone)
+		if err != nil {
+			t.Fatalf("ReadStepCountStats() error = %v", err)
+		}
+		if len(stats) != 0 {
+			t.Errorf("got %d groups, want 0", len(stats))
+		}
+	})
+}
@@ -24,11 +24,11 @@ func NewSQLiteDBWithMock(t *testing.T) (*database.SQLiteDB, sqlmock.Sqlmock) {
 	}
 
 	queries := map[string]string{
-		"insert_health_record":       `INSERT INTO health_records (date, step_count, created_at, updated_at) VALUES (?, ?, ?, ?)`,
-		"select_health_record":       `SELECT id, date, step_count, created_at, updated_at FROM health_records WHERE date = ?`,
-		"select_range_health_record": `SELECT id, date, step_count, created_at, updated_at FROM health_records WHERE date >= ? AND date < ? ORDER BY date`,
-		"update_health_record":       `UPDATE health_records SET step_count = ?, updated_at = ? WHERE date = ?`,
-		"delete_health_record":       `DELETE FROM health_records WHERE date = ?`,
+		"insert_health_record":       `INSERT INTO health_records (user_id, date, step_count, created_at, updated_at) VALUES (?, ?, ?, ?, ?)`,
+		"select_health_record":       `SELECT id, date, step_count, version, created_at, updated_at FROM health_records WHERE user_id = ? AND date = ?`,
+		"select_range_health_record": `SELECT id, date, step_count, version, created_at, updated_at FROM health_records WHERE user_id = ? AND date >= ? AND date < ? ORDER BY date`,
+		"update_health_record":       `UPDATE health_records SET step_count = ?, updated_at = ?, version = version + 1 WHERE user_id = ? AND date = ? AND (? = 0 OR version = ?)`,
+		"delete_health_record":       `DELETE FROM health_records WHERE user_id = ? AND date = ? AND (? = 0 OR version = ?)`,
 	}
 
 	var sortedKeys []string
@@ -140,7 +140,7 @@ func runCreateHealthRecordSQLiteRollbackTests(t *testing.T, db database.DBInterf
 			buildStubs: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
 				mock.ExpectExec("INSERT INTO health_records").
-					WillReturnError(errors.New("UNIQUE constraint failed: health_records.date"))
+					WillReturnError(errors.New("UNIQUE constraint failed: health_records.user_id, health_records.date"))
 				mock.ExpectRollback()
 			},
 			checkResult: func(t *testing.T, err error) {
@@ -154,7 +154,7 @@ func runCreateHealthRecordSQLiteRollbackTests(t *testing.T, db database.DBInterf
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.buildStubs(mock)
-			_, err := db.CreateHealthRecord(context.Background(), tt.record)
+			_, err := db.CreateHealthRecord(context.Background(), testutils.TestUserID, tt.record)
 			tt.checkResult(t, err)
 			if err := mock.ExpectationsWereMet(); err != nil {
 				t.Errorf("there were unfulfilled expectations: %v", err)
@@ -180,11 +180,11 @@ func runUpdateHealthRecordSQLiteRollbackTests(t *testing.T, db database.DBInterf
 			name: "update rollback on context cancellation",
 			buildStubs: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
-				mock.ExpectQuery("SELECT 1 FROM health_records WHERE date = ?").
-					WithArgs(record.Date).
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT 1 FROM health_records WHERE user_id = ? AND date = ?")).
+					WithArgs(testutils.TestUserID, record.Date).
 					WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
 				mock.ExpectExec("UPDATE health_records").
-					WithArgs(record.StepCount, sqlmock.AnyArg(), record.Date).
+					WithArgs(record.StepCount, sqlmock.AnyArg(), testutils.TestUserID, record.Date, record.Version, record.Version).
 					WillReturnError(context.Canceled)
 				mock.ExpectRollback()
 			},
@@ -198,11 +198,11 @@ func runUpdateHealthRecordSQLiteRollbackTests(t *testing.T, db database.DBInterf
 			name: "update rollback on other database error during exec",
 			buildStubs: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
-				mock.ExpectQuery("SELECT 1 FROM health_records WHERE date = ?").
-					WithArgs(record.Date).
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT 1 FROM health_records WHERE user_id = ? AND date = ?")).
+					WithArgs(testutils.TestUserID, record.Date).
 					WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
 				mock.ExpectExec("UPDATE health_records").
-					WithArgs(record.StepCount, sqlmock.AnyArg(), record.Date).
+					WithArgs(record.StepCount, sqlmock.AnyArg(), testutils.TestUserID, record.Date, record.Version, record.Version).
 					WillReturnError(errors.New("some database error"))
 				mock.ExpectRollback()
 			},
@@ -216,11 +216,11 @@ func runUpdateHealthRecordSQLiteRollbackTests(t *testing.T, db database.DBInterf
 			name: "update rollback on commit failure",
 			buildStubs: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
-				mock.ExpectQuery("SELECT 1 FROM health_records WHERE date = ?").
-					WithArgs(record.Date).
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT 1 FROM health_records WHERE user_id = ? AND date = ?")).
+					WithArgs(testutils.TestUserID, record.Date).
 					WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
 				mock.ExpectExec("UPDATE health_records").
-					WithArgs(record.StepCount, sqlmock.AnyArg(), record.Date).
+					WithArgs(record.StepCount, sqlmock.AnyArg(), testutils.TestUserID, record.Date, record.Version, record.Version).
 					WillReturnResult(sqlmock.NewResult(0, 1))
 				mock.ExpectCommit().WillReturnError(errors.New("commit failed"))
 			},
@@ -235,7 +235,7 @@ func runUpdateHealthRecordSQLiteRollbackTests(t *testing.T, db database.DBInterf
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.buildStubs(mock)
-			err := db.UpdateHealthRecord(context.Background(), record)
+			err := db.UpdateHealthRecord(context.Background(), testutils.TestUserID, record)
 			tt.checkResult(t, err)
 			if err := mock.ExpectationsWereMet(); err != nil {
 				t.Errorf("there were unfulfilled expectations: %v", err)
@@ -258,11 +258,11 @@ func runDeleteHealthRecordSQLiteRollbackTests(t *testing.T, db database.DBInterf
 			name: "delete rollback on context cancellation",
 			buildStubs: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
-				mock.ExpectQuery("SELECT 1 FROM health_records WHERE date = ?").
-					WithArgs(date).
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT 1 FROM health_records WHERE user_id = ? AND date = ?")).
+					WithArgs(testutils.TestUserID, date).
 					WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
 				mock.ExpectExec("DELETE FROM health_records").
-					WithArgs(date).
+					WithArgs(testutils.TestUserID, date, 0, 0).
 					WillReturnError(context.Canceled)
 				mock.ExpectRollback()
 			},
@@ -276,11 +276,11 @@ func runDeleteHealthRecordSQLiteRollbackTests(t *testing.T, db database.DBInterf
 			name: "delete rollback on other database error during exec",
 			buildStubs: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
-				mock.ExpectQuery("SELECT 1 FROM health_records WHERE date = ?").
-					WithArgs(date).
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT 1 FROM health_records WHERE user_id = ? AND date = ?")).
+					WithArgs(testutils.TestUserID, date).
 					WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
 				mock.ExpectExec("DELETE FROM health_records").
-					WithArgs(date).
+					WithArgs(testutils.TestUserID, date, 0, 0).
 					WillReturnError(errors.New("some database error"))
 				mock.ExpectRollback()
 			},
@@ -294,11 +294,11 @@ func runDeleteHealthRecordSQLiteRollbackTests(t *testing.T, db database.DBInterf
 			name: "delete rollback on commit failure",
 			buildStubs: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
-				mock.ExpectQuery("SELECT 1 FROM health_records WHERE date = ?").
-					WithArgs(date).
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT 1 FROM health_records WHERE user_id = ? AND date = ?")).
+					WithArgs(testutils.TestUserID, date).
 					WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
 				mock.ExpectExec("DELETE FROM health_records").
-					WithArgs(date).
+					WithArgs(testutils.TestUserID, date, 0, 0).
 					WillReturnResult(sqlmock.NewResult(0, 1))
 				mock.ExpectCommit().WillReturnError(errors.New("commit failed"))
 			},
@@ -313,7 +313,7 @@ func runDeleteHealthRecordSQLiteRollbackTests(t *testing.T, db database.DBInterf
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.buildStubs(mock)
-			err := db.DeleteHealthRecord(context.Background(), date)
+			err := db.DeleteHealthRecord(context.Background(), testutils.TestUserID, date, 0)
 			tt.checkResult(t, err)
 			if err := mock.ExpectationsWereMet(); err != nil {
 				t.Errorf("there were unfulfilled expectations: %v", err)
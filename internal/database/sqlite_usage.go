@@ -0,0 +1,25 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/models"
+)
+
+// ReadUsage returns the number of users and of health records written since the given time.
+// Timestamps are stored in local time, so since is compared in local time as well.
+func (db *SQLiteDB) ReadUsage(ctx context.Context, since time.Time) (*models.Usage, error) {
+	usageStmt, err := db.getStmt("select_usage")
+	if err != nil {
+		return nil, fmt.Errorf("getting usage statement: %w", err)
+	}
+
+	var usage models.Usage
+	if err := usageStmt.QueryRowContext(ctx, since.Local()).Scan(&usage.Users, &usage.RecordsWritten); err != nil {
+		return nil, fmt.Errorf("scan usage: %w", err)
+	}
+
+	return &usage, nil
+}
@@ -0,0 +1,44 @@
+package database_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/models"
+	"github.com/nnamm/go-health-tracker/testutils"
+)
+
+func TestSQLite_ReadUsage(t *testing.T) {
+	testDB, cleanup := testutils.SetupSQLiteTester(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	before := time.Now().Add(-time.Minute)
+
+	for _, date := range []string{"2024-01-01", "2024-01-02"} {
+		if _, err := testDB.CreateHealthRecord(ctx, testutils.TestUserID, &models.HealthRecord{Date: testutils.CreateDate(date), StepCount: 1000}); err != nil {
+			t.Fatalf("CreateHealthRecord() error = %v", err)
+		}
+	}
+	if _, err := testDB.CreateUser(ctx, &models.User{Email: "usage@example.com"}); err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+
+	usage, err := testDB.ReadUsage(ctx, before)
+	if err != nil {
+		t.Fatalf("ReadUsage() error = %v", err)
+	}
+	// The test user and the one created above
+	if usage.Users != 2 || usage.RecordsWritten != 2 {
+		t.Errorf("ReadUsage() = %+v, want 2 users and 2 records written", usage)
+	}
+
+	usage, err = testDB.ReadUsage(ctx, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("ReadUsage() error = %v", err)
+	}
+	if usage.RecordsWritten != 0 {
+		t.Errorf("ReadUsage() since a later time = %+v, want no records written", usage)
+	}
+}
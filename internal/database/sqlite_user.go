@@ -0,0 +1,79 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/models"
+)
+
+// CreateUser inserts a new user account
+func (db *SQLiteDB) CreateUser(ctx context.Context, u *models.User) (*models.User, error) {
+	insertStmt, err := db.getStmt("insert_user")
+	if err != nil {
+		return nil, fmt.Errorf("getting insert statement: %w", err)
+	}
+
+	var created *models.User
+	err = db.withTxContext(ctx, func(tx *sql.Tx) error {
+		stmt := tx.StmtContext(ctx, insertStmt)
+
+		now := time.Now()
+		result, err := stmt.ExecContext(ctx, u.Email, u.PasswordHash, now, now)
+		if isSQLiteUniqueViolation(err) {
+			return ErrConflict
+		}
+		if err != nil {
+			return fmt.Errorf("insert user: %w", err)
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("get last insert id: %w", err)
+		}
+
+		user := *u
+		user.ID = id
+		user.CreatedAt = now
+		user.UpdatedAt = now
+		created = &user
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// ReadUser retrieves a user by ID
+func (db *SQLiteDB) ReadUser(ctx context.Context, id int64) (*models.User, error) {
+	return db.queryUser(ctx, "select_user", id)
+}
+
+// ReadUserByEmail retrieves a user by email address
+func (db *SQLiteDB) ReadUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	return db.queryUser(ctx, "select_user_by_email", email)
+}
+
+// queryUser runs the named single-user select statement with the given argument
+func (db *SQLiteDB) queryUser(ctx context.Context, stmtName string, arg any) (*models.User, error) {
+	selectStmt, err := db.getStmt(stmtName)
+	if err != nil {
+		return nil, fmt.Errorf("getting select statement: %w", err)
+	}
+
+	u := &models.User{}
+	err = selectStmt.QueryRowContext(ctx, arg).Scan(&u.ID, &u.Email, &u.PasswordHash, &u.CreatedAt, &u.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // No error, but no user found
+		}
+		return nil, fmt.Errorf("scan user: %w", err)
+	}
+
+	return u, nil
+}
@@ -0,0 +1,114 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/database"
+	"github.com/nnamm/go-health-tracker/internal/models"
+	"github.com/nnamm/go-health-tracker/testutils"
+)
+
+func TestSQLite_Users(t *testing.T) {
+	testDB, cleanup := testutils.SetupSQLiteTester(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	created, err := testDB.CreateUser(ctx, &models.User{Email: "alice@example.com", PasswordHash: "hash"})
+	if err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+	if _, err := testDB.CreateUser(ctx, &models.User{Email: "alice@example.com"}); !errors.Is(err, database.ErrConflict) {
+		t.Errorf("CreateUser() with a duplicate email error = %v, want %v", err, database.ErrConflict)
+	}
+
+	byID, err := testDB.ReadUser(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("ReadUser() error = %v", err)
+	}
+	if byID == nil || byID.Email != "alice@example.com" || byID.PasswordHash != "hash" {
+		t.Errorf("ReadUser() = %+v, want the created user", byID)
+	}
+
+	byEmail, err := testDB.ReadUserByEmail(ctx, "alice@example.com")
+	if err != nil {
+		t.Fatalf("ReadUserByEmail() error = %v", err)
+	}
+	if byEmail == nil || byEmail.ID != created.ID {
+		t.Errorf("ReadUserByEmail() = %+v, want user %d", byEmail, created.ID)
+	}
+
+	missing, err := testDB.ReadUserByEmail(ctx, "bob@example.com")
+	if err != nil || missing != nil {
+		t.Errorf("ReadUserByEmail() for unknown email = %+v, %v, want nil, nil", missing, err)
+	}
+}
+
+func TestSQLite_UserIsolation(t *testing.T) {
+	testDB, cleanup := testutils.SetupSQLiteTester(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	other, err := testDB.CreateUser(ctx, &models.User{Email: "other@example.com"})
+	if err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+
+	date := testutils.CreateDate("2024-06-01")
+
+	// Both users can record the same date
+	if _, err := testDB.CreateHealthRecord(ctx, testutils.TestUserID, &models.HealthRecord{Date: date, StepCount: 8000}); err != nil {
+		t.Fatalf("CreateHealthRecord() error = %v", err)
+	}
+	if _, err := testDB.CreateHealthRecord(ctx, other.ID, &models.HealthRecord{Date: date, StepCount: 3000}); err != nil {
+		t.Fatalf("CreateHealthRecord() for other user error = %v", err)
+	}
+
+	// But each user only once
+	if _, err := testDB.CreateHealthRecord(ctx, other.ID, &models.HealthRecord{Date: date, StepCount: 4000}); !errors.Is(err, database.ErrConflict) {
+		t.Errorf("CreateHealthRecord() with a duplicate date for the same user error = %v, want %v", err, database.ErrConflict)
+	}
+
+	got, err := testDB.ReadHealthRecord(ctx, testutils.TestUserID, date)
+	if err != nil {
+		t.Fatalf("ReadHealthRecord() error = %v", err)
+	}
+	if got == nil || got.StepCount != 8000 {
+		t.Errorf("ReadHealthRecord() = %+v, want the test user's 8000 steps", got)
+	}
+
+	// Changes by one user do not touch the other user's data
+	if err := testDB.DeleteHealthRecord(ctx, other.ID, date, 0); err != nil {
+		t.Fatalf("DeleteHealthRecord() error = %v", err)
+	}
+	if got, _ := testDB.ReadHealthRecord(ctx, testutils.TestUserID, date); got == nil {
+		t.Error("DeleteHealthRecord() removed another user's record")
+	}
+
+	bed := time.Date(2024, 6, 1, 22, 0, 0, 0, time.UTC)
+	session := &models.SleepSession{BedTime: bed, WakeTime: bed.Add(8 * time.Hour)}
+	session.SetDerivedFields()
+	created, err := testDB.CreateSleepSession(ctx, testutils.TestUserID, session)
+	if err != nil {
+		t.Fatalf("CreateSleepSession() error = %v", err)
+	}
+	if got, _ := testDB.ReadSleepSession(ctx, other.ID, created.ID); got != nil {
+		t.Error("ReadSleepSession() returned another user's session")
+	}
+	if err := testDB.DeleteSleepSession(ctx, other.ID, created.ID); !errors.Is(err, database.ErrNotFound) {
+		t.Errorf("DeleteSleepSession() of another user's session error = %v, want %v", err, database.ErrNotFound)
+	}
+
+	if _, err := testDB.CreateMetricDefinition(ctx, testutils.TestUserID, &models.MetricDefinition{Name: "mood", ValueType: models.MetricValueTypeInt, Aggregation: models.MetricAggregationAvg}); err != nil {
+		t.Fatalf("CreateMetricDefinition() error = %v", err)
+	}
+	if _, err := testDB.CreateMetricDefinition(ctx, other.ID, &models.MetricDefinition{Name: "mood", ValueType: models.MetricValueTypeInt, Aggregation: models.MetricAggregationAvg}); err != nil {
+		t.Errorf("CreateMetricDefinition() with a name used by another user error = %v", err)
+	}
+	if defs, _ := testDB.ReadMetricDefinitions(ctx, other.ID); len(defs) != 1 {
+		t.Errorf("ReadMetricDefinitions() returned %d definitions, want 1", len(defs))
+	}
+}
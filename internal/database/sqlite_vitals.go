@@ -0,0 +1,146 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/models"
+)
+
+// CreateBloodPressureReading inserts a new blood pressure reading
+func (db *SQLiteDB) CreateBloodPressureReading(ctx context.Context, userID int64, bp *models.BloodPressureReading) (*models.BloodPressureReading, error) {
+	insertStmt, err := db.getStmt("insert_blood_pressure_reading")
+	if err != nil {
+		return nil, fmt.Errorf("getting insert statement: %w", err)
+	}
+
+	var created *models.BloodPressureReading
+	err = db.withTxContext(ctx, func(tx *sql.Tx) error {
+		stmt := tx.StmtContext(ctx, insertStmt)
+
+		now := time.Now()
+		result, err := stmt.ExecContext(ctx, userID, bp.Date, bp.MeasuredAt.UTC(), bp.Systolic, bp.Diastolic, bp.Pulse, bp.Context, bp.Arm, now, now)
+		if err != nil {
+			return fmt.Errorf("insert blood pressure reading: %w", err)
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("get last insert id: %w", err)
+		}
+
+		reading := *bp
+		reading.ID = id
+		reading.CreatedAt = now
+		reading.UpdatedAt = now
+		created = &reading
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// ReadBloodPressureReadingsByRange retrieves blood pressure readings between startDate (inclusive) and endDate (exclusive),
+// ordered by measurement time
+func (db *SQLiteDB) ReadBloodPressureReadingsByRange(ctx context.Context, userID int64, startDate, endDate time.Time) ([]models.BloodPressureReading, error) {
+	selectStmt, err := db.getStmt("select_range_blood_pressure_reading")
+	if err != nil {
+		return nil, fmt.Errorf("getting select_range statement: %w", err)
+	}
+
+	rows, err := selectStmt.QueryContext(ctx, userID, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("query blood pressure readings: %w", err)
+	}
+	defer rows.Close()
+
+	var readings []models.BloodPressureReading
+	for rows.Next() {
+		var bp models.BloodPressureReading
+		if err := rows.Scan(
+			&bp.ID, &bp.Date, &bp.MeasuredAt, &bp.Systolic, &bp.Diastolic, &bp.Pulse, &bp.Context, &bp.Arm, &bp.CreatedAt, &bp.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan blood pressure reading: %w", err)
+		}
+		readings = append(readings, bp)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating through rows: %w", err)
+	}
+
+	return readings, nil
+}
+
+// CreateHeartRateReading inserts a new heart rate reading
+func (db *SQLiteDB) CreateHeartRateReading(ctx context.Context, userID int64, hr *models.HeartRateReading) (*models.HeartRateReading, error) {
+	insertStmt, err := db.getStmt("insert_heart_rate_reading")
+	if err != nil {
+		return nil, fmt.Errorf("getting insert statement: %w", err)
+	}
+
+	var created *models.HeartRateReading
+	err = db.withTxContext(ctx, func(tx *sql.Tx) error {
+		stmt := tx.StmtContext(ctx, insertStmt)
+
+		now := time.Now()
+		result, err := stmt.ExecContext(ctx, userID, hr.Date, hr.MeasuredAt.UTC(), hr.BPM, hr.Context, now, now)
+		if err != nil {
+			return fmt.Errorf("insert heart rate reading: %w", err)
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("get last insert id: %w", err)
+		}
+
+		reading := *hr
+		reading.ID = id
+		reading.CreatedAt = now
+		reading.UpdatedAt = now
+		created = &reading
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// ReadHeartRateReadingsByRange retrieves heart rate readings between startDate (inclusive) and endDate (exclusive),
+// ordered by measurement time
+func (db *SQLiteDB) ReadHeartRateReadingsByRange(ctx context.Context, userID int64, startDate, endDate time.Time) ([]models.HeartRateReading, error) {
+	selectStmt, err := db.getStmt("select_range_heart_rate_reading")
+	if err != nil {
+		return nil, fmt.Errorf("getting select_range statement: %w", err)
+	}
+
+	rows, err := selectStmt.QueryContext(ctx, userID, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("query heart rate readings: %w", err)
+	}
+	defer rows.Close()
+
+	var readings []models.HeartRateReading
+	for rows.Next() {
+		var hr models.HeartRateReading
+		if err := rows.Scan(&hr.ID, &hr.Date, &hr.MeasuredAt, &hr.BPM, &hr.Context, &hr.CreatedAt, &hr.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan heart rate reading: %w", err)
+		}
+		readings = append(readings, hr)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating through rows: %w", err)
+	}
+
+	return readings, nil
+}
@@ -0,0 +1,70 @@
+package database_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/models"
+	"github.com/nnamm/go-health-tracker/testutils"
+)
+
+func TestSQLite_BloodPressureReadings(t *testing.T) {
+	testDB, cleanup := testutils.SetupSQLiteTester(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	morning := time.Date(2024, 5, 1, 7, 0, 0, 0, time.UTC)
+	pulse := 64
+
+	for _, bp := range []*models.BloodPressureReading{
+		{MeasuredAt: morning.Add(12 * time.Hour), Systolic: 128, Diastolic: 84, Context: models.VitalContextPostExercise},
+		{MeasuredAt: morning, Systolic: 118, Diastolic: 78, Pulse: &pulse, Context: models.VitalContextResting, Arm: models.MeasurementArmLeft},
+		{MeasuredAt: morning.AddDate(0, 0, 1), Systolic: 121, Diastolic: 79},
+	} {
+		bp.SetDerivedFields()
+		if _, err := testDB.CreateBloodPressureReading(ctx, testutils.TestUserID, bp); err != nil {
+			t.Fatalf("CreateBloodPressureReading() error = %v", err)
+		}
+	}
+
+	readings, err := testDB.ReadBloodPressureReadingsByRange(ctx, testutils.TestUserID, morning.Truncate(24*time.Hour), morning.Truncate(24*time.Hour).AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("ReadBloodPressureReadingsByRange() error = %v", err)
+	}
+	if len(readings) != 2 {
+		t.Fatalf("got %d readings, want 2", len(readings))
+	}
+
+	first := readings[0]
+	if first.Systolic != 118 || first.Pulse == nil || *first.Pulse != pulse || first.Context != models.VitalContextResting || first.Arm != models.MeasurementArmLeft {
+		t.Errorf("first reading = %+v, want the morning reading with pulse, context and arm", first)
+	}
+	if readings[1].Pulse != nil || readings[1].Arm != "" {
+		t.Errorf("second reading = %+v, want no pulse and no arm", readings[1])
+	}
+}
+
+func TestSQLite_HeartRateReadings(t *testing.T) {
+	testDB, cleanup := testutils.SetupSQLiteTester(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	morning := time.Date(2024, 5, 1, 7, 0, 0, 0, time.UTC)
+
+	for i, bpm := range []int{57, 142} {
+		hr := &models.HeartRateReading{MeasuredAt: morning.Add(time.Duration(i) * time.Hour), BPM: bpm}
+		hr.SetDerivedFields()
+		if _, err := testDB.CreateHeartRateReading(ctx, testutils.TestUserID, hr); err != nil {
+			t.Fatalf("CreateHeartRateReading() error = %v", err)
+		}
+	}
+
+	readings, err := testDB.ReadHeartRateReadingsByRange(ctx, testutils.TestUserID, morning.Truncate(24*time.Hour), morning.Truncate(24*time.Hour).AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("ReadHeartRateReadingsByRange() error = %v", err)
+	}
+	if len(readings) != 2 || readings[0].BPM != 57 || readings[1].BPM != 142 {
+		t.Errorf("readings = %+v, want 57 then 142 bpm", readings)
+	}
+}
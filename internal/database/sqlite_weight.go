@@ -0,0 +1,151 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/models"
+)
+
+// CreateWeightReading inserts a new weight reading
+func (db *SQLiteDB) CreateWeightReading(ctx context.Context, userID int64, wr *models.WeightReading) (*models.WeightReading, error) {
+	insertStmt, err := db.getStmt("insert_weight_reading")
+	if err != nil {
+		return nil, fmt.Errorf("getting insert statement: %w", err)
+	}
+
+	var created *models.WeightReading
+	err = db.withTxContext(ctx, func(tx *sql.Tx) error {
+		stmt := tx.StmtContext(ctx, insertStmt)
+
+		now := time.Now()
+		result, err := stmt.ExecContext(ctx, userID, wr.Date, wr.MeasuredAt.UTC(), wr.WeightKg, wr.BodyFatPercent, wr.MuscleMassKg, now, now)
+		if err != nil {
+			return fmt.Errorf("insert weight reading: %w", err)
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("get last insert id: %w", err)
+		}
+
+		created = &models.WeightReading{
+			ID:             id,
+			Date:           wr.Date,
+			MeasuredAt:     wr.MeasuredAt,
+			WeightKg:       wr.WeightKg,
+			BodyFatPercent: wr.BodyFatPercent,
+			MuscleMassKg:   wr.MuscleMassKg,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// ReadWeightReadingsByRange retrieves weight readings between startDate (inclusive) and endDate (exclusive),
+// ordered by measurement time
+func (db *SQLiteDB) ReadWeightReadingsByRange(ctx context.Context, userID int64, startDate, endDate time.Time) ([]models.WeightReading, error) {
+	selectStmt, err := db.getStmt("select_range_weight_reading")
+	if err != nil {
+		return nil, fmt.Errorf("getting select_range statement: %w", err)
+	}
+
+	rows, err := selectStmt.QueryContext(ctx, userID, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("query weight readings: %w", err)
+	}
+	defer rows.Close()
+
+	var readings []models.WeightReading
+	for rows.Next() {
+		var wr models.WeightReading
+		if err := rows.Scan(
+			&wr.ID, &wr.Date, &wr.MeasuredAt, &wr.WeightKg, &wr.BodyFatPercent, &wr.MuscleMassKg, &wr.CreatedAt, &wr.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan weight reading: %w", err)
+		}
+		readings = append(readings, wr)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating through rows: %w", err)
+	}
+
+	return readings, nil
+}
+
+// DeleteWeightReading deletes a weight reading by ID
+func (db *SQLiteDB) DeleteWeightReading(ctx context.Context, userID int64, id int64) error {
+	deleteStmt, err := db.getStmt("delete_weight_reading")
+	if err != nil {
+		return fmt.Errorf("getting delete statement: %w", err)
+	}
+
+	return db.withTxContext(ctx, func(tx *sql.Tx) error {
+		stmt := tx.StmtContext(ctx, deleteStmt)
+		result, err := stmt.ExecContext(ctx, userID, id)
+		if err != nil {
+			return fmt.Errorf("execute delete: %w", err)
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("get rows affected: %w", err)
+		}
+		if affected == 0 {
+			return ErrNotFound
+		}
+
+		return nil
+	})
+}
+
+// ReadHeightProfile retrieves the stored height profile
+func (db *SQLiteDB) ReadHeightProfile(ctx context.Context, userID int64) (*models.HeightProfile, error) {
+	selectStmt, err := db.getStmt("select_height_profile")
+	if err != nil {
+		return nil, fmt.Errorf("getting select statement: %w", err)
+	}
+
+	hp := &models.HeightProfile{}
+	err = selectStmt.QueryRowContext(ctx, userID).Scan(&hp.HeightCm, &hp.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // No error, but no profile stored yet
+		}
+		return nil, fmt.Errorf("scan height profile: %w", err)
+	}
+
+	return hp, nil
+}
+
+// UpsertHeightProfile creates or replaces the height profile
+func (db *SQLiteDB) UpsertHeightProfile(ctx context.Context, userID int64, hp *models.HeightProfile) (*models.HeightProfile, error) {
+	upsertStmt, err := db.getStmt("upsert_height_profile")
+	if err != nil {
+		return nil, fmt.Errorf("getting upsert statement: %w", err)
+	}
+
+	now := time.Now()
+	err = db.withTxContext(ctx, func(tx *sql.Tx) error {
+		stmt := tx.StmtContext(ctx, upsertStmt)
+		if _, err := stmt.ExecContext(ctx, userID, hp.HeightCm, now); err != nil {
+			return fmt.Errorf("upsert height profile: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.HeightProfile{HeightCm: hp.HeightCm, UpdatedAt: now}, nil
+}
@@ -0,0 +1,82 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/database"
+	"github.com/nnamm/go-health-tracker/internal/models"
+	"github.com/nnamm/go-health-tracker/testutils"
+)
+
+func TestSQLite_WeightReadings(t *testing.T) {
+	testDB, cleanup := testutils.SetupSQLiteTester(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	morning := time.Date(2024, 5, 1, 7, 0, 0, 0, time.UTC)
+	fat := 18.5
+
+	var ids []int64
+	for _, wr := range []*models.WeightReading{
+		{MeasuredAt: morning.Add(12 * time.Hour), WeightKg: 71.2},
+		{MeasuredAt: morning, WeightKg: 70.4, BodyFatPercent: &fat},
+		{MeasuredAt: morning.AddDate(0, 0, 1), WeightKg: 70.1},
+	} {
+		wr.SetDerivedFields()
+		created, err := testDB.CreateWeightReading(ctx, testutils.TestUserID, wr)
+		if err != nil {
+			t.Fatalf("CreateWeightReading() error = %v", err)
+		}
+		ids = append(ids, created.ID)
+	}
+
+	readings, err := testDB.ReadWeightReadingsByRange(ctx, testutils.TestUserID, morning.Truncate(24*time.Hour), morning.Truncate(24*time.Hour).AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("ReadWeightReadingsByRange() error = %v", err)
+	}
+	if len(readings) != 2 {
+		t.Fatalf("got %d readings, want 2", len(readings))
+	}
+	if readings[0].WeightKg != 70.4 || readings[1].WeightKg != 71.2 {
+		t.Errorf("readings should be ordered by measured_at, got %v then %v", readings[0].WeightKg, readings[1].WeightKg)
+	}
+	if readings[0].BodyFatPercent == nil || *readings[0].BodyFatPercent != fat || readings[1].BodyFatPercent != nil {
+		t.Errorf("body fat not stored as given: %v, %v", readings[0].BodyFatPercent, readings[1].BodyFatPercent)
+	}
+
+	if err := testDB.DeleteWeightReading(ctx, testutils.TestUserID, ids[0]); err != nil {
+		t.Fatalf("DeleteWeightReading() error = %v", err)
+	}
+	if err := testDB.DeleteWeightReading(ctx, testutils.TestUserID, ids[0]); !errors.Is(err, database.ErrNotFound) {
+		t.Errorf("DeleteWeightReading() on missing row error = %v, want %v", err, database.ErrNotFound)
+	}
+}
+
+func TestSQLite_HeightProfile(t *testing.T) {
+	testDB, cleanup := testutils.SetupSQLiteTester(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	hp, err := testDB.ReadHeightProfile(ctx, testutils.TestUserID)
+	if err != nil || hp != nil {
+		t.Fatalf("ReadHeightProfile() = %v, %v, want nil, nil before a profile is stored", hp, err)
+	}
+
+	for _, height := range []float64{170, 172.5} {
+		if _, err := testDB.UpsertHeightProfile(ctx, testutils.TestUserID, &models.HeightProfile{HeightCm: height}); err != nil {
+			t.Fatalf("UpsertHeightProfile() error = %v", err)
+		}
+	}
+
+	hp, err = testDB.ReadHeightProfile(ctx, testutils.TestUserID)
+	if err != nil {
+		t.Fatalf("ReadHeightProfile() error = %v", err)
+	}
+	if hp == nil || hp.HeightCm != 172.5 {
+		t.Errorf("height profile = %+v, want 172.5 cm", hp)
+	}
+}
@@ -0,0 +1,135 @@
+package database
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/models"
+)
+
+// statsDialect holds the SQL expressions that differ between the backends
+type statsDialect struct {
+	// periods maps each grouping to the expression deriving the period label from the date column
+	periods map[models.StatsGrouping]string
+	// day formats the date column as YYYY-MM-DD
+	day string
+	// placeholders for user ID, start and end date
+	placeholders [3]string
+}
+
+var sqliteStatsDialect = statsDialect{
+	periods: map[models.StatsGrouping]string{
+		models.StatsGroupDay:   `strftime('%Y-%m-%d', date)`,
+		models.StatsGroupWeek:  `strftime('%G-W%V', date)`,
+		models.StatsGroupMonth: `strftime('%Y-%m', date)`,
+		models.StatsGroupYear:  `strftime('%Y', date)`,
+	},
+	day:          `strftime('%Y-%m-%d', date)`,
+	placeholders: [3]string{"?", "?", "?"},
+}
+
+var postgresStatsDialect = statsDialect{
+	periods: map[models.StatsGrouping]string{
+		models.StatsGroupDay:   `to_char(date, 'YYYY-MM-DD')`,
+		models.StatsGroupWeek:  `to_char(date, 'IYYY-"W"IW')`,
+		models.StatsGroupMonth: `to_char(date, 'YYYY-MM')`,
+		models.StatsGroupYear:  `to_char(date, 'YYYY')`,
+	},
+	day:          `to_char(date, 'YYYY-MM-DD')`,
+	placeholders: [3]string{"$1", "$2", "$3"},
+}
+
+// stepCountStatsQuery builds the query computing the step count statistics of each period.
+// Rows are ranked by step count within their period, so the median and percentiles can be
+// picked by rank with integer arithmetic that both SQLite and Postgres evaluate the same way.
+// The variance is computed as E[X²] - E[X]²; its square root is taken by the caller.
+func (d statsDialect) stepCountStatsQuery(groupBy models.StatsGrouping) (string, error) {
+	period := `''`
+	partition := ``
+	if groupBy != models.StatsGroupNone {
+		expr, ok := d.periods[groupBy]
+		if !ok {
+			return "", fmt.Errorf("unsupported grouping: %s", groupBy)
+		}
+		period = expr
+		partition = `PARTITION BY ` + expr + ` `
+	}
+
+	var percentiles []string
+	for _, p := range models.StatsPercentiles {
+		// position of the percentile between the ranks, scaled by 100: floor part and fraction
+		pos := strconv.Itoa(p) + ` * (cnt - 1)`
+		lower := `MAX(CASE WHEN rn = ` + pos + ` / 100 + 1 THEN step_count END)`
+		upper := `MAX(CASE WHEN rn = ` + pos + ` / 100 + 2 THEN step_count END)`
+		fraction := `MAX(` + pos + ` % 100) / 100.0`
+		percentiles = append(percentiles,
+			`CAST(`+lower+` + (COALESCE(`+upper+`, `+lower+`) - `+lower+`) * `+fraction+` AS DOUBLE PRECISION)`)
+	}
+
+	return `
+		WITH ranked AS (
+			SELECT
+				` + period + ` AS period,
+				step_count,
+				ROW_NUMBER() OVER (` + partition + `ORDER BY step_count, date) AS rn,
+				COUNT(*) OVER (` + partition + `) AS cnt,
+				FIRST_VALUE(` + d.day + `) OVER (` + partition + `ORDER BY step_count, date) AS min_date,
+				FIRST_VALUE(` + d.day + `) OVER (` + partition + `ORDER BY step_count DESC, date) AS max_date
+			FROM health_records
+			WHERE user_id = ` + d.placeholders[0] + ` AND date >= ` + d.placeholders[1] + ` AND date < ` + d.placeholders[2] + `
+		)
+		SELECT
+			period,
+			COUNT(*),
+			SUM(step_count),
+			CAST(AVG(step_count) AS DOUBLE PRECISION),
+			MIN(step_count),
+			MIN(min_date),
+			MAX(step_count),
+			MIN(max_date),
+			AVG(CAST(step_count AS DOUBLE PRECISION) * step_count) - AVG(CAST(step_count AS DOUBLE PRECISION)) * AVG(CAST(step_count AS DOUBLE PRECISION)),
+			` + strings.Join(percentiles, ",\n\t\t\t") + `
+		FROM ranked
+		GROUP BY period
+		ORDER BY period`, nil
+}
+
+// scanStepCountStats scans a row of the statistics query
+func scanStepCountStats(row rowScanner) (*models.StepCountStats, error) {
+	var s models.StepCountStats
+	var minDate, maxDate string
+	var variance float64
+	percentiles := make([]float64, len(models.StatsPercentiles))
+
+	dest := []any{&s.Period, &s.Count, &s.Sum, &s.Mean, &s.Min.StepCount, &minDate, &s.Max.StepCount, &maxDate, &variance}
+	for i := range percentiles {
+		dest = append(dest, &percentiles[i])
+	}
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if s.Min.Date, err = time.Parse("2006-01-02", minDate); err != nil {
+		return nil, fmt.Errorf("parse min date: %w", err)
+	}
+	if s.Max.Date, err = time.Parse("2006-01-02", maxDate); err != nil {
+		return nil, fmt.Errorf("parse max date: %w", err)
+	}
+
+	// Rounding can make the variance of equal values slightly negative
+	s.StdDev = math.Sqrt(math.Max(variance, 0))
+
+	s.Percentiles = make(map[string]float64, len(percentiles))
+	for i, p := range models.StatsPercentiles {
+		s.Percentiles["p"+strconv.Itoa(p)] = percentiles[i]
+		if p == 50 {
+			s.Median = percentiles[i]
+		}
+	}
+
+	return &s, nil
+}
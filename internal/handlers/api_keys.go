@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/apperr"
+	"github.com/nnamm/go-health-tracker/internal/auth"
+	"github.com/nnamm/go-health-tracker/internal/config"
+	"github.com/nnamm/go-health-tracker/internal/database"
+	"github.com/nnamm/go-health-tracker/internal/models"
+	"github.com/nnamm/go-health-tracker/internal/validators"
+)
+
+// APIKeyHandler handles HTTP requests for managing the personal API keys of a user
+type APIKeyHandler struct {
+	DB database.DBInterface
+}
+
+// NewAPIKeyHandler creates a new APIKeyHandler
+func NewAPIKeyHandler(db database.DBInterface) *APIKeyHandler {
+	return &APIKeyHandler{DB: db}
+}
+
+// APIKeyResult represents the response structure for API key listings
+type APIKeyResult struct {
+	Keys []models.APIKey `json:"keys"`
+}
+
+// CreatedAPIKeyResult represents the response to a key creation.
+// It is the only response that contains the key itself.
+type CreatedAPIKeyResult struct {
+	Key string `json:"key"`
+	models.APIKey
+}
+
+// apiKeyNameRequest represents the request body of an API key rename
+type apiKeyNameRequest struct {
+	Name string `json:"name"`
+}
+
+// CreateAPIKey handles the creation of a new API key
+func (h *APIKeyHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	// set a timeout for the request context
+	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(config.RequestTimeoutSecond)*time.Second)
+	defer cancel()
+
+	userID, err := keyManagementUserID(ctx)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	r = r.WithContext(ctx)
+
+	body, err := readRequestBody(ctx, w, r)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	var k models.APIKey
+	if err := json.Unmarshal(body, &k); err != nil {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeInvalidFormat, err.Error()))
+		return
+	}
+
+	if err := validators.ValidateAPIKey(&k); err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	key, prefix, hash, err := auth.GenerateAPIKey()
+	if err != nil {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeInternalServer, err.Error()))
+		return
+	}
+	k.Prefix = prefix
+	k.KeyHash = hash
+
+	created, err := h.DB.CreateAPIKey(ctx, userID, &k)
+	if err != nil {
+		handleError(w, r, databaseError("failed to create api key", err))
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	sendJSONResponse(w, CreatedAPIKeyResult{Key: key, APIKey: *created}, http.StatusCreated)
+}
+
+// GetAPIKeys handles listing the API keys of the user
+func (h *APIKeyHandler) GetAPIKeys(w http.ResponseWriter, r *http.Request) {
+	// set a timeout for the request context
+	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(config.RequestTimeoutSecond)*time.Second)
+	defer cancel()
+
+	userID, err := keyManagementUserID(ctx)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	keys, err := h.DB.ReadAPIKeys(ctx, userID)
+	if err != nil {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeInternalServer, "failed to read api keys: "+err.Error()))
+		return
+	}
+	if keys == nil {
+		keys = []models.APIKey{}
+	}
+
+	sendJSONResponse(w, APIKeyResult{Keys: keys}, http.StatusOK)
+}
+
+// UpdateAPIKey handles renaming an API key
+func (h *APIKeyHandler) UpdateAPIKey(w http.ResponseWriter, r *http.Request) {
+	// set a timeout for the request context
+	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(config.RequestTimeoutSecond)*time.Second)
+	defer cancel()
+
+	userID, err := keyManagementUserID(ctx)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	id, err := apiKeyID(r)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	r = r.WithContext(ctx)
+
+	body, err := readRequestBody(ctx, w, r)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	var req apiKeyNameRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeInvalidFormat, err.Error()))
+		return
+	}
+
+	if err := validators.ValidateAPIKeyName(req.Name); err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	if err := h.DB.UpdateAPIKeyName(ctx, userID, id, req.Name); err != nil {
+		handleError(w, r, databaseError("failed to update api key", err))
+		return
+	}
+
+	// Send success response
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "API key updated successfully"})
+}
+
+// RevokeAPIKey handles revoking an API key
+func (h *APIKeyHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	// set a timeout for the request context
+	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(config.RequestTimeoutSecond)*time.Second)
+	defer cancel()
+
+	userID, err := keyManagementUserID(ctx)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	id, err := apiKeyID(r)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	if err := h.DB.RevokeAPIKey(ctx, userID, id); err != nil {
+		handleError(w, r, databaseError("failed to revoke api key", err))
+		return
+	}
+
+	// Send success response
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "API key revoked successfully"})
+}
+
+// keyManagementUserID returns the requesting user for key management.
+// API keys cannot be used to manage API keys, so a leaked key cannot mint new ones.
+func keyManagementUserID(ctx context.Context) (int64, error) {
+	if _, ok := auth.APIKeyScopeFromContext(ctx); ok {
+		return 0, apperr.NewAppError(apperr.ErrorTypeForbidden, "api keys cannot be used to manage api keys")
+	}
+	return requestUserID(ctx)
+}
+
+// apiKeyID parses the id query parameter
+func apiKeyID(r *http.Request) (int64, error) {
+	idStr := r.URL.Query().Get("id")
+	if idStr == "" {
+		return 0, apperr.NewAppError(apperr.ErrorTypeBadRequest, "id parameter is required")
+	}
+
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		return 0, apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "invalid id: "+idStr)
+	}
+	return id, nil
+}
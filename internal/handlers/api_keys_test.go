@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/auth"
+	"github.com/nnamm/go-health-tracker/internal/models"
+	"github.com/nnamm/go-health-tracker/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createTestAPIKey creates an API key of the test user through the handler and returns the response
+func createTestAPIKey(t *testing.T, h *APIKeyHandler, scope models.APIKeyScope) CreatedAPIKeyResult {
+	t.Helper()
+	rr := serve(h.CreateAPIKey, newTestRequest(http.MethodPost, "/account/api-keys", `{"name": "sync script", "scope": "`+string(scope)+`"}`))
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	assert.Equal(t, "no-store", rr.Header().Get("Cache-Control"))
+
+	var created CreatedAPIKeyResult
+	parseJSONResponse(t, rr, &created)
+	require.NotEmpty(t, created.Key)
+	return created
+}
+
+func TestAuthenticate_APIKeyScope(t *testing.T) {
+	db := newTestDB(t)
+	tokens, err := auth.NewHS256TokenManager([]byte("test-secret"), "go-health-tracker-test", 15*time.Minute, 24*time.Hour)
+	require.NoError(t, err)
+	authHandler := NewAuthHandler(db, tokens)
+	keys := NewAPIKeyHandler(db)
+
+	readOnly := createTestAPIKey(t, keys, models.APIKeyScopeReadOnly)
+	readWrite := createTestAPIKey(t, keys, models.APIKeyScopeReadWrite)
+	revoked := createTestAPIKey(t, keys, models.APIKeyScopeReadWrite)
+	rr := serve(keys.RevokeAPIKey, newTestRequest(http.MethodDelete, "/account/api-keys?id="+strconv.FormatInt(revoked.ID, 10), ""))
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	tests := []struct {
+		name           string
+		key            string
+		method         string
+		expectedStatus int
+		errorMessage   string
+	}{
+		{name: "successful - read-only key reads", key: readOnly.Key, method: http.MethodGet, expectedStatus: http.StatusOK},
+		{name: "successful - read-write key reads", key: readWrite.Key, method: http.MethodGet, expectedStatus: http.StatusOK},
+		{name: "successful - read-write key writes", key: readWrite.Key, method: http.MethodPost, expectedStatus: http.StatusOK},
+		{
+			name:           "error - read-only key writes",
+			key:            readOnly.Key,
+			method:         http.MethodPost,
+			expectedStatus: http.StatusForbidden,
+			errorMessage:   "api key scope read-only does not allow POST requests",
+		},
+		{
+			name:           "error - read-only key deletes",
+			key:            readOnly.Key,
+			method:         http.MethodDelete,
+			expectedStatus: http.StatusForbidden,
+			errorMessage:   "api key scope read-only does not allow DELETE requests",
+		},
+		{name: "error - revoked key", key: revoked.Key, method: http.MethodGet, expectedStatus: http.StatusUnauthorized, errorMessage: "invalid api key"},
+		{name: "error - unknown key", key: "not-a-key", method: http.MethodGet, expectedStatus: http.StatusUnauthorized, errorMessage: "invalid api key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotUserID int64
+			var gotScope models.APIKeyScope
+			next := func(w http.ResponseWriter, r *http.Request) {
+				gotUserID, _ = auth.UserIDFromContext(r.Context())
+				gotScope, _ = auth.APIKeyScopeFromContext(r.Context())
+				w.WriteHeader(http.StatusOK)
+			}
+
+			req := httptest.NewRequest(tt.method, "/health/records", nil)
+			req.Header.Set(apiKeyHeader, tt.key)
+
+			rr := serve(authHandler.Authenticate(next), req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.errorMessage != "" {
+				assert.Equal(t, tt.errorMessage, parseProblem(t, rr).Detail)
+				return
+			}
+			assert.Equal(t, testutils.TestUserID, gotUserID)
+			assert.NotEmpty(t, gotScope)
+		})
+	}
+
+	// Authenticated keys record their use
+	stored, err := db.ReadAPIKeys(context.Background(), testutils.TestUserID)
+	require.NoError(t, err)
+	for _, k := range stored {
+		if k.ID == revoked.ID {
+			assert.Nil(t, k.LastUsedAt, "revoked key")
+			continue
+		}
+		assert.NotNil(t, k.LastUsedAt, "key %d", k.ID)
+	}
+}
+
+func TestAPIKeyHandler_KeysCannotManageKeys(t *testing.T) {
+	h := NewAPIKeyHandler(newTestDB(t))
+
+	byMethod := map[string]http.HandlerFunc{
+		http.MethodGet:    h.GetAPIKeys,
+		http.MethodPost:   h.CreateAPIKey,
+		http.MethodPut:    h.UpdateAPIKey,
+		http.MethodDelete: h.RevokeAPIKey,
+	}
+	for method, handler := range byMethod {
+		t.Run(method, func(t *testing.T) {
+			req := newTestRequest(method, "/account/api-keys?id=1", `{"name": "minted", "scope": "read-write"}`)
+			req = req.WithContext(auth.WithAPIKeyScope(req.Context(), models.APIKeyScopeReadWrite))
+
+			rr := serve(handler, req)
+
+			assert.Equal(t, http.StatusForbidden, rr.Code)
+			assert.Equal(t, "api keys cannot be used to manage api keys", parseProblem(t, rr).Detail)
+		})
+	}
+}
+
+func TestCreateAPIKey_InvalidScope(t *testing.T) {
+	h := NewAPIKeyHandler(newTestDB(t))
+
+	rr := serve(h.CreateAPIKey, newTestRequest(http.MethodPost, "/account/api-keys", `{"name": "sync script", "scope": "admin"}`))
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
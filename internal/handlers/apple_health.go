@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/apperr"
+	"github.com/nnamm/go-health-tracker/internal/config"
+	"github.com/nnamm/go-health-tracker/internal/importer"
+	"github.com/nnamm/go-health-tracker/internal/models"
+)
+
+// maxAppleHealthUploadSize limits the size of uploaded Apple Health exports (4GB)
+const maxAppleHealthUploadSize = 4 << 30
+
+// ImportAppleHealth imports the daily step counts of an Apple Health export.xml request body.
+// The body is stream-parsed, and the days are written in a single transaction.
+// Days that already have a record are skipped, overwritten or fail the whole import depending on the on_conflict parameter.
+func (h *HealthRecordHandler) ImportAppleHealth(w http.ResponseWriter, r *http.Request) {
+	// Parsing a multi-year export takes longer than a regular request,
+	// so the server's read and write deadlines are extended as well
+	timeout := time.Duration(config.ImportTimeoutSecond) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	if err := extendDeadlines(w, timeout); err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || (mediaType != "application/xml" && mediaType != "text/xml") {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeBadRequest, "Content-Type must be application/xml"))
+		return
+	}
+
+	onConflict, err := models.ParseConflictPolicy(r.URL.Query().Get("on_conflict"))
+	if err != nil {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeInvalidFormat, err.Error()))
+		return
+	}
+
+	records, err := importer.ReadAppleHealthSteps(http.MaxBytesReader(w, r.Body, maxAppleHealthUploadSize))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			handleError(w, r, apperr.NewAppError(apperr.ErrorTypeBadRequest, "import file too large"))
+			return
+		}
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeInvalidFormat, err.Error()))
+		return
+	}
+
+	report, err := importer.ImportRecords(ctx, h.DB, userID, records, h.validator, onConflict)
+	if err != nil {
+		handleError(w, r, databaseError("failed to import apple health records", err))
+		return
+	}
+
+	sendJSONResponse(w, report, http.StatusOK)
+}
+
+// extendDeadlines extends the server's read and write deadlines of the connection by timeout,
+// for uploads that take longer than the server timeouts allow
+func extendDeadlines(w http.ResponseWriter, timeout time.Duration) error {
+	rc := http.NewResponseController(w)
+	if err := rc.SetReadDeadline(time.Now().Add(timeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return apperr.NewAppError(apperr.ErrorTypeInternalServer, "failed to extend read deadline: "+err.Error())
+	}
+	if err := rc.SetWriteDeadline(time.Now().Add(timeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return apperr.NewAppError(apperr.ErrorTypeInternalServer, "failed to extend write deadline: "+err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/nnamm/go-health-tracker/internal/importer"
+	"github.com/nnamm/go-health-tracker/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthRecordHandler_ImportAppleHealth(t *testing.T) {
+	db := newTestDB(t)
+	handler := NewHealthRecordHandler(db)
+	createTestRecord(t, db, "2024-03-02", 8000)
+
+	importExport := func(query string) *http.Request {
+		body := `<HealthData>
+ <Record type="HKQuantityTypeIdentifierStepCount" sourceName="iPhone" startDate="2024-03-01 08:00:00 +0900" endDate="2024-03-01 08:10:00 +0900" value="1200"/>
+ <Record type="HKQuantityTypeIdentifierStepCount" sourceName="iPhone" startDate="2024-03-02 08:00:00 +0900" endDate="2024-03-02 08:10:00 +0900" value="500"/>
+</HealthData>`
+		req := newTestRequest(http.MethodPost, "/health/records/import/apple-health"+query, body)
+		req.Header.Set("Content-Type", "application/xml")
+		return req
+	}
+	stepCount := func(date string) int {
+		hr, err := db.ReadHealthRecord(t.Context(), testutils.TestUserID, testutils.CreateDate(date))
+		require.NoError(t, err)
+		if hr == nil {
+			return 0
+		}
+		return hr.StepCount
+	}
+
+	rr := serve(handler.ImportAppleHealth, importExport("?on_conflict=fail"))
+	assert.Equal(t, http.StatusConflict, rr.Code, rr.Body.String())
+	assert.Zero(t, stepCount("2024-03-01"))
+
+	// Stored records are kept unless the import overwrites them
+	rr = serve(handler.ImportAppleHealth, importExport(""))
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var report importer.ImportReport
+	parseJSONResponse(t, rr, &report)
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, []importer.Conflict{{Date: "2024-03-02", Stored: 8000, Imported: 500}}, report.Conflicts)
+	assert.Equal(t, 8000, stepCount("2024-03-02"))
+
+	rr = serve(handler.ImportAppleHealth, importExport("?on_conflict=overwrite"))
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	report = importer.ImportReport{}
+	parseJSONResponse(t, rr, &report)
+	assert.Equal(t, 2, report.Overwritten)
+	assert.Equal(t, 500, stepCount("2024-03-02"))
+}
@@ -0,0 +1,282 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/apperr"
+	"github.com/nnamm/go-health-tracker/internal/auth"
+	"github.com/nnamm/go-health-tracker/internal/config"
+	"github.com/nnamm/go-health-tracker/internal/database"
+	"github.com/nnamm/go-health-tracker/internal/models"
+	"github.com/nnamm/go-health-tracker/internal/validators"
+)
+
+// apiKeyHeader is the request header carrying a personal API key
+const apiKeyHeader = "X-API-Key"
+
+// AuthHandler handles user registration, login and token refresh,
+// and authenticates API requests with bearer tokens or API keys
+type AuthHandler struct {
+	DB     database.DBInterface
+	tokens *auth.TokenManager
+}
+
+// NewAuthHandler creates a new AuthHandler
+func NewAuthHandler(db database.DBInterface, tokens *auth.TokenManager) *AuthHandler {
+	return &AuthHandler{
+		DB:     db,
+		tokens: tokens,
+	}
+}
+
+// credentialsRequest represents the request body of registration and login
+type credentialsRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// refreshRequest represents the request body of a token refresh
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// Register handles the creation of a new user account
+func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
+	// set a timeout for the request context
+	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(config.RequestTimeoutSecond)*time.Second)
+	defer cancel()
+
+	r = r.WithContext(ctx)
+
+	creds, err := readCredentials(ctx, w, r)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	if err := validators.ValidateCredentials(creds.Email, creds.Password); err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	existing, err := h.DB.ReadUserByEmail(ctx, creds.Email)
+	if err != nil {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeInternalServer, "failed to read user: "+err.Error()))
+		return
+	}
+	if existing != nil {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeConflict, "email is already registered"))
+		return
+	}
+
+	hash, err := auth.HashPassword(creds.Password)
+	if err != nil {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeInternalServer, "failed to hash password: "+err.Error()))
+		return
+	}
+
+	created, err := h.DB.CreateUser(ctx, &models.User{Email: creds.Email, PasswordHash: hash})
+	if err != nil {
+		handleError(w, r, databaseError("failed to create user", err))
+		return
+	}
+
+	sendJSONResponse(w, created, http.StatusCreated)
+}
+
+// Login handles password login and issues an access and refresh token
+func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	// set a timeout for the request context
+	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(config.RequestTimeoutSecond)*time.Second)
+	defer cancel()
+
+	r = r.WithContext(ctx)
+
+	creds, err := readCredentials(ctx, w, r)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	user, err := h.DB.ReadUserByEmail(ctx, creds.Email)
+	if err != nil {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeInternalServer, "failed to read user: "+err.Error()))
+		return
+	}
+
+	// Unknown emails and wrong passwords get the same response, and the password is checked
+	// either way so the response time is the same too
+	var passwordHash string
+	if user != nil {
+		passwordHash = user.PasswordHash
+	}
+	if !auth.CheckPassword(passwordHash, creds.Password) || user == nil {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeUnauthorized, "invalid email or password"))
+		return
+	}
+
+	h.sendTokenPair(w, r, user.ID)
+}
+
+// Refresh exchanges a valid refresh token for a new access and refresh token
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	// set a timeout for the request context
+	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(config.RequestTimeoutSecond)*time.Second)
+	defer cancel()
+
+	r = r.WithContext(ctx)
+
+	body, err := readRequestBody(ctx, w, r)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	var req refreshRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeInvalidFormat, err.Error()))
+		return
+	}
+
+	userID, err := h.verify(req.RefreshToken, auth.TokenTypeRefresh)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	// The account may have been removed since the refresh token was issued
+	user, err := h.DB.ReadUser(ctx, userID)
+	if err != nil {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeInternalServer, "failed to read user: "+err.Error()))
+		return
+	}
+	if user == nil {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeUnauthorized, "invalid refresh token"))
+		return
+	}
+
+	h.sendTokenPair(w, r, user.ID)
+}
+
+// Authenticate is middleware that requires a valid bearer access token or API key
+// and attributes the request to the user the credential belongs to.
+// CORS preflight requests pass through unauthenticated.
+func (h *AuthHandler) Authenticate(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodOptions {
+			next(w, r)
+			return
+		}
+
+		if key := r.Header.Get(apiKeyHeader); key != "" {
+			ctx, err := h.authenticateAPIKey(r.Context(), key, r.Method)
+			if err != nil {
+				handleError(w, r, err)
+				return
+			}
+			next(w, r.WithContext(ctx))
+			return
+		}
+
+		token, ok := bearerToken(r)
+		if !ok {
+			w.Header().Set("WWW-Authenticate", `Bearer`)
+			handleError(w, r, apperr.NewAppError(apperr.ErrorTypeUnauthorized, "missing bearer token"))
+			return
+		}
+
+		userID, err := h.verify(token, auth.TokenTypeAccess)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			handleError(w, r, err)
+			return
+		}
+
+		next(w, r.WithContext(auth.WithUserID(r.Context(), userID)))
+	}
+}
+
+// authenticateAPIKey looks up an API key, checks it is active and its scope allows the method,
+// records its use and returns a context carrying the key's user and scope
+func (h *AuthHandler) authenticateAPIKey(ctx context.Context, key, method string) (context.Context, error) {
+	dbCtx, cancel := context.WithTimeout(ctx, time.Duration(config.RequestTimeoutSecond)*time.Second)
+	defer cancel()
+
+	k, err := h.DB.ReadAPIKeyByHash(dbCtx, auth.HashAPIKey(key))
+	if err != nil {
+		return nil, apperr.NewAppError(apperr.ErrorTypeInternalServer, "failed to read api key: "+err.Error())
+	}
+	if k == nil || k.IsRevoked() {
+		return nil, apperr.NewAppError(apperr.ErrorTypeUnauthorized, "invalid api key")
+	}
+	if !k.Scope.Allows(method) {
+		return nil, apperr.NewAppError(apperr.ErrorTypeForbidden, "api key scope "+string(k.Scope)+" does not allow "+method+" requests")
+	}
+
+	if err := h.DB.TouchAPIKey(dbCtx, k.ID, time.Now()); err != nil {
+		return nil, apperr.NewAppError(apperr.ErrorTypeInternalServer, "failed to record api key use: "+err.Error())
+	}
+
+	ctx = auth.WithUserID(ctx, k.UserID)
+	return auth.WithAPIKeyScope(ctx, k.Scope), nil
+}
+
+// verify verifies a token and returns the user ID it was issued for
+func (h *AuthHandler) verify(token string, typ auth.TokenType) (int64, error) {
+	claims, err := h.tokens.Verify(token, typ)
+	if err != nil {
+		if errors.Is(err, auth.ErrExpiredToken) {
+			return 0, apperr.NewAppError(apperr.ErrorTypeUnauthorized, "token has expired")
+		}
+		return 0, apperr.NewAppError(apperr.ErrorTypeUnauthorized, "invalid token")
+	}
+
+	userID, err := claims.UserID()
+	if err != nil {
+		return 0, apperr.NewAppError(apperr.ErrorTypeUnauthorized, "invalid token")
+	}
+	return userID, nil
+}
+
+// sendTokenPair issues and sends a new token pair for the user
+func (h *AuthHandler) sendTokenPair(w http.ResponseWriter, r *http.Request, userID int64) {
+	pair, err := h.tokens.IssueTokenPair(userID)
+	if err != nil {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeInternalServer, "failed to issue tokens: "+err.Error()))
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	sendJSONResponse(w, pair, http.StatusOK)
+}
+
+// readCredentials reads the email and password from the request body
+func readCredentials(ctx context.Context, w http.ResponseWriter, r *http.Request) (*credentialsRequest, error) {
+	body, err := readRequestBody(ctx, w, r)
+	if err != nil {
+		return nil, err
+	}
+
+	var creds credentialsRequest
+	if err := json.Unmarshal(body, &creds); err != nil {
+		return nil, apperr.NewAppError(apperr.ErrorTypeInvalidFormat, err.Error())
+	}
+	creds.Email = strings.TrimSpace(creds.Email)
+
+	return &creds, nil
+}
+
+// bearerToken extracts the token from an "Authorization: Bearer <token>" header
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/apperr"
+	"github.com/nnamm/go-health-tracker/internal/config"
+	"github.com/nnamm/go-health-tracker/internal/models"
+)
+
+// recordETag returns the entity tag of a health record, derived from its version
+func recordETag(hr *models.HealthRecord) string {
+	return `"` + strconv.FormatInt(hr.Version, 10) + `"`
+}
+
+// etagMatches reports whether an If-Match or If-None-Match header value matches etag.
+// If-Match uses the strong comparison, in which weak tags never match;
+// If-None-Match uses the weak comparison, which ignores the W/ prefix.
+func etagMatches(header, etag string, weak bool) bool {
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if strings.HasPrefix(candidate, "W/") {
+			if !weak {
+				continue
+			}
+			candidate = strings.TrimPrefix(candidate, "W/")
+		}
+		if candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// expectedVersion evaluates the If-Match precondition of a write to the record of a date.
+// It returns the version the write must still find, or 0 for an unconditional write.
+func (h *HealthRecordHandler) expectedVersion(ctx context.Context, r *http.Request, userID int64, date time.Time) (int64, error) {
+	ifMatch := r.Header.Get("If-Match")
+	if ifMatch == "" {
+		if config.RequireIfMatch {
+			return 0, apperr.NewAppError(apperr.ErrorTypePreconditionRequired, "If-Match header is required")
+		}
+		return 0, nil
+	}
+
+	current, err := h.DB.ReadHealthRecord(ctx, userID, date)
+	if err != nil {
+		return 0, apperr.NewAppError(apperr.ErrorTypeInternalServer, "failed to read health record: "+err.Error())
+	}
+	if current == nil {
+		return 0, apperr.NewAppError(apperr.ErrorTypePreconditionFailed, "health record does not exist")
+	}
+	if !etagMatches(ifMatch, recordETag(current), false) {
+		return 0, apperr.NewAppError(apperr.ErrorTypePreconditionFailed, "health record has been modified")
+	}
+
+	return current.Version, nil
+}
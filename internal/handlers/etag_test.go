@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/nnamm/go-health-tracker/internal/config"
+	"github.com/nnamm/go-health-tracker/internal/database"
+	"github.com/nnamm/go-health-tracker/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEtagMatches(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		weak   bool
+		want   bool
+	}{
+		{name: "exact match", header: `"2"`, want: true},
+		{name: "any", header: "*", want: true},
+		{name: "one of a list", header: `"1", "2"`, want: true},
+		{name: "other version", header: `"1"`, want: false},
+		{name: "weak tag with strong comparison", header: `W/"2"`, want: false},
+		{name: "weak tag with weak comparison", header: `W/"2"`, weak: true, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, etagMatches(tt.header, `"2"`, tt.weak))
+		})
+	}
+}
+
+func TestGetHealthRecord_IfNoneMatch(t *testing.T) {
+	db := newTestDB(t)
+	createTestRecord(t, db, "2024-01-01", 10000)
+	handler := NewHealthRecordHandler(db)
+
+	tests := []struct {
+		name           string
+		ifNoneMatch    string
+		expectedStatus int
+	}{
+		{name: "current version", ifNoneMatch: `"1"`, expectedStatus: http.StatusNotModified},
+		{name: "weak current version", ifNoneMatch: `W/"1"`, expectedStatus: http.StatusNotModified},
+		{name: "other version", ifNoneMatch: `"2"`, expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := newTestRequest(http.MethodGet, "/health/records?date=20240101", "")
+			req.Header.Set("If-None-Match", tt.ifNoneMatch)
+
+			rr := serve(handler.GetHealthRecords, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Equal(t, `"1"`, rr.Header().Get("ETag"))
+			if tt.expectedStatus == http.StatusNotModified {
+				assert.Empty(t, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestUpdateHealthRecord_IfMatch(t *testing.T) {
+	tests := []struct {
+		name           string
+		records        map[string]int
+		ifMatch        string
+		requireIfMatch bool
+		expectedStatus int
+		wantStepCount  int
+	}{
+		{name: "successful - current version", records: map[string]int{"2024-01-01": 10000}, ifMatch: `"1"`, expectedStatus: http.StatusOK, wantStepCount: 12000},
+		{name: "successful - without If-Match", records: map[string]int{"2024-01-01": 10000}, expectedStatus: http.StatusOK, wantStepCount: 12000},
+		{name: "error - stale version", records: map[string]int{"2024-01-01": 10000}, ifMatch: `"2"`, expectedStatus: http.StatusPreconditionFailed, wantStepCount: 10000},
+		{name: "error - weak tag", records: map[string]int{"2024-01-01": 10000}, ifMatch: `W/"1"`, expectedStatus: http.StatusPreconditionFailed, wantStepCount: 10000},
+		{name: "error - no record", ifMatch: `"1"`, expectedStatus: http.StatusPreconditionFailed},
+		{name: "error - If-Match required", records: map[string]int{"2024-01-01": 10000}, requireIfMatch: true, expectedStatus: http.StatusPreconditionRequired, wantStepCount: 10000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRequireIfMatch(t, tt.requireIfMatch)
+			db := newTestDB(t)
+			for date, stepCount := range tt.records {
+				createTestRecord(t, db, date, stepCount)
+			}
+			handler := NewHealthRecordHandler(db)
+
+			req := newTestRequest(http.MethodPut, "/health/records", `{"date": "2024-01-01", "step_count": 12000}`)
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+
+			rr := serve(handler.UpdateHealthRecord, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code, rr.Body.String())
+			if rr.Code == http.StatusOK {
+				assert.Equal(t, `"2"`, rr.Header().Get("ETag"))
+			}
+			assertStepCount(t, db, "2024-01-01", tt.wantStepCount)
+		})
+	}
+}
+
+func TestPutHealthRecord_Preconditions(t *testing.T) {
+	tests := []struct {
+		name           string
+		records        map[string]int
+		ifMatch        string
+		ifNoneMatch    string
+		requireIfMatch bool
+		expectedStatus int
+		wantResult     string
+		wantStepCount  int
+	}{
+		{name: "successful - create only", ifNoneMatch: "*", expectedStatus: http.StatusCreated, wantResult: upsertResultCreated, wantStepCount: 12000},
+		{name: "error - create only on an existing record", records: map[string]int{"2024-01-01": 10000}, ifNoneMatch: "*", expectedStatus: http.StatusPreconditionFailed, wantStepCount: 10000},
+		{name: "successful - replace only", records: map[string]int{"2024-01-01": 10000}, ifMatch: `"1"`, expectedStatus: http.StatusOK, wantResult: upsertResultReplaced, wantStepCount: 12000},
+		{name: "error - replace only with a stale version", records: map[string]int{"2024-01-01": 10000}, ifMatch: `"2"`, expectedStatus: http.StatusPreconditionFailed, wantStepCount: 10000},
+		{name: "error - replace only without a record", ifMatch: `"1"`, expectedStatus: http.StatusPreconditionFailed},
+		{name: "error - If-Match required", records: map[string]int{"2024-01-01": 10000}, requireIfMatch: true, expectedStatus: http.StatusPreconditionRequired, wantStepCount: 10000},
+		{name: "successful - If-Match required still allows create only", requireIfMatch: true, ifNoneMatch: "*", expectedStatus: http.StatusCreated, wantResult: upsertResultCreated, wantStepCount: 12000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRequireIfMatch(t, tt.requireIfMatch)
+			db := newTestDB(t)
+			for date, stepCount := range tt.records {
+				createTestRecord(t, db, date, stepCount)
+			}
+			handler := NewHealthRecordHandler(db)
+
+			req := newTestRequest(http.MethodPut, "/health/records/20240101", `{"step_count": 12000}`)
+			req.SetPathValue("date", "20240101")
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			if tt.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+
+			rr := serve(handler.PutHealthRecord, req)
+
+			require.Equal(t, tt.expectedStatus, rr.Code, rr.Body.String())
+			if tt.wantResult != "" {
+				var result HealthRecordResult
+				parseJSONResponse(t, rr, &result)
+				assert.Equal(t, tt.wantResult, result.Result)
+			}
+			assertStepCount(t, db, "2024-01-01", tt.wantStepCount)
+		})
+	}
+}
+
+func TestDeleteHealthRecord_IfMatch(t *testing.T) {
+	tests := []struct {
+		name           string
+		ifMatch        string
+		requireIfMatch bool
+		expectedStatus int
+		wantStepCount  int
+	}{
+		{name: "successful - current version", ifMatch: `"1"`, expectedStatus: http.StatusOK},
+		{name: "error - stale version", ifMatch: `"2"`, expectedStatus: http.StatusPreconditionFailed, wantStepCount: 10000},
+		{name: "error - If-Match required", requireIfMatch: true, expectedStatus: http.StatusPreconditionRequired, wantStepCount: 10000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRequireIfMatch(t, tt.requireIfMatch)
+			db := newTestDB(t)
+			createTestRecord(t, db, "2024-01-01", 10000)
+			handler := NewHealthRecordHandler(db)
+
+			req := newTestRequest(http.MethodDelete, "/health/records?date=20240101", "")
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+
+			rr := serve(handler.DeleteHealthRecord, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code, rr.Body.String())
+			assertStepCount(t, db, "2024-01-01", tt.wantStepCount)
+		})
+	}
+}
+
+// setRequireIfMatch sets config.RequireIfMatch for the duration of a test
+func setRequireIfMatch(t *testing.T, required bool) {
+	t.Helper()
+	original := config.RequireIfMatch
+	config.RequireIfMatch = required
+	t.Cleanup(func() { config.RequireIfMatch = original })
+}
+
+// assertStepCount checks the stored step count of a date, where 0 means there is no record
+func assertStepCount(t *testing.T, db *database.SQLiteDB, date string, want int) {
+	t.Helper()
+	stored, err := db.ReadHealthRecord(context.Background(), testutils.TestUserID, testutils.CreateDate(date))
+	require.NoError(t, err)
+	if want == 0 {
+		assert.Nil(t, stored)
+		return
+	}
+	require.NotNil(t, stored)
+	assert.Equal(t, want, stored.StepCount)
+}
@@ -0,0 +1,264 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/apperr"
+	"github.com/nnamm/go-health-tracker/internal/config"
+	"github.com/nnamm/go-health-tracker/internal/database"
+	"github.com/nnamm/go-health-tracker/internal/models"
+	"github.com/nnamm/go-health-tracker/internal/validators"
+)
+
+// GoalHandler handles HTTP requests for daily step goals and the progress towards them
+type GoalHandler struct {
+	DB database.DBInterface
+}
+
+// NewGoalHandler creates a new GoalHandler
+func NewGoalHandler(db database.DBInterface) *GoalHandler {
+	return &GoalHandler{DB: db}
+}
+
+// StepGoalResult represents the response structure for step goals
+type StepGoalResult struct {
+	Goals []models.StepGoal `json:"goals"`
+}
+
+// GoalProgressResult represents the per-day and per-month goal completion of a date range
+type GoalProgressResult struct {
+	From   string                         `json:"from"`
+	To     string                         `json:"to"`
+	Days   []models.GoalDay               `json:"days"`
+	Months []models.MonthlyGoalCompletion `json:"months"`
+}
+
+// GoalStreakResult represents the current and longest goal streaks in days
+type GoalStreakResult struct {
+	CurrentStreak int `json:"current_streak"`
+	LongestStreak int `json:"longest_streak"`
+}
+
+// GetStepGoals retrieves all step goals ordered by effective date
+func (h *GoalHandler) GetStepGoals(w http.ResponseWriter, r *http.Request) {
+	// set a timeout for the request context
+	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(config.RequestTimeoutSecond)*time.Second)
+	defer cancel()
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	goals, err := h.readGoals(ctx, userID)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	sendJSONResponse(w, StepGoalResult{Goals: goals}, http.StatusOK)
+}
+
+// PutStepGoal sets the step goal effective from the given date, replacing a goal set for the same date
+func (h *GoalHandler) PutStepGoal(w http.ResponseWriter, r *http.Request) {
+	// set a timeout for the request context
+	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(config.RequestTimeoutSecond)*time.Second)
+	defer cancel()
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	r = r.WithContext(ctx)
+
+	body, err := readRequestBody(ctx, w, r)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	var g models.StepGoal
+	if err := json.Unmarshal(body, &g); err != nil {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeInvalidFormat, err.Error()))
+		return
+	}
+
+	if err := validators.ValidateStepGoal(&g); err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	stored, err := h.DB.UpsertStepGoal(ctx, userID, &g)
+	if err != nil {
+		handleError(w, r, databaseError("failed to set step goal", err))
+		return
+	}
+
+	sendJSONResponse(w, stored, http.StatusOK)
+}
+
+// DeleteStepGoal handles the deletion of the step goal effective from the given date
+func (h *GoalHandler) DeleteStepGoal(w http.ResponseWriter, r *http.Request) {
+	// Set a timeout for the request context
+	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(config.RequestTimeoutSecond)*time.Second)
+	defer cancel()
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	dateStr := r.URL.Query().Get("effective_from")
+	if dateStr == "" {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeBadRequest, "effective_from parameter is required"))
+		return
+	}
+
+	date, err := parseDate("effective_from", dateStr)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	if err := h.DB.DeleteStepGoal(ctx, userID, date); err != nil {
+		handleError(w, r, databaseError("failed to delete step goal", err))
+		return
+	}
+
+	// Send success response
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Step goal deleted successfully"})
+}
+
+// GetGoalProgress reports whether each day of the specified year (and month) or date range (from, to)
+// met the goal in effect on that day, with the completion rate per month.
+// Days after today are not reported, and days without a record count as missed.
+func (h *GoalHandler) GetGoalProgress(w http.ResponseWriter, r *http.Request) {
+	// set a timeout for the request context
+	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(config.RequestTimeoutSecond)*time.Second)
+	defer cancel()
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	query := r.URL.Query()
+
+	var start, end time.Time
+	switch {
+	case query.Get("year") != "":
+		start, end, err = yearMonthRange(query.Get("year"), query.Get("month"))
+	default:
+		start, end, err = parseDateRange(query.Get("from"), query.Get("to"))
+	}
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	result := GoalProgressResult{
+		From:   start.Format("2006-01-02"),
+		To:     end.AddDate(0, 0, -1).Format("2006-01-02"),
+		Days:   []models.GoalDay{},
+		Months: []models.MonthlyGoalCompletion{},
+	}
+
+	if tomorrow := today().AddDate(0, 0, 1); end.After(tomorrow) {
+		end = tomorrow
+	}
+
+	days, err := h.evaluate(ctx, userID, start, end)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+	if days != nil {
+		result.Days = days
+		result.Months = models.MonthlyGoalCompletions(days)
+	}
+
+	sendJSONResponse(w, result, http.StatusOK)
+}
+
+// GetGoalStreaks reports the current and longest runs of consecutive days that met the goal,
+// over the whole history since the first goal took effect
+func (h *GoalHandler) GetGoalStreaks(w http.ResponseWriter, r *http.Request) {
+	// set a timeout for the request context
+	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(config.RequestTimeoutSecond)*time.Second)
+	defer cancel()
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	goals, err := h.readGoals(ctx, userID)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	var result GoalStreakResult
+	if len(goals) > 0 {
+		now := today()
+		days, err := h.evaluateGoals(ctx, userID, goals, goals[0].EffectiveFrom, now.AddDate(0, 0, 1))
+		if err != nil {
+			handleError(w, r, err)
+			return
+		}
+		result.CurrentStreak, result.LongestStreak = models.GoalStreaks(days, now)
+	}
+
+	sendJSONResponse(w, result, http.StatusOK)
+}
+
+// evaluate evaluates the days in [start, end) against the user's goals
+func (h *GoalHandler) evaluate(ctx context.Context, userID int64, start, end time.Time) ([]models.GoalDay, error) {
+	goals, err := h.readGoals(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return h.evaluateGoals(ctx, userID, goals, start, end)
+}
+
+// evaluateGoals evaluates the days in [start, end) against the given goals using the recorded step counts
+func (h *GoalHandler) evaluateGoals(ctx context.Context, userID int64, goals []models.StepGoal, start, end time.Time) ([]models.GoalDay, error) {
+	if len(goals) == 0 || !start.Before(end) {
+		return nil, nil
+	}
+
+	records, err := h.DB.ReadHealthRecordsByRange(ctx, userID, start, end)
+	if err != nil {
+		return nil, apperr.NewAppError(apperr.ErrorTypeInternalServer, "failed to read health records: "+err.Error())
+	}
+
+	return models.EvaluateGoalDays(records, goals, start, end), nil
+}
+
+// readGoals reads the user's step goals, never returning a nil slice
+func (h *GoalHandler) readGoals(ctx context.Context, userID int64) ([]models.StepGoal, error) {
+	goals, err := h.DB.ReadStepGoals(ctx, userID)
+	if err != nil {
+		return nil, apperr.NewAppError(apperr.ErrorTypeInternalServer, "failed to read step goals: "+err.Error())
+	}
+	if goals == nil {
+		goals = []models.StepGoal{}
+	}
+	return goals, nil
+}
+
+// today returns the current date in UTC, the time zone of the stored dates
+func today() time.Time {
+	now := time.Now().UTC()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
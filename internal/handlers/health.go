@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/config"
+	"github.com/nnamm/go-health-tracker/internal/database"
+	"github.com/nnamm/go-health-tracker/internal/logging"
+)
+
+// readinessTimeout bounds the database check of a readiness probe
+const readinessTimeout = 2 * time.Second
+
+// Health statuses
+const (
+	healthStatusOK          = "ok"
+	healthStatusUnavailable = "unavailable"
+)
+
+// HealthHandler serves the liveness, readiness and database debug endpoints
+type HealthHandler struct {
+	DB database.DBInterface
+}
+
+// NewHealthHandler creates a new HealthHandler
+func NewHealthHandler(db database.DBInterface) *HealthHandler {
+	return &HealthHandler{DB: db}
+}
+
+// HealthStatus is the response of the liveness and readiness endpoints
+type HealthStatus struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// Livez reports that the process is up and serving requests. It doesn't touch the database,
+// so a database outage makes the instance unready rather than getting it restarted.
+func (h *HealthHandler) Livez(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	sendJSONResponse(w, HealthStatus{Status: healthStatusOK}, http.StatusOK)
+}
+
+// Readyz reports whether the instance can serve API requests. It responds with 503
+// when the database is unreachable or its connection pool is exhausted.
+// Backends without a HealthChecker are assumed ready.
+func (h *HealthHandler) Readyz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+
+	checker, ok := h.DB.(database.HealthChecker)
+	if !ok {
+		sendJSONResponse(w, HealthStatus{Status: healthStatusOK}, http.StatusOK)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+	defer cancel()
+
+	if err := checker.HealthCheck(ctx); err != nil {
+		logging.FromContext(ctx).Warn("readiness check failed", slog.Any("error", err))
+
+		check := "unreachable"
+		if errors.Is(err, database.ErrPoolExhausted) {
+			check = "pool exhausted"
+		}
+		sendJSONResponse(w, HealthStatus{Status: healthStatusUnavailable, Checks: map[string]string{"database": check}}, http.StatusServiceUnavailable)
+		return
+	}
+
+	sendJSONResponse(w, HealthStatus{Status: healthStatusOK, Checks: map[string]string{"database": healthStatusOK}}, http.StatusOK)
+}
+
+// DebugDB shows the connection pool statistics of the database. It is only served in development mode.
+func (h *HealthHandler) DebugDB(w http.ResponseWriter, r *http.Request) {
+	if !config.IsDev() {
+		http.NotFound(w, r)
+		return
+	}
+
+	checker, ok := h.DB.(database.HealthChecker)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	sendJSONResponse(w, checker.GetPoolInfo(), http.StatusOK)
+}
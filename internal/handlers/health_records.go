@@ -4,9 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"io"
-	"log"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/nnamm/go-health-tracker/internal/apperr"
@@ -30,202 +30,394 @@ func NewHealthRecordHandler(db database.DBInterface) *HealthRecordHandler {
 	}
 }
 
-// HealthRecordResult represents the response structure for health records
+// HealthRecordResult represents the response structure for health records.
+// Listings by year or date range are paginated: NextCursor is set while more records follow,
+// and Total counts all records of the listing. Upserts set Result to created or replaced.
 type HealthRecordResult struct {
-	Records []models.HealthRecord `json:"records"`
+	Records    []models.HealthRecord `json:"records"`
+	NextCursor string                `json:"next_cursor,omitempty"`
+	Total      *int                  `json:"total,omitempty"`
+	Result     string                `json:"result,omitempty"`
 }
 
+// Upsert results
+const (
+	upsertResultCreated  = "created"
+	upsertResultReplaced = "replaced"
+)
+
 // CreateHealthRecord handles the creation of a new health record
 func (h *HealthRecordHandler) CreateHealthRecord(w http.ResponseWriter, r *http.Request) {
 	// set a timeout for the request context
 	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(config.RequestTimeoutSecond)*time.Second)
 	defer cancel()
 
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
 	// Create a new request with original request's context
 	r = r.WithContext(ctx)
 
-	// Limit the request body size to 8KB
-	r.Body = http.MaxBytesReader(w, r.Body, 8*1024)
-
-	// Create channels to handle the request body and errors for async processing
-	bodyCh := make(chan []byte, 1)
-	errCh := make(chan error, 1)
-
-	go func() {
-		body, err := io.ReadAll(r.Body)
-		if err != nil {
-			errCh <- err
-			return
-		}
-		bodyCh <- body
-	}()
-
-	// Check if the request has been cancelled or timed out
-	select {
-	case <-ctx.Done():
-		if ctx.Err() == context.DeadlineExceeded {
-			h.handleError(w, apperr.NewAppError(apperr.ErrorTypeInternalServer, "request processing timed out"))
-		} else {
-			h.handleError(w, apperr.NewAppError(apperr.ErrorTypeInternalServer, "request was cancelled"))
-		}
+	body, err := readRequestBody(ctx, w, r)
+	if err != nil {
+		handleError(w, r, err)
 		return
-	case err := <-errCh:
-		if err.Error() == "http: request body too large" {
-			h.handleError(w, apperr.NewAppError(apperr.ErrorTypeBadRequest, "request body too large"))
-		} else {
-			h.handleError(w, apperr.NewAppError(apperr.ErrorTypeInternalServer, "failed to read request body"))
-		}
+	}
+
+	var hr models.HealthRecord
+	if err := hr.UnmarshalJSON(body); err != nil {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeInvalidFormat, err.Error()))
 		return
-	case body := <-bodyCh:
-		var hr models.HealthRecord
-		if err := hr.UnmarshalJSON(body); err != nil {
-			h.handleError(w, apperr.NewAppError(apperr.ErrorTypeInvalidFormat, err.Error()))
-			return
-		}
+	}
 
-		if err := h.validator.Validate(&hr); err != nil {
-			h.handleError(w, err)
-			return
-		}
+	if err := h.validator.Validate(&hr); err != nil {
+		handleError(w, r, err)
+		return
+	}
 
-		// Send success response
-		createdRecord, err := h.DB.CreateHealthRecord(ctx, &hr)
-		if err != nil {
-			h.handleError(w, apperr.NewAppError(apperr.ErrorTypeInternalServer, "failed to create health record: "+err.Error()))
-			return
-		}
+	// Send success response
+	createdRecord, err := h.DB.CreateHealthRecord(ctx, userID, &hr)
+	if err != nil {
+		handleError(w, r, databaseError("failed to create health record", err))
+		return
+	}
 
-		result := HealthRecordResult{
-			Records: []models.HealthRecord{*createdRecord},
-		}
-		h.sendJSONResponse(w, result, http.StatusCreated)
+	result := HealthRecordResult{
+		Records: []models.HealthRecord{*createdRecord},
 	}
+	w.Header().Set("ETag", recordETag(createdRecord))
+	sendJSONResponse(w, result, http.StatusCreated)
 }
 
-// GetHealthRecords retrieves record(s) for the specified date (year, month. date)
+// GetHealthRecords retrieves record(s) for the specified date (year, month. date) or date range (from, to)
 func (h *HealthRecordHandler) GetHealthRecords(w http.ResponseWriter, r *http.Request) {
 	// set a timeout for the request context
 	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(config.RequestTimeoutSecond)*time.Second)
 	defer cancel()
 
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
 	query := r.URL.Query()
 	var result HealthRecordResult
-	var err error
 
 	switch {
 	case query.Get("date") != "":
 		var record *models.HealthRecord
-		record, err = h.getByDate(ctx, query.Get("date"))
+		record, err = h.getByDate(ctx, userID, query.Get("date"))
 		if record != nil {
+			etag := recordETag(record)
+			w.Header().Set("ETag", etag)
+			if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" && etagMatches(ifNoneMatch, etag, true) {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
 			result.Records = []models.HealthRecord{*record}
 		}
 	case query.Get("year") != "":
-		result.Records, err = h.getByYearMonth(ctx, query.Get("year"), query.Get("month"))
+		var start, end time.Time
+		if start, end, err = yearMonthRange(query.Get("year"), query.Get("month")); err == nil {
+			result, err = h.getPage(ctx, userID, start, end, query)
+		}
+	case query.Get("from") != "" || query.Get("to") != "":
+		var start, end time.Time
+		if start, end, err = parseDateRange(query.Get("from"), query.Get("to")); err == nil {
+			result, err = h.getPage(ctx, userID, start, end, query)
+		}
+	default:
+		sendErrorResponse(w, r, apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "invalid query parameters: expected date, year or from/to"), http.StatusBadRequest)
+		return
+	}
+
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	sendJSONResponse(w, result, http.StatusOK)
+}
+
+// HealthRecordStatsResult represents the response structure for step count statistics.
+// Without grouping, Groups holds a single entry for the whole range.
+type HealthRecordStatsResult struct {
+	From    string                  `json:"from"`
+	To      string                  `json:"to"`
+	GroupBy models.StatsGrouping    `json:"group_by,omitempty"`
+	Groups  []models.StepCountStats `json:"groups"`
+}
+
+// GetHealthRecordStats retrieves step count statistics for the specified year (and month) or date range (from, to),
+// optionally grouped by day, week, month or year
+func (h *HealthRecordHandler) GetHealthRecordStats(w http.ResponseWriter, r *http.Request) {
+	// set a timeout for the request context
+	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(config.RequestTimeoutSecond)*time.Second)
+	defer cancel()
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	query := r.URL.Query()
+
+	var start, end time.Time
+	switch {
+	case query.Get("year") != "":
+		start, end, err = yearMonthRange(query.Get("year"), query.Get("month"))
 	default:
-		h.sendErrorResponse(w, apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "invalid query parameters: expected date or year"), http.StatusBadRequest)
+		start, end, err = parseDateRange(query.Get("from"), query.Get("to"))
+	}
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	groupBy, err := models.ParseStatsGrouping(query.Get("group_by"))
+	if err != nil {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeInvalidFormat, err.Error()))
 		return
 	}
 
+	stats, err := h.DB.ReadStepCountStats(ctx, userID, start, end, groupBy)
 	if err != nil {
-		h.handleError(w, err)
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeInternalServer, "failed to read step count stats: "+err.Error()))
 		return
 	}
 
-	h.sendJSONResponse(w, result, http.StatusOK)
+	result := HealthRecordStatsResult{
+		From:    start.Format("2006-01-02"),
+		To:      end.AddDate(0, 0, -1).Format("2006-01-02"),
+		GroupBy: groupBy,
+		Groups:  stats,
+	}
+	if result.Groups == nil {
+		result.Groups = []models.StepCountStats{}
+	}
+	if groupBy == models.StatsGroupNone {
+		for i := range result.Groups {
+			result.Groups[i].Period = result.From + "/" + result.To
+		}
+	}
+
+	sendJSONResponse(w, result, http.StatusOK)
 }
 
-// UpdateHealthRecord handles the update of an existing health record
+// UpdateHealthRecord handles the update of an existing health record.
+// With an If-Match header, the record is only updated if it still has that ETag.
 func (h *HealthRecordHandler) UpdateHealthRecord(w http.ResponseWriter, r *http.Request) {
 	// Set a timeout for the request context
 	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(config.RequestTimeoutSecond)*time.Second)
 	defer cancel()
 
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
 	// Create a new request with original request's context
 	r = r.WithContext(ctx)
 
-	// Limit the request body size to 8KB
-	r.Body = http.MaxBytesReader(w, r.Body, 8*1024)
+	body, err := readRequestBody(ctx, w, r)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
 
-	// Create channels to handle the request body and errors for async processing
-	bodyCh := make(chan []byte, 1)
-	errCh := make(chan error, 1)
+	var hr models.HealthRecord
+	if err := hr.UnmarshalJSON(body); err != nil {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeInvalidFormat, err.Error()))
+		return
+	}
 
-	go func() {
-		body, err := io.ReadAll(r.Body)
-		if err != nil {
-			errCh <- err
-			return
-		}
-		bodyCh <- body
-	}()
-
-	// Check if the request has been cancelled or timed out
-	select {
-	case <-ctx.Done():
-		if ctx.Err() == context.DeadlineExceeded {
-			h.handleError(w, apperr.NewAppError(apperr.ErrorTypeInternalServer, "request processing timed out"))
-		} else {
-			h.handleError(w, apperr.NewAppError(apperr.ErrorTypeInternalServer, "request was cancelled"))
-		}
-	case err := <-errCh:
-		if err.Error() == "http: request body too large" {
-			h.handleError(w, apperr.NewAppError(apperr.ErrorTypeBadRequest, "request body too large"))
-		} else {
-			h.handleError(w, apperr.NewAppError(apperr.ErrorTypeInternalServer, "failed to read request body"))
-		}
-	case body := <-bodyCh:
-		var hr models.HealthRecord
-		if err := hr.UnmarshalJSON(body); err != nil {
-			h.handleError(w, apperr.NewAppError(apperr.ErrorTypeInvalidFormat, err.Error()))
-			return
-		}
+	if err := h.validator.Validate(&hr); err != nil {
+		handleError(w, r, err)
+		return
+	}
 
-		if err := h.validator.Validate(&hr); err != nil {
-			h.handleError(w, err)
-			return
-		}
+	// The version is only taken from If-Match, never from the body
+	hr.Version, err = h.expectedVersion(ctx, r, userID, hr.Date)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
 
-		if err := h.DB.UpdateHealthRecord(ctx, &hr); err != nil {
-			h.handleError(w, apperr.NewAppError(apperr.ErrorTypeInternalServer, "failed to update health record: "+err.Error()))
-			return
-		}
+	if err := h.DB.UpdateHealthRecord(ctx, userID, &hr); err != nil {
+		handleError(w, r, databaseError("failed to update health record", err))
+		return
+	}
+
+	// Send success response
+	updatedRecord, err := h.DB.ReadHealthRecord(ctx, userID, hr.Date)
+	if err != nil {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeInternalServer, "failed to read updated health record: "+err.Error()))
+		return
+	}
+
+	result := HealthRecordResult{
+		Records: []models.HealthRecord{*updatedRecord},
+	}
+	w.Header().Set("ETag", recordETag(updatedRecord))
+	sendJSONResponse(w, result, http.StatusOK)
+}
+
+// putHealthRecordRequest represents the request body of an upsert.
+// The date is taken from the path, so it is optional in the body.
+type putHealthRecordRequest struct {
+	Date      string `json:"date"`
+	StepCount *int   `json:"step_count"`
+}
+
+// PutHealthRecord creates or replaces the record of the date in the request path (YYYYMMDD).
+// A date in the request body must match the path. It responds with 201 when the record was created
+// and 200 when it was replaced. With If-Match the stored record is only replaced if it still has that ETag,
+// and with "If-None-Match: *" a record is only created if the date has none yet.
+func (h *HealthRecordHandler) PutHealthRecord(w http.ResponseWriter, r *http.Request) {
+	// Set a timeout for the request context
+	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(config.RequestTimeoutSecond)*time.Second)
+	defer cancel()
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	date, err := parseDate("date", r.PathValue("date"))
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	// Create a new request with original request's context
+	r = r.WithContext(ctx)
+
+	body, err := readRequestBody(ctx, w, r)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	var req putHealthRecordRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeInvalidFormat, err.Error()))
+		return
+	}
+	if req.StepCount == nil {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "step_count is required"))
+		return
+	}
+	if req.Date != "" && req.Date != date.Format("2006-01-02") {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeInvalidDate, "date in the body does not match the path"))
+		return
+	}
+
+	hr := models.HealthRecord{Date: date, StepCount: *req.StepCount}
+	if err := h.validator.Validate(&hr); err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	stored, created, err := h.putRecord(ctx, r, userID, &hr)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	result := HealthRecordResult{
+		Records: []models.HealthRecord{*stored},
+		Result:  upsertResultReplaced,
+	}
+	statusCode := http.StatusOK
+	if created {
+		result.Result = upsertResultCreated
+		statusCode = http.StatusCreated
+	}
+	w.Header().Set("ETag", recordETag(stored))
+	sendJSONResponse(w, result, statusCode)
+}
 
-		// Send success response
-		updatedRecord, err := h.DB.ReadHealthRecord(ctx, hr.Date)
+// putRecord stores the record of an upsert under the preconditions of the request
+func (h *HealthRecordHandler) putRecord(ctx context.Context, r *http.Request, userID int64, hr *models.HealthRecord) (*models.HealthRecord, bool, error) {
+	// Create only
+	if strings.TrimSpace(r.Header.Get("If-None-Match")) == "*" {
+		created, err := h.DB.CreateHealthRecord(ctx, userID, hr)
+		if errors.Is(err, database.ErrConflict) {
+			return nil, false, apperr.NewAppError(apperr.ErrorTypePreconditionFailed, "health record already exists")
+		}
 		if err != nil {
-			h.handleError(w, apperr.NewAppError(apperr.ErrorTypeInternalServer, "failed to read updated health record: "+err.Error()))
-			return
+			return nil, false, databaseError("failed to create health record", err)
 		}
+		return created, true, nil
+	}
 
-		result := HealthRecordResult{
-			Records: []models.HealthRecord{*updatedRecord},
+	if r.Header.Get("If-Match") == "" && !config.RequireIfMatch {
+		stored, created, err := h.DB.UpsertHealthRecord(ctx, userID, hr)
+		if err != nil {
+			return nil, false, databaseError("failed to upsert health record", err)
 		}
-		h.sendJSONResponse(w, result, http.StatusOK)
+		return stored, created, nil
 	}
+
+	// Replace only
+	version, err := h.expectedVersion(ctx, r, userID, hr.Date)
+	if err != nil {
+		return nil, false, err
+	}
+	hr.Version = version
+	if err := h.DB.UpdateHealthRecord(ctx, userID, hr); err != nil {
+		return nil, false, databaseError("failed to update health record", err)
+	}
+
+	stored, err := h.DB.ReadHealthRecord(ctx, userID, hr.Date)
+	if err != nil {
+		return nil, false, apperr.NewAppError(apperr.ErrorTypeInternalServer, "failed to read updated health record: "+err.Error())
+	}
+	return stored, false, nil
 }
 
-// DeleteHealthRecord handles the deletion of a health record
+// DeleteHealthRecord handles the deletion of a health record.
+// With an If-Match header, the record is only deleted if it still has that ETag.
 func (h *HealthRecordHandler) DeleteHealthRecord(w http.ResponseWriter, r *http.Request) {
 	// Set a timeout for the request context
 	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(config.RequestTimeoutSecond)*time.Second)
 	defer cancel()
 
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
 	// Get date from query parameters and parse it
 	dateStr := r.URL.Query().Get("date")
 	if dateStr == "" {
-		h.handleError(w, apperr.NewAppError(apperr.ErrorTypeBadRequest, "date parameter is required"))
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeBadRequest, "date parameter is required"))
 		return
 	}
 
 	date, err := time.Parse("20060102", dateStr)
 	if err != nil {
-		h.handleError(w, apperr.NewAppError(apperr.ErrorTypeInvalidDate, "invalid date format: "+dateStr+" (Use YYYYMMDD)"))
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeInvalidDate, "invalid date format: "+dateStr+" (Use YYYYMMDD)"))
+		return
+	}
+
+	version, err := h.expectedVersion(ctx, r, userID, date)
+	if err != nil {
+		handleError(w, r, err)
 		return
 	}
 
 	// Delete the record
-	if err = h.DB.DeleteHealthRecord(ctx, date); err != nil {
-		h.handleError(w, apperr.NewAppError(apperr.ErrorTypeInternalServer, "failed to delete health record: "+err.Error()))
+	if err = h.DB.DeleteHealthRecord(ctx, userID, date, version); err != nil {
+		handleError(w, r, databaseError("failed to delete health record", err))
 		return
 	}
 
@@ -235,13 +427,13 @@ func (h *HealthRecordHandler) DeleteHealthRecord(w http.ResponseWriter, r *http.
 }
 
 // getByDate retrieves a record for the specified date (YYYYMMDD)
-func (h *HealthRecordHandler) getByDate(ctx context.Context, dateStr string) (*models.HealthRecord, error) {
+func (h *HealthRecordHandler) getByDate(ctx context.Context, userID int64, dateStr string) (*models.HealthRecord, error) {
 	date, err := time.Parse("20060102", dateStr)
 	if err != nil {
 		return nil, apperr.NewAppError(apperr.ErrorTypeInvalidDate, "invalid date format: "+dateStr+" (Use YYYYMMDD)")
 	}
 
-	record, err := h.DB.ReadHealthRecord(ctx, date)
+	record, err := h.DB.ReadHealthRecord(ctx, userID, date)
 	if err != nil {
 		return nil, apperr.NewAppError(apperr.ErrorTypeInternalServer, "failed to read health record: "+err.Error())
 	}
@@ -249,77 +441,41 @@ func (h *HealthRecordHandler) getByDate(ctx context.Context, dateStr string) (*m
 	return record, nil
 }
 
-// getByYearMonth retrieves record(s) for the specified year and month (YYYY, MM)
-func (h *HealthRecordHandler) getByYearMonth(ctx context.Context, yearStr, monthStr string) ([]models.HealthRecord, error) {
-	year, err := time.Parse("2006", yearStr)
+// getPage retrieves one page of the records in [start, end), selected by the limit, cursor and sort parameters
+func (h *HealthRecordHandler) getPage(ctx context.Context, userID int64, start, end time.Time, query url.Values) (HealthRecordResult, error) {
+	page, err := parsePageRequest(query)
 	if err != nil {
-		return nil, apperr.NewAppError(apperr.ErrorTypeInvalidYear, "invalid year format: "+yearStr+" (Use YYYY)")
+		return HealthRecordResult{}, err
 	}
 
-	if monthStr == "" {
-		records, err := h.DB.ReadHealthRecordsByYear(ctx, year.Year())
-		if err != nil {
-			return nil, apperr.NewAppError(apperr.ErrorTypeInternalServer, "failed to read health records: "+err.Error())
-		}
-		return records, nil
-	}
-
-	month, err := time.Parse("01", monthStr)
+	result, err := h.DB.ReadHealthRecordsPage(ctx, userID, start, end, page)
 	if err != nil {
-		return nil, apperr.NewAppError(apperr.ErrorTypeInvalidMonth, "invalid month format: "+monthStr+" (Use MM)")
-	}
-	records, err := h.DB.ReadHealthRecordsByYearMonth(ctx, year.Year(), int(month.Month()))
-	if err != nil {
-		return nil, apperr.NewAppError(apperr.ErrorTypeInternalServer, "failed to read  health records: "+err.Error())
+		return HealthRecordResult{}, apperr.NewAppError(apperr.ErrorTypeInternalServer, "failed to read health records: "+err.Error())
 	}
 
-	return records, nil
+	return HealthRecordResult{
+		Records:    result.Records,
+		NextCursor: result.NextCursor,
+		Total:      &result.Total,
+	}, nil
 }
 
-// handleError processes errors and sends appropriate responses
-func (h *HealthRecordHandler) handleError(w http.ResponseWriter, err error) {
-	var appErr apperr.AppError
-	if errors.As(err, &appErr) {
-
-		log.Printf("application error: %v, Type: %s", appErr, appErr.Type)
-
-		clientMessage := appErr.Error()
-
-		if !config.IsDev() && appErr.Type == apperr.ErrorTypeInternalServer {
-			clientMessage = "an internal server error occurred"
-		}
-
-		statusCode := http.StatusInternalServerError
-		switch appErr.Type {
-		case apperr.ErrorTypeInvalidDate, apperr.ErrorTypeInvalidYear, apperr.ErrorTypeInvalidMonth, apperr.ErrorTypeInvalidFormat, apperr.ErrorTypeBadRequest:
-			statusCode = http.StatusBadRequest
-		case apperr.ErrorTypeNotFound:
-			statusCode = http.StatusNotFound
-		}
+// yearMonthRange returns the half-open date range of the specified year, or month of the year (YYYY, MM)
+func yearMonthRange(yearStr, monthStr string) (start, end time.Time, err error) {
+	year, err := time.Parse("2006", yearStr)
+	if err != nil {
+		return time.Time{}, time.Time{}, apperr.NewAppError(apperr.ErrorTypeInvalidYear, "invalid year format: "+yearStr+" (Use YYYY)")
+	}
 
-		h.sendErrorResponse(w, apperr.AppError{Type: appErr.Type, Message: clientMessage}, statusCode)
-	} else {
-		log.Printf("unhandled error: %v", err)
-		message := "an unexpected error occurred"
-		if config.IsDevelopment {
-			message = err.Error()
-		}
-		h.sendErrorResponse(w, apperr.NewAppError(apperr.ErrorTypeInternalServer, message), http.StatusInternalServerError)
+	if monthStr == "" {
+		return year, year.AddDate(1, 0, 0), nil
 	}
-}
 
-// sendJSONResponse sends a JSON response
-func (h *HealthRecordHandler) sendJSONResponse(w http.ResponseWriter, data HealthRecordResult, statusCode int) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	if err := json.NewEncoder(w).Encode(data); err != nil {
-		h.sendErrorResponse(w, apperr.NewAppError(apperr.ErrorTypeInternalServer, "failed to encode response"), http.StatusInternalServerError)
+	month, err := time.Parse("01", monthStr)
+	if err != nil {
+		return time.Time{}, time.Time{}, apperr.NewAppError(apperr.ErrorTypeInvalidMonth, "invalid month format: "+monthStr+" (Use MM)")
 	}
-}
 
-// sendErrorResponse sends an error response
-func (h *HealthRecordHandler) sendErrorResponse(w http.ResponseWriter, err apperr.AppError, statusCode int) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+	start = time.Date(year.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 1, 0), nil
 }
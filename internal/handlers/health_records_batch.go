@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/apperr"
+	"github.com/nnamm/go-health-tracker/internal/config"
+	"github.com/nnamm/go-health-tracker/internal/models"
+)
+
+// maxBatchRecords limits the number of records of a batch write
+const maxBatchRecords = 1000
+
+// maxBatchBodySize limits the size of batch request bodies (1MB)
+const maxBatchBodySize = 1024 * 1024
+
+// Batch item statuses. Valid items of a rejected all-or-nothing batch are skipped.
+const (
+	batchStatusCreated  = "created"
+	batchStatusReplaced = "replaced"
+	batchStatusFailed   = "failed"
+	batchStatusSkipped  = "skipped"
+)
+
+// batchHealthRecordsRequest represents the request body of a batch write
+type batchHealthRecordsRequest struct {
+	Records []putHealthRecordRequest `json:"records"`
+}
+
+// BatchResult reports the outcome of a batch write, with one item per request record in request order
+type BatchResult struct {
+	Atomic  bool              `json:"atomic"`
+	Applied int               `json:"applied"`
+	Failed  int               `json:"failed"`
+	Items   []BatchItemResult `json:"items"`
+}
+
+// BatchItemResult is the outcome of a single record of a batch write
+type BatchItemResult struct {
+	Index  int                  `json:"index"`
+	Date   string               `json:"date,omitempty"`
+	Status string               `json:"status"`
+	Record *models.HealthRecord `json:"record,omitempty"`
+	Errors []apperr.FieldError  `json:"errors,omitempty"`
+}
+
+// BatchHealthRecords creates or replaces up to maxBatchRecords records in a single transaction.
+// With atomic=true (the default) any invalid record rejects the whole batch with 400 and nothing is written;
+// with atomic=false the valid records are written and the invalid ones reported as failed.
+// Best effort only covers validation: the valid records are still written in one transaction,
+// so a database error fails the whole batch and nothing is written.
+func (h *HealthRecordHandler) BatchHealthRecords(w http.ResponseWriter, r *http.Request) {
+	// set a timeout for the request context
+	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(config.RequestTimeoutSecond)*time.Second)
+	defer cancel()
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	result := BatchResult{Atomic: true}
+	if atomic := r.URL.Query().Get("atomic"); atomic != "" {
+		if result.Atomic, err = strconv.ParseBool(atomic); err != nil {
+			handleError(w, r, apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "invalid atomic: "+atomic))
+			return
+		}
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBatchBodySize))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			handleError(w, r, apperr.NewAppError(apperr.ErrorTypeBadRequest, "request body too large"))
+			return
+		}
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeInternalServer, "failed to read request body"))
+		return
+	}
+
+	var req batchHealthRecordsRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeInvalidFormat, err.Error()))
+		return
+	}
+	if len(req.Records) == 0 {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "records is required"))
+		return
+	}
+	if len(req.Records) > maxBatchRecords {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "a batch must not have more than "+strconv.Itoa(maxBatchRecords)+" records"))
+		return
+	}
+
+	// Validate every record before writing, so an all-or-nothing batch is rejected as a whole
+	result.Items = make([]BatchItemResult, len(req.Records))
+	var pending []models.HealthRecord
+	var pendingItems []int
+	seen := make(map[string]bool, len(req.Records))
+	for i, item := range req.Records {
+		result.Items[i] = BatchItemResult{Index: i, Date: item.Date}
+
+		hr, err := h.batchRecord(item, seen)
+		if err != nil {
+			result.Items[i].Status = batchStatusFailed
+			result.Items[i].Errors = fieldErrors(err)
+			result.Failed++
+			continue
+		}
+		pending = append(pending, *hr)
+		pendingItems = append(pendingItems, i)
+	}
+
+	if result.Atomic && result.Failed > 0 {
+		for _, i := range pendingItems {
+			result.Items[i].Status = batchStatusSkipped
+		}
+		sendJSONResponse(w, result, http.StatusBadRequest)
+		return
+	}
+
+	if len(pending) > 0 {
+		upserts, err := h.DB.UpsertHealthRecords(ctx, userID, pending)
+		if err != nil {
+			handleError(w, r, databaseError("failed to write health records", err))
+			return
+		}
+
+		for n, upsert := range upserts {
+			item := &result.Items[pendingItems[n]]
+			item.Record = &upsert.Record
+			item.Status = batchStatusReplaced
+			if upsert.Created {
+				item.Status = batchStatusCreated
+			}
+		}
+		result.Applied = len(upserts)
+	}
+
+	sendJSONResponse(w, result, http.StatusOK)
+}
+
+// batchRecord checks a record of a batch. A date may only appear once per batch.
+func (h *HealthRecordHandler) batchRecord(item putHealthRecordRequest, seen map[string]bool) (*models.HealthRecord, error) {
+	date, err := time.Parse("2006-01-02", item.Date)
+	if err != nil {
+		return nil, apperr.NewValidationError(apperr.ErrorTypeInvalidDate, []apperr.FieldError{
+			{Field: "date", Code: apperr.ErrorTypeInvalidDate.Code(), Message: "invalid date format: " + item.Date + " (Use YYYY-MM-DD)"},
+		})
+	}
+	if item.StepCount == nil {
+		return nil, apperr.NewValidationError(apperr.ErrorTypeInvalidFormat, []apperr.FieldError{
+			{Field: "step_count", Code: apperr.ErrorTypeInvalidFormat.Code(), Message: "step_count is required"},
+		})
+	}
+	if seen[item.Date] {
+		return nil, apperr.NewValidationError(apperr.ErrorTypeConflict, []apperr.FieldError{
+			{Field: "date", Code: apperr.ErrorTypeConflict.Code(), Message: "date appears more than once in the batch"},
+		})
+	}
+	seen[item.Date] = true
+
+	hr := &models.HealthRecord{Date: date, StepCount: *item.StepCount}
+	if err := h.validator.Validate(hr); err != nil {
+		return nil, err
+	}
+	return hr, nil
+}
+
+// fieldErrors returns the field-level problems of an error, or the error itself as a single problem
+func fieldErrors(err error) []apperr.FieldError {
+	var appErr apperr.AppError
+	if !errors.As(err, &appErr) {
+		return []apperr.FieldError{{Code: apperr.ErrorTypeInternalServer.Code(), Message: err.Error()}}
+	}
+	if len(appErr.Fields) > 0 {
+		return appErr.Fields
+	}
+	return []apperr.FieldError{{Code: appErr.Type.Code(), Message: appErr.Message}}
+}
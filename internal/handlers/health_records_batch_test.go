@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/nnamm/go-health-tracker/internal/apperr"
+	"github.com/nnamm/go-health-tracker/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchHealthRecords(t *testing.T) {
+	tests := []struct {
+		name           string
+		records        map[string]int
+		queryParams    string
+		requestBody    string
+		expectedStatus int
+		wantStatuses   []string
+		wantApplied    int
+		wantFailed     int
+		wantStored     map[string]int
+	}{
+		{
+			name:           "successful - create and replace",
+			records:        map[string]int{"2024-01-02": 5000},
+			requestBody:    `{"records": [{"date": "2024-01-01", "step_count": 8000}, {"date": "2024-01-02", "step_count": 9000}]}`,
+			expectedStatus: http.StatusOK,
+			wantStatuses:   []string{batchStatusCreated, batchStatusReplaced},
+			wantApplied:    2,
+			wantStored:     map[string]int{"2024-01-01": 8000, "2024-01-02": 9000},
+		},
+		{
+			name:           "error - all or nothing rejects the whole batch",
+			records:        map[string]int{"2024-01-02": 5000},
+			requestBody:    `{"records": [{"date": "2024-01-01", "step_count": 8000}, {"date": "2024-01-02", "step_count": -1}]}`,
+			expectedStatus: http.StatusBadRequest,
+			wantStatuses:   []string{batchStatusSkipped, batchStatusFailed},
+			wantFailed:     1,
+			wantStored:     map[string]int{"2024-01-02": 5000},
+		},
+		{
+			name:           "successful - best effort applies the valid records",
+			records:        map[string]int{"2024-01-02": 5000},
+			queryParams:    "?atomic=false",
+			requestBody:    `{"records": [{"date": "2024-01-01", "step_count": 8000}, {"date": "2024-01-02", "step_count": -1}, {"date": "2024/01/03", "step_count": 1}]}`,
+			expectedStatus: http.StatusOK,
+			wantStatuses:   []string{batchStatusCreated, batchStatusFailed, batchStatusFailed},
+			wantApplied:    1,
+			wantFailed:     2,
+			wantStored:     map[string]int{"2024-01-01": 8000, "2024-01-02": 5000},
+		},
+		{
+			name:           "error - duplicate date",
+			requestBody:    `{"records": [{"date": "2024-01-01", "step_count": 8000}, {"date": "2024-01-01", "step_count": 9000}]}`,
+			expectedStatus: http.StatusBadRequest,
+			wantStatuses:   []string{batchStatusSkipped, batchStatusFailed},
+			wantFailed:     1,
+			wantStored:     map[string]int{},
+		},
+		{
+			name:           "successful - duplicate date with best effort keeps the first",
+			queryParams:    "?atomic=false",
+			requestBody:    `{"records": [{"date": "2024-01-01", "step_count": 8000}, {"date": "2024-01-01", "step_count": 9000}]}`,
+			expectedStatus: http.StatusOK,
+			wantStatuses:   []string{batchStatusCreated, batchStatusFailed},
+			wantApplied:    1,
+			wantFailed:     1,
+			wantStored:     map[string]int{"2024-01-01": 8000},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t)
+			for date, stepCount := range tt.records {
+				createTestRecord(t, db, date, stepCount)
+			}
+
+			handler := NewHealthRecordHandler(db)
+			req := newTestRequest(http.MethodPost, "/health/records/batch"+tt.queryParams, tt.requestBody)
+
+			// Act
+			rr := serve(handler.BatchHealthRecords, req)
+
+			// Assert
+			require.Equal(t, tt.expectedStatus, rr.Code, rr.Body.String())
+
+			var result BatchResult
+			parseJSONResponse(t, rr, &result)
+			assert.Equal(t, tt.wantApplied, result.Applied)
+			assert.Equal(t, tt.wantFailed, result.Failed)
+			require.Len(t, result.Items, len(tt.wantStatuses))
+			for i, item := range result.Items {
+				assert.Equal(t, i, item.Index)
+				assert.Equal(t, tt.wantStatuses[i], item.Status, "item %d", i)
+				if item.Status == batchStatusFailed {
+					assert.NotEmpty(t, item.Errors, "item %d", i)
+				}
+				if item.Status == batchStatusCreated || item.Status == batchStatusReplaced {
+					assert.NotNil(t, item.Record, "item %d", i)
+				}
+			}
+
+			stored, err := db.ReadHealthRecordsByRange(context.Background(), testutils.TestUserID, testutils.CreateDate("2024-01-01"), testutils.CreateDate("2024-02-01"))
+			require.NoError(t, err)
+			got := make(map[string]int, len(stored))
+			for _, record := range stored {
+				got[record.Date.Format("2006-01-02")] = record.StepCount
+			}
+			assert.Equal(t, tt.wantStored, got)
+		})
+	}
+}
+
+func TestBatchHealthRecords_DuplicateDateError(t *testing.T) {
+	handler := NewHealthRecordHandler(newTestDB(t))
+	req := newTestRequest(http.MethodPost, "/health/records/batch", `{"records": [{"date": "2024-01-01", "step_count": 8000}, {"date": "2024-01-01", "step_count": 9000}]}`)
+
+	rr := serve(handler.BatchHealthRecords, req)
+
+	var result BatchResult
+	parseJSONResponse(t, rr, &result)
+	require.Len(t, result.Items, 2)
+	require.Len(t, result.Items[1].Errors, 1)
+	assert.Equal(t, "date", result.Items[1].Errors[0].Field)
+	assert.Equal(t, apperr.ErrorTypeConflict.Code(), result.Items[1].Errors[0].Code)
+}
+
+// Best effort only covers validation: the valid records are still written in one transaction,
+// so a database error fails the whole batch
+func TestBatchHealthRecords_DatabaseError(t *testing.T) {
+	db := newTestDB(t)
+	handler := NewHealthRecordHandler(db)
+	db.Close()
+	req := newTestRequest(http.MethodPost, "/health/records/batch?atomic=false", `{"records": [{"date": "2024-01-01", "step_count": 8000}, {"date": "2024-01-02", "step_count": -1}]}`)
+
+	rr := serve(handler.BatchHealthRecords, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Equal(t, "Internal Server Error", parseProblem(t, rr).Title)
+}
+
+func TestBatchHealthRecords_InvalidRequest(t *testing.T) {
+	tooMany := make([]string, maxBatchRecords+1)
+	for i := range tooMany {
+		tooMany[i] = `{"date": "2024-01-01", "step_count": 1}`
+	}
+
+	tests := []struct {
+		name         string
+		queryParams  string
+		requestBody  string
+		errorMessage string
+	}{
+		{
+			name:         "more than maxBatchRecords records",
+			requestBody:  `{"records": [` + strings.Join(tooMany, ",") + `]}`,
+			errorMessage: fmt.Sprintf("must not have more than %d records", maxBatchRecords),
+		},
+		{
+			name:         "no records",
+			requestBody:  `{"records": []}`,
+			errorMessage: "records is required",
+		},
+		{
+			name:         "invalid json",
+			requestBody:  `{"records": `,
+			errorMessage: "unexpected end of JSON input",
+		},
+		{
+			name:         "invalid atomic",
+			queryParams:  "?atomic=maybe",
+			requestBody:  `{"records": [{"date": "2024-01-01", "step_count": 1}]}`,
+			errorMessage: "invalid atomic",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t)
+			handler := NewHealthRecordHandler(db)
+			req := newTestRequest(http.MethodPost, "/health/records/batch"+tt.queryParams, tt.requestBody)
+
+			rr := serve(handler.BatchHealthRecords, req)
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+			assert.Contains(t, parseProblem(t, rr).Detail, tt.errorMessage)
+
+			stored, err := db.ReadHealthRecord(context.Background(), testutils.TestUserID, testutils.CreateDate("2024-01-01"))
+			require.NoError(t, err)
+			assert.Nil(t, stored)
+		})
+	}
+}
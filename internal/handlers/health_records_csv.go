@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/apperr"
+	"github.com/nnamm/go-health-tracker/internal/config"
+	"github.com/nnamm/go-health-tracker/internal/database"
+	"github.com/nnamm/go-health-tracker/internal/importer"
+	"github.com/nnamm/go-health-tracker/internal/logging"
+	"github.com/nnamm/go-health-tracker/internal/models"
+)
+
+// maxCSVUploadSize limits the size of uploaded CSV import files (32MB)
+const maxCSVUploadSize = 32 << 20
+
+// maxReportedRejections limits the rejected rows listed in an import result
+const maxReportedRejections = 100
+
+// ImportResult reports the outcome of an import. In a dry run nothing is written,
+// and Accepted lists what would have been written.
+// Rejected lists the rejected rows with the lowest line numbers, up to maxReportedRejections;
+// RejectedCount counts all of them.
+type ImportResult struct {
+	DryRun        bool                  `json:"dry_run"`
+	OnConflict    models.ConflictPolicy `json:"on_conflict"`
+	Accepted      []ImportedRow         `json:"accepted"`
+	Rejected      []importer.RowError   `json:"rejected"`
+	RejectedCount int                   `json:"rejected_count"`
+}
+
+// reject counts a rejected row and lists it if it is among the first maxReportedRejections lines
+func (res *ImportResult) reject(rowErr importer.RowError) {
+	res.RejectedCount++
+	if len(res.Rejected) < maxReportedRejections {
+		res.Rejected = append(res.Rejected, rowErr)
+		return
+	}
+
+	// Skipped rows are only known after the parse errors of later lines, so the last listed line may be replaced
+	last := 0
+	for i, listed := range res.Rejected {
+		if listed.Line > res.Rejected[last].Line {
+			last = i
+		}
+	}
+	if rowErr.Line < res.Rejected[last].Line {
+		res.Rejected[last] = rowErr
+	}
+}
+
+// ImportedRow is an accepted import row and whether it creates or overwrites a record
+type ImportedRow struct {
+	Line      int                 `json:"line"`
+	Date      string              `json:"date"`
+	StepCount int                 `json:"step_count"`
+	Action    models.ImportAction `json:"action"`
+}
+
+// ExportHealthRecords exports the records of a date range (from, to) as CSV
+func (h *HealthRecordHandler) ExportHealthRecords(w http.ResponseWriter, r *http.Request) {
+	// set a timeout for the request context
+	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(config.RequestTimeoutSecond)*time.Second)
+	defer cancel()
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	query := r.URL.Query()
+	if format := query.Get("format"); format != "" && format != "csv" {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "unsupported export format: "+format+" (use csv)"))
+		return
+	}
+
+	start, end, err := parseDateRange(query.Get("from"), query.Get("to"))
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	records, err := h.DB.ReadHealthRecordsByRange(ctx, userID, start, end)
+	if err != nil {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeInternalServer, "failed to read health records: "+err.Error()))
+		return
+	}
+
+	filename := "health-records-" + query.Get("from") + "-" + query.Get("to") + ".csv"
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	w.WriteHeader(http.StatusOK)
+	if err := importer.WriteCSV(w, records); err != nil {
+		// The status is already sent, so the client can only notice a truncated file
+		logging.FromContext(r.Context()).Error("failed to write csv export", slog.Any("error", err))
+	}
+}
+
+// ImportHealthRecords imports records from a text/csv request body of up to maxCSVUploadSize.
+// The body is parsed as it streams in and each valid row is written as soon as it is read,
+// all in a single transaction, so a failed import stores nothing.
+// Invalid rows are rejected while the valid ones are imported. Rows for dates that already have a record
+// are skipped, overwritten or fail the whole import depending on the on_conflict parameter,
+// and with dry_run=true the transaction is rolled back.
+func (h *HealthRecordHandler) ImportHealthRecords(w http.ResponseWriter, r *http.Request) {
+	// Large files take longer than a regular request,
+	// so the server's read and write deadlines are extended as well
+	timeout := time.Duration(config.ImportTimeoutSecond) * time.Second
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	if err := extendDeadlines(w, timeout); err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != "text/csv" {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeBadRequest, "Content-Type must be text/csv"))
+		return
+	}
+
+	query := r.URL.Query()
+	result := ImportResult{
+		Accepted: []ImportedRow{},
+		Rejected: []importer.RowError{},
+	}
+
+	if result.OnConflict, err = models.ParseConflictPolicy(query.Get("on_conflict")); err != nil {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeInvalidFormat, err.Error()))
+		return
+	}
+	if dryRun := query.Get("dry_run"); dryRun != "" {
+		if result.DryRun, err = strconv.ParseBool(dryRun); err != nil {
+			handleError(w, r, apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "invalid dry_run: "+dryRun))
+			return
+		}
+	}
+
+	csvReader, err := importer.NewCSVReader(http.MaxBytesReader(w, r.Body, maxCSVUploadSize), h.validator)
+	if err != nil {
+		handleError(w, r, csvReadError(err))
+		return
+	}
+
+	// The store reports one outcome per yielded record, in order, so the lines are kept to match them up
+	var lines []int
+	var last importer.Row
+	var readErr error
+	rows := func(yield func(models.HealthRecord, error) bool) {
+		for {
+			row, err := csvReader.Read()
+			if err == io.EOF {
+				return
+			}
+			var rowErr *importer.RowError
+			if errors.As(err, &rowErr) {
+				result.reject(*rowErr)
+				continue
+			}
+			if err != nil {
+				readErr = err
+				yield(models.HealthRecord{}, err)
+				return
+			}
+
+			lines = append(lines, row.Line)
+			last = row
+			if !yield(row.Record, nil) {
+				return
+			}
+		}
+	}
+
+	imports, err := h.DB.ImportHealthRecords(ctx, userID, rows, models.ImportOptions{OnConflict: result.OnConflict, DryRun: result.DryRun})
+	switch {
+	case readErr != nil:
+		handleError(w, r, csvReadError(readErr))
+		return
+	case errors.Is(err, database.ErrConflict):
+		// The import stopped at the conflicting row, the last one read, and wrote nothing
+		result.reject(importer.RowError{Line: last.Line, Reason: "a record already exists for " + last.Record.Date.Format("2006-01-02")})
+		sort.Slice(result.Rejected, func(i, j int) bool { return result.Rejected[i].Line < result.Rejected[j].Line })
+		sendJSONResponse(w, result, http.StatusConflict)
+		return
+	case err != nil:
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeInternalServer, "failed to import health records: "+err.Error()))
+		return
+	}
+
+	for i, imported := range imports {
+		date := imported.Record.Date.Format("2006-01-02")
+		if imported.Action == models.ImportSkipped {
+			result.reject(importer.RowError{Line: lines[i], Reason: "a record already exists for " + date})
+			continue
+		}
+		result.Accepted = append(result.Accepted, ImportedRow{Line: lines[i], Date: date, StepCount: imported.Record.StepCount, Action: imported.Action})
+	}
+	sort.Slice(result.Rejected, func(i, j int) bool { return result.Rejected[i].Line < result.Rejected[j].Line })
+
+	sendJSONResponse(w, result, http.StatusOK)
+}
+
+// csvReadError maps an error reading a CSV upload to the error returned to the client
+func csvReadError(err error) error {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return apperr.NewAppError(apperr.ErrorTypeBadRequest, "request body too large")
+	}
+	return apperr.NewAppError(apperr.ErrorTypeInvalidFormat, err.Error())
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/nnamm/go-health-tracker/internal/importer"
+	"github.com/nnamm/go-health-tracker/internal/models"
+	"github.com/nnamm/go-health-tracker/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthRecordHandler_ImportHealthRecords(t *testing.T) {
+	db := newTestDB(t)
+	handler := NewHealthRecordHandler(db)
+	createTestRecord(t, db, "2024-01-02", 8000)
+
+	importCSV := func(query string) *http.Request {
+		body := "date,step_count\n2024-01-01,7000\n2024-01-02,9500\nbad,1\n2024-01-03,6000\n"
+		req := newTestRequest(http.MethodPost, "/health/records/import"+query, body)
+		req.Header.Set("Content-Type", "text/csv")
+		return req
+	}
+	stepCount := func(date string) int {
+		hr, err := db.ReadHealthRecord(t.Context(), testutils.TestUserID, testutils.CreateDate(date))
+		require.NoError(t, err)
+		if hr == nil {
+			return 0
+		}
+		return hr.StepCount
+	}
+
+	// The fail policy stops at the first stored date and writes nothing
+	rr := serve(handler.ImportHealthRecords, importCSV("?on_conflict=fail"))
+	require.Equal(t, http.StatusConflict, rr.Code, rr.Body.String())
+	var result ImportResult
+	parseJSONResponse(t, rr, &result)
+	assert.Empty(t, result.Accepted)
+	assert.Equal(t, []importer.RowError{{Line: 3, Reason: "a record already exists for 2024-01-02"}}, result.Rejected)
+	assert.Zero(t, stepCount("2024-01-01"))
+
+	rr = serve(handler.ImportHealthRecords, importCSV("?dry_run=true"))
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	assert.Zero(t, stepCount("2024-01-01"))
+
+	rr = serve(handler.ImportHealthRecords, importCSV(""))
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	result = ImportResult{}
+	parseJSONResponse(t, rr, &result)
+	assert.Equal(t, models.ConflictSkip, result.OnConflict)
+	assert.Equal(t, []ImportedRow{
+		{Line: 2, Date: "2024-01-01", StepCount: 7000, Action: models.ImportCreated},
+		{Line: 5, Date: "2024-01-03", StepCount: 6000, Action: models.ImportCreated},
+	}, result.Accepted)
+	require.Len(t, result.Rejected, 2)
+	assert.Equal(t, importer.RowError{Line: 3, Reason: "a record already exists for 2024-01-02"}, result.Rejected[0])
+	assert.Equal(t, 4, result.Rejected[1].Line)
+	assert.Equal(t, 8000, stepCount("2024-01-02"))
+
+	rr = serve(handler.ImportHealthRecords, importCSV("?on_conflict=overwrite"))
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	result = ImportResult{}
+	parseJSONResponse(t, rr, &result)
+	require.Len(t, result.Accepted, 3)
+	assert.Equal(t, models.ImportOverwritten, result.Accepted[1].Action)
+	assert.Equal(t, 9500, stepCount("2024-01-02"))
+}
+
+func TestHealthRecordHandler_ImportHealthRecords_Limits(t *testing.T) {
+	handler := NewHealthRecordHandler(newTestDB(t))
+	importCSV := func(body string) *http.Request {
+		req := newTestRequest(http.MethodPost, "/health/records/import", body)
+		req.Header.Set("Content-Type", "text/csv")
+		return req
+	}
+
+	// Only the first rejected rows are listed, but all are counted
+	var body strings.Builder
+	body.WriteString("date,step_count\n")
+	for i := range maxReportedRejections + 50 {
+		body.WriteString("junk," + strconv.Itoa(i) + "\n")
+	}
+	body.WriteString("2024-01-01,8000\n")
+
+	rr := serve(handler.ImportHealthRecords, importCSV(body.String()))
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var result ImportResult
+	parseJSONResponse(t, rr, &result)
+	assert.Len(t, result.Accepted, 1)
+	assert.Equal(t, maxReportedRejections+50, result.RejectedCount)
+	require.Len(t, result.Rejected, maxReportedRejections)
+	assert.Equal(t, 2, result.Rejected[0].Line)
+	assert.Equal(t, maxReportedRejections+1, result.Rejected[maxReportedRejections-1].Line)
+
+	rr = serve(handler.ImportHealthRecords, importCSV("date,step_count\n"+strings.Repeat("2024-01-01,8000\n", maxCSVUploadSize/16+1)))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, "request body too large", parseProblem(t, rr).Detail)
+}
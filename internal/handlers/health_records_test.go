@@ -5,9 +5,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
-	"time"
 
-	"github.com/nnamm/go-health-tracker/internal/models"
+	"github.com/nnamm/go-health-tracker/internal/database"
+	"github.com/nnamm/go-health-tracker/testutils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -15,7 +15,7 @@ import (
 func TestCreateHealthRecord(t *testing.T) {
 	tests := []struct {
 		name           string
-		setupMock      func(*mock.MockDB)
+		setupDB        func(*testing.T, *database.SQLiteDB)
 		requestBody    string
 		expectedStatus int
 		wantError      bool
@@ -24,16 +24,18 @@ func TestCreateHealthRecord(t *testing.T) {
 	}{
 		{
 			name:           "successful - normal creation",
-			requestBody:    handlertest.CreateHealthRecordJSON(t, time.Date(2024, 7, 10, 0, 0, 0, 0, time.UTC), 10000),
+			requestBody:    `{"date": "2024-07-10", "step_count": 10000}`,
 			expectedStatus: http.StatusCreated,
 			checkResponse: func(t *testing.T, rr *httptest.ResponseRecorder) {
 				var result HealthRecordResult
-				handlertest.ParseJSONResponse(t, rr.Body.Bytes(), &result)
+				parseJSONResponse(t, rr, &result)
 				require.Len(t, result.Records, 1)
 
 				record := result.Records[0]
 				assert.Equal(t, 10000, record.StepCount)
 				assert.Equal(t, "2024-07-10", record.Date.Format("2006-01-02"))
+				assert.Equal(t, int64(1), record.Version)
+				assert.Equal(t, `"1"`, rr.Header().Get("ETag"))
 			},
 		},
 		{
@@ -72,46 +74,44 @@ func TestCreateHealthRecord(t *testing.T) {
 			errorMessage:   "step count must not be negative",
 		},
 		{
-			name: "error - database error",
-			setupMock: func(db *mock.MockDB) {
-				db.SetSimulateDBError(true)
+			name: "error - record already exists",
+			setupDB: func(t *testing.T, db *database.SQLiteDB) {
+				createTestRecord(t, db, "2024-07-10", 8000)
 			},
-			requestBody:    handlertest.CreateHealthRecordJSON(t, time.Date(2024, 7, 10, 0, 0, 0, 0, time.UTC), 10000),
-			expectedStatus: http.StatusInternalServerError,
+			requestBody:    `{"date": "2024-07-10", "step_count": 10000}`,
+			expectedStatus: http.StatusConflict,
 			wantError:      true,
 			errorMessage:   "failed to create health record",
 		},
 		{
-			name: "error - timeout error",
-			setupMock: func(db *mock.MockDB) {
-				db.SetSimulateTimeout(true)
+			name: "error - database error",
+			setupDB: func(t *testing.T, db *database.SQLiteDB) {
+				db.Close()
 			},
-			requestBody:    handlertest.CreateHealthRecordJSON(t, time.Date(2024, 7, 10, 0, 0, 0, 0, time.UTC), 10000),
+			requestBody:    `{"date": "2024-07-10", "step_count": 10000}`,
 			expectedStatus: http.StatusInternalServerError,
 			wantError:      true,
-			errorMessage:   "failed to create health record",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mockDB := mock.NewMockDB()
-			if tt.setupMock != nil {
-				tt.setupMock(mockDB)
+			db := newTestDB(t)
+			if tt.setupDB != nil {
+				tt.setupDB(t, db)
 			}
 
-			handler := NewHealthRecordHandler(mockDB)
-			ctx := context.Background()
-			req := handlertest.CreateRequestContext(ctx, http.MethodPost, "/health/records", tt.requestBody)
+			handler := NewHealthRecordHandler(db)
+			req := newTestRequest(http.MethodPost, "/health/records", tt.requestBody)
 
 			// Act
-			rr := handlertest.ExecuteHandlerRequest(t, handler.CreateHealthRecord, req)
+			rr := serve(handler.CreateHealthRecord, req)
 
 			// Assert
-			handlertest.AssertHTTPStatusCode(t, rr.Code, tt.expectedStatus)
+			assert.Equal(t, tt.expectedStatus, rr.Code)
 
 			if tt.wantError {
-				handlertest.AssertErrorResponse(t, rr.Body.Bytes(), tt.errorMessage)
+				assert.Contains(t, parseProblem(t, rr).Detail, tt.errorMessage)
 			} else if tt.checkResponse != nil {
 				tt.checkResponse(t, rr)
 			}
@@ -122,7 +122,8 @@ func TestCreateHealthRecord(t *testing.T) {
 func TestGetHealthRecord(t *testing.T) {
 	tests := []struct {
 		name           string
-		setupMock      func(*testing.T) *mock.MockDB
+		records        map[string]int
+		setupDB        func(*testing.T, *database.SQLiteDB)
 		queryParams    string
 		expectedStatus int
 		wantError      bool
@@ -130,80 +131,59 @@ func TestGetHealthRecord(t *testing.T) {
 		checkResponse  func(*testing.T, *httptest.ResponseRecorder)
 	}{
 		{
-			name: "successful - get by date",
-			setupMock: func(t *testing.T) *mock.MockDB {
-				records := []models.HealthRecord{
-					{Date: handlertest.ParseAPIDateFormat("2024-01-01"), StepCount: 10000},
-				}
-				return handlertest.SetupMockDBWithRecords(t, records)
-			},
+			name:           "successful - get by date",
+			records:        map[string]int{"2024-01-01": 10000},
 			queryParams:    "?date=20240101",
 			expectedStatus: http.StatusOK,
 			checkResponse: func(t *testing.T, rr *httptest.ResponseRecorder) {
 				var result HealthRecordResult
-				handlertest.ParseJSONResponse(t, rr.Body.Bytes(), &result)
+				parseJSONResponse(t, rr, &result)
 
 				require.Len(t, result.Records, 1)
 				record := result.Records[0]
 				assert.Equal(t, "2024-01-01", record.Date.Format("2006-01-02"))
 				assert.Equal(t, 10000, record.StepCount)
+				assert.Equal(t, `"1"`, rr.Header().Get("ETag"))
 			},
 		},
 		{
-			name: "successful - data not exist",
-			setupMock: func(t *testing.T) *mock.MockDB {
-				records := []models.HealthRecord{
-					{Date: handlertest.ParseAPIDateFormat("2025-01-01"), StepCount: 10000},
-				}
-				return handlertest.SetupMockDBWithRecords(t, records)
-			},
+			name:           "successful - data not exist",
+			records:        map[string]int{"2025-01-01": 10000},
 			queryParams:    "?date=20240101",
 			expectedStatus: http.StatusOK,
 			checkResponse: func(t *testing.T, rr *httptest.ResponseRecorder) {
 				var result HealthRecordResult
-				handlertest.ParseJSONResponse(t, rr.Body.Bytes(), &result)
+				parseJSONResponse(t, rr, &result)
 
 				require.Len(t, result.Records, 0)
 			},
 		},
 		{
-			name: "successful - get by year",
-			setupMock: func(t *testing.T) *mock.MockDB {
-				records := []models.HealthRecord{
-					{Date: handlertest.ParseAPIDateFormat("2024-01-01"), StepCount: 10000},
-					{Date: handlertest.ParseAPIDateFormat("2024-02-01"), StepCount: 11000},
-					{Date: handlertest.ParseAPIDateFormat("2025-12-01"), StepCount: 12000},
-				}
-				return handlertest.SetupMockDBWithRecords(t, records)
-			},
+			name:           "successful - get by year",
+			records:        map[string]int{"2024-01-01": 10000, "2024-02-01": 11000, "2025-12-01": 12000},
 			queryParams:    "?year=2024",
 			expectedStatus: http.StatusOK,
 			checkResponse: func(t *testing.T, rr *httptest.ResponseRecorder) {
 				var result HealthRecordResult
-				handlertest.ParseJSONResponse(t, rr.Body.Bytes(), &result)
+				parseJSONResponse(t, rr, &result)
 
 				require.Len(t, result.Records, 2)
 				assert.Equal(t, "2024-01-01", result.Records[0].Date.Format("2006-01-02"))
 				assert.Equal(t, 10000, result.Records[0].StepCount)
 				assert.Equal(t, "2024-02-01", result.Records[1].Date.Format("2006-01-02"))
 				assert.Equal(t, 11000, result.Records[1].StepCount)
+				require.NotNil(t, result.Total)
+				assert.Equal(t, 2, *result.Total)
 			},
 		},
 		{
-			name: "successful - get by year and month",
-			setupMock: func(t *testing.T) *mock.MockDB {
-				records := []models.HealthRecord{
-					{Date: handlertest.ParseAPIDateFormat("2024-01-01"), StepCount: 10000},
-					{Date: handlertest.ParseAPIDateFormat("2024-01-15"), StepCount: 11000},
-					{Date: handlertest.ParseAPIDateFormat("2025-12-01"), StepCount: 12000},
-				}
-				return handlertest.SetupMockDBWithRecords(t, records)
-			},
+			name:           "successful - get by year and month",
+			records:        map[string]int{"2024-01-01": 10000, "2024-01-15": 11000, "2025-12-01": 12000},
 			queryParams:    "?year=2024&month=01",
 			expectedStatus: http.StatusOK,
 			checkResponse: func(t *testing.T, rr *httptest.ResponseRecorder) {
 				var result HealthRecordResult
-				handlertest.ParseJSONResponse(t, rr.Body.Bytes(), &result)
+				parseJSONResponse(t, rr, &result)
 
 				require.Len(t, result.Records, 2)
 				assert.Equal(t, "2024-01-01", result.Records[0].Date.Format("2006-01-02"))
@@ -213,40 +193,28 @@ func TestGetHealthRecord(t *testing.T) {
 			},
 		},
 		{
-			name: "error - invalid date format",
-			setupMock: func(t *testing.T) *mock.MockDB {
-				return mock.NewMockDB()
-			},
+			name:           "error - invalid date format",
 			queryParams:    "?date=2024/01/01", // Wrong format
 			expectedStatus: http.StatusBadRequest,
 			wantError:      true,
 			errorMessage:   "invalid date format",
 		},
 		{
-			name: "error - invalid year format",
-			setupMock: func(t *testing.T) *mock.MockDB {
-				return mock.NewMockDB()
-			},
+			name:           "error - invalid year format",
 			queryParams:    "?year=invalid",
 			expectedStatus: http.StatusBadRequest,
 			wantError:      true,
 			errorMessage:   "invalid year format",
 		},
 		{
-			name: "error - invalid month format",
-			setupMock: func(t *testing.T) *mock.MockDB {
-				return mock.NewMockDB()
-			},
+			name:           "error - invalid month format",
 			queryParams:    "?year=2024&month=invalid",
 			expectedStatus: http.StatusBadRequest,
 			wantError:      true,
 			errorMessage:   "invalid month format",
 		},
 		{
-			name: "error - missing query parameters",
-			setupMock: func(t *testing.T) *mock.MockDB {
-				return mock.NewMockDB()
-			},
+			name:           "error - missing query parameters",
 			queryParams:    "",
 			expectedStatus: http.StatusBadRequest,
 			wantError:      true,
@@ -254,45 +222,36 @@ func TestGetHealthRecord(t *testing.T) {
 		},
 		{
 			name: "error - database error",
-			setupMock: func(t *testing.T) *mock.MockDB {
-				mockDB := mock.NewMockDB()
-				mockDB.SetSimulateDBError(true)
-				return mockDB
-			},
-			queryParams:    "?date=20250101",
-			expectedStatus: http.StatusInternalServerError,
-			wantError:      true,
-			errorMessage:   "failed to read health record",
-		},
-		{
-			name: "error - timeout",
-			setupMock: func(t *testing.T) *mock.MockDB {
-				mockDB := mock.NewMockDB()
-				mockDB.SetSimulateTimeout(true)
-				return mockDB
+			setupDB: func(t *testing.T, db *database.SQLiteDB) {
+				db.Close()
 			},
 			queryParams:    "?date=20250101",
 			expectedStatus: http.StatusInternalServerError,
 			wantError:      true,
-			errorMessage:   "failed to read health record",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mockDB := tt.setupMock(t)
-			handler := NewHealthRecordHandler(mockDB)
-			ctx := context.Background()
-			req := handlertest.CreateRequestContext(ctx, http.MethodGet, "/health/records"+tt.queryParams, "")
+			db := newTestDB(t)
+			for date, stepCount := range tt.records {
+				createTestRecord(t, db, date, stepCount)
+			}
+			if tt.setupDB != nil {
+				tt.setupDB(t, db)
+			}
+
+			handler := NewHealthRecordHandler(db)
+			req := newTestRequest(http.MethodGet, "/health/records"+tt.queryParams, "")
 
 			// Act
-			rr := handlertest.ExecuteHandlerRequest(t, handler.GetHealthRecords, req)
+			rr := serve(handler.GetHealthRecords, req)
 
 			// Assert
-			handlertest.AssertHTTPStatusCode(t, rr.Code, tt.expectedStatus)
+			assert.Equal(t, tt.expectedStatus, rr.Code)
 
 			if tt.wantError {
-				handlertest.AssertErrorResponse(t, rr.Body.Bytes(), tt.errorMessage)
+				assert.Contains(t, parseProblem(t, rr).Detail, tt.errorMessage)
 			} else if tt.checkResponse != nil {
 				tt.checkResponse(t, rr)
 			}
@@ -303,7 +262,8 @@ func TestGetHealthRecord(t *testing.T) {
 func TestUpdateHealthRecord(t *testing.T) {
 	tests := []struct {
 		name           string
-		setupMock      func(*testing.T) *mock.MockDB
+		records        map[string]int
+		setupDB        func(*testing.T, *database.SQLiteDB)
 		requestBody    string
 		expectedStatus int
 		wantError      bool
@@ -311,122 +271,93 @@ func TestUpdateHealthRecord(t *testing.T) {
 		checkResponse  func(*testing.T, *httptest.ResponseRecorder)
 	}{
 		{
-			name: "successful - normal update",
-			setupMock: func(t *testing.T) *mock.MockDB {
-				records := []models.HealthRecord{
-					{Date: handlertest.ParseAPIDateFormat("2025-01-01"), StepCount: 10000},
-				}
-				return handlertest.SetupMockDBWithRecords(t, records)
-			},
-			requestBody:    handlertest.CreateHealthRecordJSON(t, handlertest.ParseAPIDateFormat("2025-01-01"), 15000),
+			name:           "successful - normal update",
+			records:        map[string]int{"2025-01-01": 10000},
+			requestBody:    `{"date": "2025-01-01", "step_count": 15000}`,
 			expectedStatus: http.StatusOK,
 			checkResponse: func(t *testing.T, rr *httptest.ResponseRecorder) {
 				var result HealthRecordResult
-				handlertest.ParseJSONResponse(t, rr.Body.Bytes(), &result)
+				parseJSONResponse(t, rr, &result)
 
 				require.Len(t, result.Records, 1)
 				assert.Equal(t, 15000, result.Records[0].StepCount)
+				assert.Equal(t, `"2"`, rr.Header().Get("ETag"))
 			},
 		},
 		{
-			name: "successful - zero step count",
-			setupMock: func(t *testing.T) *mock.MockDB {
-				records := []models.HealthRecord{
-					{Date: handlertest.ParseAPIDateFormat("2025-01-01"), StepCount: 10000},
-				}
-				return handlertest.SetupMockDBWithRecords(t, records)
-			},
-			requestBody:    handlertest.CreateHealthRecordJSON(t, handlertest.ParseAPIDateFormat("2025-01-01"), 0),
+			name:           "successful - zero step count",
+			records:        map[string]int{"2025-01-01": 10000},
+			requestBody:    `{"date": "2025-01-01", "step_count": 0}`,
 			expectedStatus: http.StatusOK,
 			checkResponse: func(t *testing.T, rr *httptest.ResponseRecorder) {
 				var result HealthRecordResult
-				handlertest.ParseJSONResponse(t, rr.Body.Bytes(), &result)
+				parseJSONResponse(t, rr, &result)
 
 				require.Len(t, result.Records, 1)
 				assert.Equal(t, 0, result.Records[0].StepCount)
 			},
 		},
 		{
-			name: "successful - no existing record (no update)",
-			setupMock: func(t *testing.T) *mock.MockDB {
-				return mock.NewMockDB()
-			},
-			requestBody:    handlertest.CreateHealthRecordJSON(t, handlertest.ParseAPIDateFormat("2025-01-01"), 15000),
-			expectedStatus: http.StatusInternalServerError,
+			name:           "error - no existing record",
+			requestBody:    `{"date": "2025-01-01", "step_count": 15000}`,
+			expectedStatus: http.StatusNotFound,
 			wantError:      true,
-			errorMessage:   "record not found",
+			errorMessage:   "failed to update health record",
 		},
 		{
-			name: "error - invalid request body",
-			setupMock: func(t *testing.T) *mock.MockDB {
-				return mock.NewMockDB()
-			},
+			name:           "error - invalid request body",
 			requestBody:    "",
 			expectedStatus: http.StatusBadRequest,
 			wantError:      true,
 			errorMessage:   "failed to unmarshal health record",
 		},
 		{
-			name: "error - validation error (negative step count)",
-			setupMock: func(t *testing.T) *mock.MockDB {
-				return mock.NewMockDB()
-			},
-			requestBody:    handlertest.CreateHealthRecordJSON(t, handlertest.ParseAPIDateFormat("2025-01-01"), -10000),
+			name:           "error - validation error (negative step count)",
+			requestBody:    `{"date": "2025-01-01", "step_count": -10000}`,
 			expectedStatus: http.StatusBadRequest,
 			wantError:      true,
 			errorMessage:   "step count must not be negative",
 		},
 		{
-			name: "error - validation error (too many step count)",
-			setupMock: func(t *testing.T) *mock.MockDB {
-				return mock.NewMockDB()
-			},
-			requestBody:    handlertest.CreateHealthRecordJSON(t, handlertest.ParseAPIDateFormat("2025-01-01"), 1000001),
+			name:           "error - validation error (too many step count)",
+			requestBody:    `{"date": "2025-01-01", "step_count": 1000001}`,
 			expectedStatus: http.StatusBadRequest,
 			wantError:      true,
 			errorMessage:   "step count is unrealistically high",
 		},
 		{
-			name: "error - database error",
-			setupMock: func(t *testing.T) *mock.MockDB {
-				mockDB := mock.NewMockDB()
-				mockDB.SetSimulateDBError(true)
-				return mockDB
+			name:    "error - database error",
+			records: map[string]int{"2025-01-01": 10000},
+			setupDB: func(t *testing.T, db *database.SQLiteDB) {
+				db.Close()
 			},
-			requestBody:    handlertest.CreateHealthRecordJSON(t, handlertest.ParseAPIDateFormat("2025-03-01"), 5000),
+			requestBody:    `{"date": "2025-01-01", "step_count": 15000}`,
 			expectedStatus: http.StatusInternalServerError,
 			wantError:      true,
-			errorMessage:   "failed to update health record",
-		},
-		{
-			name: "error - timeout",
-			setupMock: func(t *testing.T) *mock.MockDB {
-				mockDB := mock.NewMockDB()
-				mockDB.SetSimulateTimeout(true)
-				return mockDB
-			},
-			requestBody:    handlertest.CreateHealthRecordJSON(t, handlertest.ParseAPIDateFormat("2025-03-01"), 5000),
-			expectedStatus: http.StatusInternalServerError,
-			wantError:      true,
-			errorMessage:   "failed to update health record",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mockDB := tt.setupMock(t)
-			handler := NewHealthRecordHandler(mockDB)
-			ctx := context.Background()
-			req := handlertest.CreateRequestContext(ctx, http.MethodGet, "/health/records", tt.requestBody)
+			db := newTestDB(t)
+			for date, stepCount := range tt.records {
+				createTestRecord(t, db, date, stepCount)
+			}
+			if tt.setupDB != nil {
+				tt.setupDB(t, db)
+			}
+
+			handler := NewHealthRecordHandler(db)
+			req := newTestRequest(http.MethodPut, "/health/records", tt.requestBody)
 
 			// Act
-			rr := handlertest.ExecuteHandlerRequest(t, handler.UpdateHealthRecord, req)
+			rr := serve(handler.UpdateHealthRecord, req)
 
 			// Assert
-			handlertest.AssertHTTPStatusCode(t, rr.Code, tt.expectedStatus)
+			assert.Equal(t, tt.expectedStatus, rr.Code)
 
 			if tt.wantError {
-				handlertest.AssertErrorResponse(t, rr.Body.Bytes(), tt.errorMessage)
+				assert.Contains(t, parseProblem(t, rr).Detail, tt.errorMessage)
 			} else if tt.checkResponse != nil {
 				tt.checkResponse(t, rr)
 			}
@@ -437,105 +368,89 @@ func TestUpdateHealthRecord(t *testing.T) {
 func TestDeleteHealthRecord(t *testing.T) {
 	tests := []struct {
 		name           string
-		setupMock      func(*testing.T) *mock.MockDB
+		records        map[string]int
+		setupDB        func(*testing.T, *database.SQLiteDB)
 		queryParams    string
 		expectedStatus int
 		wantError      bool
 		errorMessage   string
-		checkResponse  func(*testing.T, *httptest.ResponseRecorder)
 	}{
 		{
-			name: "successful - normal delete",
-			setupMock: func(t *testing.T) *mock.MockDB {
-				records := []models.HealthRecord{
-					{Date: handlertest.ParseAPIDateFormat("2025-01-01"), StepCount: 10000},
-				}
-				return handlertest.SetupMockDBWithRecords(t, records)
-			},
+			name:           "successful - normal delete",
+			records:        map[string]int{"2025-01-01": 10000},
 			queryParams:    "?date=20250101",
 			expectedStatus: http.StatusOK,
-			checkResponse: func(t *testing.T, rr *httptest.ResponseRecorder) {
-				var result HealthRecordResult
-				handlertest.ParseJSONResponse(t, rr.Body.Bytes(), &result)
-
-				require.Len(t, result.Records, 0)
-			},
 		},
 		{
-			name: "error - record not found",
-			setupMock: func(t *testing.T) *mock.MockDB {
-				// Setup an empty mock DB without the requested record
-				return mock.NewMockDB()
-			},
+			name:           "error - record not found",
 			queryParams:    "?date=20250101",
-			expectedStatus: http.StatusInternalServerError,
+			expectedStatus: http.StatusNotFound,
 			wantError:      true,
-			errorMessage:   "record not found",
+			errorMessage:   "failed to delete health record",
 		},
 		{
-			name: "error - invalid date format",
-			setupMock: func(t *testing.T) *mock.MockDB {
-				return mock.NewMockDB()
-			},
+			name:           "error - invalid date format",
 			queryParams:    "?date=2025/01/01", // Wrong format
 			expectedStatus: http.StatusBadRequest,
 			wantError:      true,
 			errorMessage:   "invalid date format",
 		},
 		{
-			name: "error - missing query parameters",
-			setupMock: func(t *testing.T) *mock.MockDB {
-				return mock.NewMockDB()
-			},
+			name:           "error - missing query parameters",
 			queryParams:    "",
 			expectedStatus: http.StatusBadRequest,
 			wantError:      true,
 			errorMessage:   "date parameter is required",
 		},
 		{
-			name: "error - database error",
-			setupMock: func(t *testing.T) *mock.MockDB {
-				mockDB := mock.NewMockDB()
-				mockDB.SetSimulateDBError(true)
-				return mockDB
+			name:    "error - database error",
+			records: map[string]int{"2025-01-01": 10000},
+			setupDB: func(t *testing.T, db *database.SQLiteDB) {
+				db.Close()
 			},
 			queryParams:    "?date=20250101",
 			expectedStatus: http.StatusInternalServerError,
 			wantError:      true,
-			errorMessage:   "failed to delete health record",
-		},
-		{
-			name: "error - timeout",
-			setupMock: func(t *testing.T) *mock.MockDB {
-				mockDB := mock.NewMockDB()
-				mockDB.SetSimulateTimeout(true)
-				return mockDB
-			},
-			queryParams:    "?date=20250101",
-			expectedStatus: http.StatusInternalServerError,
-			wantError:      true,
-			errorMessage:   "failed to delete health record",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mockDB := tt.setupMock(t)
-			handler := NewHealthRecordHandler(mockDB)
-			ctx := context.Background()
-			req := handlertest.CreateRequestContext(ctx, http.MethodDelete, "/health/records"+tt.queryParams, "")
+			db := newTestDB(t)
+			for date, stepCount := range tt.records {
+				createTestRecord(t, db, date, stepCount)
+			}
+			if tt.setupDB != nil {
+				tt.setupDB(t, db)
+			}
+
+			handler := NewHealthRecordHandler(db)
+			req := newTestRequest(http.MethodDelete, "/health/records"+tt.queryParams, "")
 
 			// Act
-			rr := handlertest.ExecuteHandlerRequest(t, handler.DeleteHealthRecord, req)
+			rr := serve(handler.DeleteHealthRecord, req)
 
 			// Assert
-			handlertest.AssertHTTPStatusCode(t, rr.Code, tt.expectedStatus)
+			assert.Equal(t, tt.expectedStatus, rr.Code)
 
 			if tt.wantError {
-				handlertest.AssertErrorResponse(t, rr.Body.Bytes(), tt.errorMessage)
-			} else if tt.checkResponse != nil {
-				tt.checkResponse(t, rr)
+				assert.Contains(t, parseProblem(t, rr).Detail, tt.errorMessage)
+				return
 			}
+
+			stored, err := db.ReadHealthRecord(context.Background(), testutils.TestUserID, testutils.CreateDate("2025-01-01"))
+			require.NoError(t, err)
+			assert.Nil(t, stored)
 		})
 	}
 }
+
+func TestHealthRecordHandler_NoUser(t *testing.T) {
+	handler := NewHealthRecordHandler(newTestDB(t))
+	req := httptest.NewRequest(http.MethodGet, "/health/records?date=20240101", nil)
+
+	rr := serve(handler.GetHealthRecords, req)
+
+	// The server middleware always attaches a user, so a request without one is a server error
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+}
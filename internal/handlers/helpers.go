@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/apperr"
+	"github.com/nnamm/go-health-tracker/internal/auth"
+	"github.com/nnamm/go-health-tracker/internal/config"
+	"github.com/nnamm/go-health-tracker/internal/database"
+	"github.com/nnamm/go-health-tracker/internal/logging"
+	"github.com/nnamm/go-health-tracker/internal/models"
+	"github.com/nnamm/go-health-tracker/internal/validators"
+)
+
+// maxRequestBodySize limits the size of JSON request bodies (8KB)
+const maxRequestBodySize = 8 * 1024
+
+// readRequestBody reads the size-limited request body asynchronously,
+// so that a cancelled or timed out request does not wait for a slow client.
+func readRequestBody(ctx context.Context, w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodySize)
+
+	// Create channels to handle the request body and errors for async processing
+	bodyCh := make(chan []byte, 1)
+	errCh := make(chan error, 1)
+
+	go func() {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			errCh <- err
+			return
+		}
+		bodyCh <- body
+	}()
+
+	// Check if the request has been cancelled or timed out
+	select {
+	case <-ctx.Done():
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, apperr.NewAppError(apperr.ErrorTypeInternalServer, "request processing timed out")
+		}
+		return nil, apperr.NewAppError(apperr.ErrorTypeInternalServer, "request was cancelled")
+	case err := <-errCh:
+		if err.Error() == "http: request body too large" {
+			return nil, apperr.NewAppError(apperr.ErrorTypeBadRequest, "request body too large")
+		}
+		return nil, apperr.NewAppError(apperr.ErrorTypeInternalServer, "failed to read request body")
+	case body := <-bodyCh:
+		return body, nil
+	}
+}
+
+// databaseError converts an error of a database write into an AppError.
+// Missing, duplicate and concurrently modified records are client errors; anything else is a server error.
+func databaseError(message string, err error) error {
+	switch {
+	case errors.Is(err, database.ErrNotFound):
+		return apperr.NewAppError(apperr.ErrorTypeNotFound, message+": "+err.Error())
+	case errors.Is(err, database.ErrConflict):
+		return apperr.NewAppError(apperr.ErrorTypeConflict, message+": "+err.Error())
+	case errors.Is(err, database.ErrVersionMismatch):
+		return apperr.NewAppError(apperr.ErrorTypePreconditionFailed, message+": "+err.Error())
+	default:
+		return apperr.NewAppError(apperr.ErrorTypeInternalServer, message+": "+err.Error())
+	}
+}
+
+// requestUserID returns the ID of the user the request is processed for.
+// The user is attached to the context by the server middleware; a missing user is a server misconfiguration.
+func requestUserID(ctx context.Context) (int64, error) {
+	userID, ok := auth.UserIDFromContext(ctx)
+	if !ok {
+		return 0, apperr.NewAppError(apperr.ErrorTypeInternalServer, "no user associated with the request")
+	}
+	return userID, nil
+}
+
+// parseDate parses a YYYYMMDD query parameter
+func parseDate(name, value string) (time.Time, error) {
+	date, err := time.Parse("20060102", value)
+	if err != nil {
+		return time.Time{}, apperr.NewAppError(apperr.ErrorTypeInvalidDate, "invalid "+name+" format: "+value+" (Use YYYYMMDD)")
+	}
+	return date, nil
+}
+
+// parseDateRange parses and validates an inclusive from/to range (YYYYMMDD).
+// The returned end is the day after "to", ready for the half-open DB range queries.
+func parseDateRange(fromStr, toStr string) (start, end time.Time, err error) {
+	if fromStr == "" || toStr == "" {
+		return time.Time{}, time.Time{}, apperr.NewAppError(apperr.ErrorTypeInvalidRange, "both from and to are required")
+	}
+
+	from, err := parseDate("from", fromStr)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	to, err := parseDate("to", toStr)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	if err := validators.ValidateDateRange(from, to); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	return from, to.AddDate(0, 0, 1), nil
+}
+
+// parseDateQuery parses either a single date or a from/to range from the query parameters
+// into a half-open [start, end) range
+func parseDateQuery(query url.Values) (start, end time.Time, err error) {
+	switch {
+	case query.Get("date") != "":
+		start, err = parseDate("date", query.Get("date"))
+		return start, start.AddDate(0, 0, 1), err
+	case query.Get("from") != "" || query.Get("to") != "":
+		return parseDateRange(query.Get("from"), query.Get("to"))
+	default:
+		return time.Time{}, time.Time{}, apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "invalid query parameters: expected date or from/to")
+	}
+}
+
+// parsePageRequest parses the limit, cursor and sort query parameters of a paginated listing.
+// A cursor is only valid with the sort order it was issued for.
+func parsePageRequest(query url.Values) (models.PageRequest, error) {
+	page := models.PageRequest{Limit: models.DefaultPageLimit}
+
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > models.MaxPageLimit {
+			return page, apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "limit must be between 1 and "+strconv.Itoa(models.MaxPageLimit))
+		}
+		page.Limit = limit
+	}
+
+	sort, err := models.ParseRecordSort(query.Get("sort"))
+	if err != nil {
+		return page, apperr.NewAppError(apperr.ErrorTypeInvalidFormat, err.Error())
+	}
+	page.Sort = sort
+
+	if cursorStr := query.Get("cursor"); cursorStr != "" {
+		cursor, err := models.DecodeHealthRecordCursor(cursorStr)
+		if err != nil {
+			return page, apperr.NewAppError(apperr.ErrorTypeInvalidFormat, err.Error())
+		}
+		if cursor.Sort != page.Sort {
+			return page, apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "cursor was issued for sort "+string(cursor.Sort))
+		}
+		page.After = cursor
+	}
+
+	return page, nil
+}
+
+// handleError processes errors and sends appropriate responses
+func handleError(w http.ResponseWriter, r *http.Request, err error) {
+	var appErr apperr.AppError
+	if errors.As(err, &appErr) {
+		clientMessage := appErr.Error()
+
+		if !config.IsDev() && appErr.Type == apperr.ErrorTypeInternalServer {
+			clientMessage = "an internal server error occurred"
+		}
+
+		statusCode := http.StatusInternalServerError
+		switch appErr.Type {
+		case apperr.ErrorTypeInvalidDate, apperr.ErrorTypeInvalidYear, apperr.ErrorTypeInvalidMonth, apperr.ErrorTypeInvalidRange, apperr.ErrorTypeInvalidFormat, apperr.ErrorTypeBadRequest:
+			statusCode = http.StatusBadRequest
+		case apperr.ErrorTypeUnauthorized:
+			statusCode = http.StatusUnauthorized
+		case apperr.ErrorTypeForbidden:
+			statusCode = http.StatusForbidden
+		case apperr.ErrorTypeNotFound:
+			statusCode = http.StatusNotFound
+		case apperr.ErrorTypeConflict:
+			statusCode = http.StatusConflict
+		case apperr.ErrorTypePreconditionFailed:
+			statusCode = http.StatusPreconditionFailed
+		case apperr.ErrorTypePreconditionRequired:
+			statusCode = http.StatusPreconditionRequired
+		case apperr.ErrorTypeUnprocessableEntity:
+			statusCode = http.StatusUnprocessableEntity
+		}
+
+		level := slog.LevelInfo
+		if statusCode >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		logging.FromContext(r.Context()).Log(r.Context(), level, "application error",
+			slog.String("type", string(appErr.Type)), slog.Int("status", statusCode), slog.String("error", appErr.Error()))
+
+		sendErrorResponse(w, r, apperr.AppError{Type: appErr.Type, Message: clientMessage, Fields: appErr.Fields}, statusCode)
+	} else {
+		logging.FromContext(r.Context()).Error("unhandled error", slog.Any("error", err))
+		message := "an unexpected error occurred"
+		if config.IsDev() {
+			message = err.Error()
+		}
+		sendErrorResponse(w, r, apperr.NewAppError(apperr.ErrorTypeInternalServer, message), http.StatusInternalServerError)
+	}
+}
+
+// sendJSONResponse sends a JSON response
+func sendJSONResponse(w http.ResponseWriter, data any, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		sendErrorResponse(w, nil, apperr.NewAppError(apperr.ErrorTypeInternalServer, "failed to encode response"), http.StatusInternalServerError)
+	}
+}
+
+// problemContentType is the media type of RFC 7807 problem details
+const problemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details error response
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail"`
+	Instance string `json:"instance,omitempty"`
+
+	// Code is the stable machine-readable code of the error, e.g. "invalid_date"
+	Code string `json:"code"`
+
+	// Errors lists the field-level problems of a validation failure
+	Errors []apperr.FieldError `json:"errors,omitempty"`
+
+	// RequestID is the X-Request-ID of the request, for finding its log lines
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// sendErrorResponse sends an error response as problem details.
+// The request path is reported as the instance when the request is known.
+func sendErrorResponse(w http.ResponseWriter, r *http.Request, err apperr.AppError, statusCode int) {
+	problem := Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(statusCode),
+		Status: statusCode,
+		Detail: err.Error(),
+		Code:   err.Type.Code(),
+		Errors: err.Fields,
+	}
+	if r != nil {
+		problem.Instance = r.URL.Path
+		problem.RequestID = logging.RequestID(r.Context())
+	}
+
+	w.Header().Set("Content-Type", problemContentType)
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(problem)
+}
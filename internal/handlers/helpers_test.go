@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nnamm/go-health-tracker/internal/auth"
+	"github.com/nnamm/go-health-tracker/internal/database"
+	"github.com/nnamm/go-health-tracker/internal/models"
+	"github.com/nnamm/go-health-tracker/testutils"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestDB returns a fresh SQLite database with the test user, closed when the test ends
+func newTestDB(t *testing.T) *database.SQLiteDB {
+	t.Helper()
+	db, cleanup := testutils.SetupSQLiteTester(t)
+	t.Cleanup(cleanup)
+	return db
+}
+
+// newTestRequest creates a request made by the test user
+func newTestRequest(method, target, body string) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	return req.WithContext(auth.WithUserID(req.Context(), testutils.TestUserID))
+}
+
+// serve runs a handler on the request and returns the recorded response
+func serve(handler http.HandlerFunc, req *http.Request) *httptest.ResponseRecorder {
+	rr := httptest.NewRecorder()
+	handler(rr, req)
+	return rr
+}
+
+// parseJSONResponse parses a JSON response body into target
+func parseJSONResponse(t *testing.T, rr *httptest.ResponseRecorder, target any) {
+	t.Helper()
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), target), "response body: %s", rr.Body.String())
+}
+
+// parseProblem parses a problem details error response
+func parseProblem(t *testing.T, rr *httptest.ResponseRecorder) Problem {
+	t.Helper()
+	require.Equal(t, problemContentType, rr.Header().Get("Content-Type"))
+	var problem Problem
+	parseJSONResponse(t, rr, &problem)
+	return problem
+}
+
+// createTestRecord stores a record of the test user
+func createTestRecord(t *testing.T, db database.DBInterface, date string, stepCount int) *models.HealthRecord {
+	t.Helper()
+	created, err := db.CreateHealthRecord(t.Context(), testutils.TestUserID, &models.HealthRecord{Date: testutils.CreateDate(date), StepCount: stepCount})
+	require.NoError(t, err)
+	return created
+}
@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/apperr"
+	"github.com/nnamm/go-health-tracker/internal/config"
+	"github.com/nnamm/go-health-tracker/internal/database"
+	"github.com/nnamm/go-health-tracker/internal/logging"
+	"github.com/nnamm/go-health-tracker/internal/models"
+)
+
+// idempotencyKeyHeader is the request header carrying the client's key of a POST request
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// idempotencyReplayedHeader marks a response that was replayed from a stored key
+const idempotencyReplayedHeader = "Idempotency-Replayed"
+
+// maxIdempotencyKeyLength limits the length of an Idempotency-Key
+const maxIdempotencyKeyLength = 255
+
+// maxIdempotentDrainSize limits how much of a body left unread by the handler is read to complete its hash (256KB).
+// The response to a request whose body is not read to the end is not stored.
+const maxIdempotentDrainSize = 256 << 10
+
+// IdempotencyHandler stores the responses of POST requests sent with an Idempotency-Key
+// and replays them on retries
+type IdempotencyHandler struct {
+	DB database.IdempotencyStore
+}
+
+// NewIdempotencyHandler creates a new IdempotencyHandler
+func NewIdempotencyHandler(db database.IdempotencyStore) *IdempotencyHandler {
+	return &IdempotencyHandler{DB: db}
+}
+
+// Idempotent is middleware that makes POST requests with an Idempotency-Key header safe to retry.
+// The first request with a key is processed and its response stored for the user and key;
+// a retry with the same payload gets the stored response, one with a different payload a 422.
+// Server errors are not stored, so the request can be retried. Other requests pass through.
+//
+// The body is hashed while the handler reads it instead of being buffered, so uploads of any size
+// can be sent with a key; the hash is stored with the response, and a retry's body is hashed
+// before the stored response is replayed.
+func (h *IdempotencyHandler) Idempotent(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(idempotencyKeyHeader)
+		if r.Method != http.MethodPost || key == "" {
+			next(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			handleError(w, r, apperr.NewAppError(apperr.ErrorTypeBadRequest, "Idempotency-Key must not be longer than 255 characters"))
+			return
+		}
+
+		userID, err := requestUserID(r.Context())
+		if err != nil {
+			handleError(w, r, err)
+			return
+		}
+
+		now := time.Now()
+		k := &models.IdempotencyKey{
+			Key:       key,
+			CreatedAt: now,
+			ExpiresAt: now.Add(time.Duration(config.IdempotencyKeyTTLHours) * time.Hour),
+		}
+
+		ctx, cancel := storeContext(r)
+		err = h.DB.ReserveIdempotencyKey(ctx, userID, k)
+		cancel()
+		if errors.Is(err, database.ErrConflict) {
+			h.replay(w, r, userID, key)
+			return
+		}
+		if err != nil {
+			handleError(w, r, databaseError("failed to reserve idempotency key", err))
+			return
+		}
+
+		body := newHashingBody(r)
+		r.Body = body
+		rec := &responseRecorder{ResponseWriter: w}
+		next(rec, r)
+
+		// The stores run even if the client has gone away, so a retry does not find the key in progress
+		ctx, cancel = storeContext(r)
+		defer cancel()
+
+		// A server error may leave the body being read, so it is checked first
+		if rec.status == 0 || rec.status >= http.StatusInternalServerError || !body.readToEnd() {
+			if err := h.DB.DeleteIdempotencyKey(ctx, userID, key); err != nil {
+				logging.FromContext(ctx).Error("failed to release idempotency key", slog.Any("error", err))
+			}
+			return
+		}
+
+		k.RequestHash = body.sum()
+		k.StatusCode = rec.status
+		k.Header = rec.header
+		k.Body = rec.body.Bytes()
+		if err := h.DB.CompleteIdempotencyKey(ctx, userID, k); err != nil {
+			logging.FromContext(ctx).Error("failed to store idempotent response", slog.Any("error", err))
+		}
+	}
+}
+
+// replay sends the stored response of the first request with a key
+// once the retry's body has been hashed and matches the first request
+func (h *IdempotencyHandler) replay(w http.ResponseWriter, r *http.Request, userID int64, key string) {
+	ctx, cancel := storeContext(r)
+	stored, err := h.DB.ReadIdempotencyKey(ctx, userID, key)
+	cancel()
+	if err != nil {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeInternalServer, "failed to read idempotency key: "+err.Error()))
+		return
+	}
+
+	switch {
+	case stored == nil:
+		// Released by a failed first request or expired since the reservation
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeConflict, "a request with this Idempotency-Key was just processed, retry the request"))
+		return
+	case !stored.Completed():
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeConflict, "a request with this Idempotency-Key is still being processed"))
+		return
+	}
+
+	// A retried upload takes as long to read as the first one
+	if err := extendDeadlines(w, time.Duration(config.ImportTimeoutSecond)*time.Second); err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	// No route accepts a larger body than an Apple Health export
+	body := newHashingBody(r)
+	if _, err := io.Copy(io.Discard, http.MaxBytesReader(w, body, maxAppleHealthUploadSize)); err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			handleError(w, r, apperr.NewAppError(apperr.ErrorTypeBadRequest, "request body too large"))
+			return
+		}
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeBadRequest, "failed to read request body: "+err.Error()))
+		return
+	}
+
+	if body.sum() != stored.RequestHash {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeUnprocessableEntity, "Idempotency-Key has already been used for a different request"))
+		return
+	}
+
+	for name, value := range stored.Header {
+		w.Header().Set(name, value)
+	}
+	w.Header().Set(idempotencyReplayedHeader, "true")
+	w.WriteHeader(stored.StatusCode)
+	w.Write(stored.Body)
+}
+
+// storeContext returns the context of the key store operations of a request.
+// It is not cancelled with the request.
+func storeContext(r *http.Request) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(r.Context()), time.Duration(config.RequestTimeoutSecond)*time.Second)
+}
+
+// hashingBody hashes a request body as it is read, identifying the request by its method, path, query and body
+type hashingBody struct {
+	io.ReadCloser
+	hash hash.Hash
+	eof  bool
+}
+
+// newHashingBody wraps the body of r, hashing its method, path and query up front
+func newHashingBody(r *http.Request) *hashingBody {
+	h := sha256.New()
+	io.WriteString(h, r.Method+" "+r.URL.RequestURI()+"\n")
+	return &hashingBody{ReadCloser: r.Body, hash: h}
+}
+
+// Read reads from the body and adds what was read to the hash
+func (b *hashingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.hash.Write(p[:n])
+	if err == io.EOF {
+		b.eof = true
+	}
+	return n, err
+}
+
+// readToEnd reads what the handler left of the body, up to maxIdempotentDrainSize,
+// and reports whether the whole body has been hashed
+func (b *hashingBody) readToEnd() bool {
+	if !b.eof {
+		io.CopyN(io.Discard, b, maxIdempotentDrainSize)
+	}
+	return b.eof
+}
+
+// sum returns the hex-encoded hash of the request
+func (b *hashingBody) sum() string {
+	return hex.EncodeToString(b.hash.Sum(nil))
+}
+
+// responseRecorder passes a response through and keeps a copy of its status, headers and body
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	header map[string]string
+	body   bytes.Buffer
+}
+
+// WriteHeader records the status and the headers set by then, except the request ID
+func (rec *responseRecorder) WriteHeader(statusCode int) {
+	if rec.status != 0 {
+		return
+	}
+	rec.status = statusCode
+	rec.header = make(map[string]string, len(rec.Header()))
+	for name := range rec.Header() {
+		// The request ID belongs to this request; a replay echoes its own
+		if name == http.CanonicalHeaderKey(logging.RequestIDHeader) {
+			continue
+		}
+		rec.header[name] = rec.Header().Get(name)
+	}
+	rec.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Unwrap returns the wrapped ResponseWriter, so http.ResponseController reaches the connection
+func (rec *responseRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
+
+// Write records the body; a write without a status sends 200 OK
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.WriteHeader(http.StatusOK)
+	}
+	rec.body.Write(b)
+	return rec.ResponseWriter.Write(b)
+}
@@ -0,0 +1,250 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/apperr"
+	"github.com/nnamm/go-health-tracker/internal/auth"
+	"github.com/nnamm/go-health-tracker/internal/models"
+	"github.com/nnamm/go-health-tracker/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newIdempotentRequest creates a POST request of the test user with an Idempotency-Key
+func newIdempotentRequest(target, key string, body io.Reader) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, target, body)
+	req.Header.Set(idempotencyKeyHeader, key)
+	return req.WithContext(auth.WithUserID(req.Context(), testutils.TestUserID))
+}
+
+// countingHandler reads the request body and responds with the given status, counting its calls
+type countingHandler struct {
+	status int
+	calls  int
+}
+
+func (h *countingHandler) serve(w http.ResponseWriter, r *http.Request) {
+	h.calls++
+	n, _ := io.Copy(io.Discard, r.Body)
+	w.Header().Set("ETag", `"1"`)
+	w.WriteHeader(h.status)
+	fmt.Fprintf(w, `{"call": %d, "bytes": %d}`, h.calls, n)
+}
+
+func TestIdempotent_Replay(t *testing.T) {
+	db := newTestDB(t)
+	records := NewHealthRecordHandler(db)
+	handler := NewIdempotencyHandler(db).Idempotent(records.CreateHealthRecord)
+	body := `{"date": "2024-01-01", "step_count": 8000}`
+
+	first := serve(handler, newIdempotentRequest("/health/records", "key-1", strings.NewReader(body)))
+	require.Equal(t, http.StatusCreated, first.Code, first.Body.String())
+	assert.Empty(t, first.Header().Get(idempotencyReplayedHeader))
+
+	// Creating the record again would conflict, so a 201 shows the write was not repeated
+	retry := serve(handler, newIdempotentRequest("/health/records", "key-1", strings.NewReader(body)))
+	require.Equal(t, http.StatusCreated, retry.Code, retry.Body.String())
+	assert.Equal(t, "true", retry.Header().Get(idempotencyReplayedHeader))
+	assert.Equal(t, first.Header().Get("ETag"), retry.Header().Get("ETag"))
+	assert.Equal(t, first.Body.String(), retry.Body.String())
+
+	tests := []struct {
+		name           string
+		target         string
+		key            string
+		body           string
+		expectedStatus int
+		errorMessage   string
+	}{
+		{
+			name:           "error - same key with a different body",
+			target:         "/health/records",
+			key:            "key-1",
+			body:           `{"date": "2024-01-01", "step_count": 9000}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+			errorMessage:   "Idempotency-Key has already been used for a different request",
+		},
+		{
+			name:           "error - same key on a different path",
+			target:         "/health/records?dry_run=true",
+			key:            "key-1",
+			body:           body,
+			expectedStatus: http.StatusUnprocessableEntity,
+			errorMessage:   "Idempotency-Key has already been used for a different request",
+		},
+		{
+			name:           "error - key too long",
+			target:         "/health/records",
+			key:            strings.Repeat("k", maxIdempotencyKeyLength+1),
+			body:           body,
+			expectedStatus: http.StatusBadRequest,
+			errorMessage:   "Idempotency-Key must not be longer than 255 characters",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := serve(handler, newIdempotentRequest(tt.target, tt.key, strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.Equal(t, tt.errorMessage, parseProblem(t, rr).Detail)
+		})
+	}
+
+	stored, err := db.ReadHealthRecord(context.Background(), testutils.TestUserID, testutils.CreateDate("2024-01-01"))
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.Equal(t, 8000, stored.StepCount)
+}
+
+func TestIdempotent_InProgress(t *testing.T) {
+	db := newTestDB(t)
+	next := &countingHandler{status: http.StatusCreated}
+	handler := NewIdempotencyHandler(db).Idempotent(next.serve)
+
+	// A key reserved by a request that has not finished yet
+	now := time.Now()
+	require.NoError(t, db.ReserveIdempotencyKey(context.Background(), testutils.TestUserID, &models.IdempotencyKey{Key: "key-1", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}))
+
+	rr := serve(handler, newIdempotentRequest("/health/records", "key-1", strings.NewReader(`{}`)))
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Equal(t, "a request with this Idempotency-Key is still being processed", parseProblem(t, rr).Detail)
+	assert.Equal(t, 0, next.calls)
+}
+
+func TestIdempotent_ResponsesNotStored(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   func() io.Reader
+		read   bool
+	}{
+		{
+			name:   "server error",
+			status: http.StatusInternalServerError,
+			body:   func() io.Reader { return strings.NewReader(`{}`) },
+			read:   true,
+		},
+		{
+			name:   "body left unread beyond the drain limit",
+			status: http.StatusBadRequest,
+			body:   func() io.Reader { return strings.NewReader(strings.Repeat("x", maxIdempotentDrainSize+1)) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t)
+			calls := 0
+			handler := NewIdempotencyHandler(db).Idempotent(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				if tt.read {
+					io.Copy(io.Discard, r.Body)
+				}
+				sendErrorResponse(w, r, apperr.NewAppError(apperr.ErrorTypeBadRequest, "rejected"), tt.status)
+			})
+
+			for range 2 {
+				rr := serve(handler, newIdempotentRequest("/health/records/import", "key-1", tt.body()))
+				assert.Equal(t, tt.status, rr.Code)
+				assert.Empty(t, rr.Header().Get(idempotencyReplayedHeader))
+			}
+
+			// Both requests were processed, and the key was released each time
+			assert.Equal(t, 2, calls)
+			stored, err := db.ReadIdempotencyKey(context.Background(), testutils.TestUserID, "key-1")
+			require.NoError(t, err)
+			assert.Nil(t, stored)
+		})
+	}
+}
+
+func TestIdempotent_UnreadBodyIsDrained(t *testing.T) {
+	db := newTestDB(t)
+	calls := 0
+	handler := NewIdempotencyHandler(db).Idempotent(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		sendJSONResponse(w, map[string]string{"status": "accepted"}, http.StatusAccepted)
+	})
+
+	for range 2 {
+		serve(handler, newIdempotentRequest("/health/records/import", "key-1", strings.NewReader(`{"small": "body"}`)))
+	}
+	assert.Equal(t, 1, calls)
+
+	// The drained body still identifies the request
+	rr := serve(handler, newIdempotentRequest("/health/records/import", "key-1", strings.NewReader(`{"other": "body"}`)))
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+}
+
+// repeatReader streams n bytes of b without holding them in memory
+type repeatReader struct {
+	b byte
+	n int64
+}
+
+func (r *repeatReader) Read(p []byte) (int, error) {
+	if r.n <= 0 {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > r.n {
+		p = p[:r.n]
+	}
+	for i := range p {
+		p[i] = r.b
+	}
+	r.n -= int64(len(p))
+	return len(p), nil
+}
+
+func TestIdempotent_LargeUpload(t *testing.T) {
+	db := newTestDB(t)
+	next := &countingHandler{status: http.StatusOK}
+	handler := NewIdempotencyHandler(db).Idempotent(next.serve)
+
+	// Bodies are hashed as they stream rather than held in memory, so large uploads can be retried
+	const size = 40 << 20
+
+	first := serve(handler, newIdempotentRequest("/health/apple-health", "key-1", &repeatReader{b: 'a', n: size}))
+	require.Equal(t, http.StatusOK, first.Code, first.Body.String())
+
+	retry := serve(handler, newIdempotentRequest("/health/apple-health", "key-1", &repeatReader{b: 'a', n: size}))
+	require.Equal(t, http.StatusOK, retry.Code, retry.Body.String())
+	assert.Equal(t, "true", retry.Header().Get(idempotencyReplayedHeader))
+	assert.Equal(t, first.Body.String(), retry.Body.String())
+	assert.Equal(t, 1, next.calls)
+
+	changed := serve(handler, newIdempotentRequest("/health/apple-health", "key-1", &repeatReader{b: 'b', n: size}))
+	assert.Equal(t, http.StatusUnprocessableEntity, changed.Code)
+	assert.Equal(t, 1, next.calls)
+}
+
+func TestIdempotent_PassThrough(t *testing.T) {
+	db := newTestDB(t)
+	next := &countingHandler{status: http.StatusCreated}
+	handler := NewIdempotencyHandler(db).Idempotent(next.serve)
+
+	// Without a key every request is processed
+	for range 2 {
+		req := newTestRequest(http.MethodPost, "/health/records", `{}`)
+		assert.Equal(t, http.StatusCreated, serve(handler, req).Code)
+	}
+
+	// Only POST requests are idempotent by key
+	for range 2 {
+		req := newTestRequest(http.MethodPut, "/health/records/20240101", `{}`)
+		req.Header.Set(idempotencyKeyHeader, "key-1")
+		serve(handler, req)
+	}
+
+	assert.Equal(t, 4, next.calls)
+}
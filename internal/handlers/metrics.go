@@ -0,0 +1,287 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/apperr"
+	"github.com/nnamm/go-health-tracker/internal/config"
+	"github.com/nnamm/go-health-tracker/internal/database"
+	"github.com/nnamm/go-health-tracker/internal/models"
+	"github.com/nnamm/go-health-tracker/internal/validators"
+)
+
+// MetricHandler handles HTTP requests for custom metric definitions and their values
+type MetricHandler struct {
+	DB        database.DBInterface
+	validator validators.HealthRecordValidator
+}
+
+// NewMetricHandler creates a new MetricHandler
+func NewMetricHandler(db database.DBInterface) *MetricHandler {
+	return &MetricHandler{
+		DB:        db,
+		validator: validators.NewHealthRecordValidator(),
+	}
+}
+
+// MetricDefinitionResult represents the response structure for metric definitions
+type MetricDefinitionResult struct {
+	Metrics []models.MetricDefinition `json:"metrics"`
+}
+
+// MetricValueResult represents the response structure for metric values
+type MetricValueResult struct {
+	Values []models.MetricValue `json:"values"`
+}
+
+// DailyMetricValueResult represents the response structure for daily metric values
+type DailyMetricValueResult struct {
+	Metric string                    `json:"metric"`
+	Days   []models.DailyMetricValue `json:"days"`
+}
+
+// CreateMetricDefinition handles the registration of a new custom metric
+func (h *MetricHandler) CreateMetricDefinition(w http.ResponseWriter, r *http.Request) {
+	// set a timeout for the request context
+	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(config.RequestTimeoutSecond)*time.Second)
+	defer cancel()
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	r = r.WithContext(ctx)
+
+	body, err := readRequestBody(ctx, w, r)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	var def models.MetricDefinition
+	if err := json.Unmarshal(body, &def); err != nil {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeInvalidFormat, err.Error()))
+		return
+	}
+
+	if err := h.validator.ValidateMetricDefinition(&def); err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	existing, err := h.DB.ReadMetricDefinition(ctx, userID, def.Name)
+	if err != nil {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeInternalServer, "failed to check existing metric: "+err.Error()))
+		return
+	}
+	if existing != nil {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeConflict, "metric already exists: "+def.Name))
+		return
+	}
+
+	created, err := h.DB.CreateMetricDefinition(ctx, userID, &def)
+	if err != nil {
+		handleError(w, r, databaseError("failed to create metric definition", err))
+		return
+	}
+
+	sendJSONResponse(w, MetricDefinitionResult{Metrics: []models.MetricDefinition{*created}}, http.StatusCreated)
+}
+
+// GetMetricDefinitions retrieves all custom metric definitions, or a single one by name
+func (h *MetricHandler) GetMetricDefinitions(w http.ResponseWriter, r *http.Request) {
+	// set a timeout for the request context
+	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(config.RequestTimeoutSecond)*time.Second)
+	defer cancel()
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	if name := r.URL.Query().Get("name"); name != "" {
+		def, err := h.readDefinition(ctx, userID, name)
+		if err != nil {
+			handleError(w, r, err)
+			return
+		}
+		sendJSONResponse(w, MetricDefinitionResult{Metrics: []models.MetricDefinition{*def}}, http.StatusOK)
+		return
+	}
+
+	defs, err := h.DB.ReadMetricDefinitions(ctx, userID)
+	if err != nil {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeInternalServer, "failed to read metric definitions: "+err.Error()))
+		return
+	}
+
+	sendJSONResponse(w, MetricDefinitionResult{Metrics: defs}, http.StatusOK)
+}
+
+// DeleteMetricDefinition handles the deletion of a custom metric and all of its values
+func (h *MetricHandler) DeleteMetricDefinition(w http.ResponseWriter, r *http.Request) {
+	// Set a timeout for the request context
+	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(config.RequestTimeoutSecond)*time.Second)
+	defer cancel()
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	def, err := h.readDefinition(ctx, userID, r.URL.Query().Get("name"))
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	if err := h.DB.DeleteMetricDefinition(ctx, userID, def.ID); err != nil {
+		handleError(w, r, databaseError("failed to delete metric definition", err))
+		return
+	}
+
+	// Send success response
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Metric deleted successfully"})
+}
+
+// CreateMetricValue handles the recording of a value against a custom metric
+func (h *MetricHandler) CreateMetricValue(w http.ResponseWriter, r *http.Request) {
+	// set a timeout for the request context
+	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(config.RequestTimeoutSecond)*time.Second)
+	defer cancel()
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	r = r.WithContext(ctx)
+
+	body, err := readRequestBody(ctx, w, r)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	var mv models.MetricValue
+	if err := json.Unmarshal(body, &mv); err != nil {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeInvalidFormat, err.Error()))
+		return
+	}
+
+	def, err := h.readDefinition(ctx, userID, mv.Metric)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	if err := h.validator.ValidateMetricValue(def, &mv); err != nil {
+		handleError(w, r, err)
+		return
+	}
+	mv.MetricID = def.ID
+	mv.SetDerivedFields()
+
+	created, err := h.DB.CreateMetricValue(ctx, userID, &mv)
+	if err != nil {
+		handleError(w, r, databaseError("failed to create metric value", err))
+		return
+	}
+
+	sendJSONResponse(w, MetricValueResult{Values: []models.MetricValue{*created}}, http.StatusCreated)
+}
+
+// GetMetricValues retrieves the values of a metric for the specified date or date range (from, to)
+func (h *MetricHandler) GetMetricValues(w http.ResponseWriter, r *http.Request) {
+	// set a timeout for the request context
+	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(config.RequestTimeoutSecond)*time.Second)
+	defer cancel()
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	_, values, err := h.readValues(ctx, userID, r)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	sendJSONResponse(w, MetricValueResult{Values: values}, http.StatusOK)
+}
+
+// GetDailyMetricValues retrieves one value per day, combined by the metric's aggregation rule
+func (h *MetricHandler) GetDailyMetricValues(w http.ResponseWriter, r *http.Request) {
+	// set a timeout for the request context
+	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(config.RequestTimeoutSecond)*time.Second)
+	defer cancel()
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	def, values, err := h.readValues(ctx, userID, r)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	sendJSONResponse(w, DailyMetricValueResult{Metric: def.Name, Days: models.AggregateMetricValues(def, values)}, http.StatusOK)
+}
+
+// readDefinition reads a metric definition by name, returning a NotFound error when it is not registered
+func (h *MetricHandler) readDefinition(ctx context.Context, userID int64, name string) (*models.MetricDefinition, error) {
+	if name == "" {
+		return nil, apperr.NewAppError(apperr.ErrorTypeBadRequest, "metric name is required")
+	}
+
+	def, err := h.DB.ReadMetricDefinition(ctx, userID, name)
+	if err != nil {
+		return nil, apperr.NewAppError(apperr.ErrorTypeInternalServer, "failed to read metric definition: "+err.Error())
+	}
+	if def == nil {
+		return nil, apperr.NewAppError(apperr.ErrorTypeNotFound, "metric not found: "+name)
+	}
+
+	return def, nil
+}
+
+// readValues reads the values of the metric named by the metric query parameter,
+// selected by the date or from/to query parameters
+func (h *MetricHandler) readValues(ctx context.Context, userID int64, r *http.Request) (*models.MetricDefinition, []models.MetricValue, error) {
+	query := r.URL.Query()
+
+	start, end, err := parseDateQuery(query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	def, err := h.readDefinition(ctx, userID, query.Get("metric"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	values, err := h.DB.ReadMetricValuesByRange(ctx, userID, def.ID, start, end)
+	if err != nil {
+		return nil, nil, apperr.NewAppError(apperr.ErrorTypeInternalServer, "failed to read metric values: "+err.Error())
+	}
+
+	for i := range values {
+		values[i].Metric = def.Name
+	}
+
+	return def, values, nil
+}
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/apperr"
+	"github.com/nnamm/go-health-tracker/internal/config"
+	"github.com/nnamm/go-health-tracker/internal/database"
+	"github.com/nnamm/go-health-tracker/internal/models"
+	"github.com/nnamm/go-health-tracker/internal/validators"
+)
+
+// SleepHandler handles HTTP requests for sleep sessions
+type SleepHandler struct {
+	DB        database.DBInterface
+	validator validators.SleepSessionValidator
+}
+
+// NewSleepHandler creates a new SleepHandler
+func NewSleepHandler(db database.DBInterface) *SleepHandler {
+	return &SleepHandler{
+		DB:        db,
+		validator: validators.NewSleepSessionValidator(),
+	}
+}
+
+// SleepSessionResult represents the response structure for sleep sessions
+type SleepSessionResult struct {
+	Sessions []models.SleepSession `json:"sessions"`
+}
+
+// CreateSleepSession handles the creation of a new sleep session
+func (h *SleepHandler) CreateSleepSession(w http.ResponseWriter, r *http.Request) {
+	// set a timeout for the request context
+	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(config.RequestTimeoutSecond)*time.Second)
+	defer cancel()
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	r = r.WithContext(ctx)
+
+	s, err := h.decodeSleepSession(ctx, w, r)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	created, err := h.DB.CreateSleepSession(ctx, userID, s)
+	if err != nil {
+		handleError(w, r, sleepSessionWriteError("failed to create sleep session", err))
+		return
+	}
+
+	sendJSONResponse(w, SleepSessionResult{Sessions: []models.SleepSession{*created}}, http.StatusCreated)
+}
+
+// GetSleepSessions retrieves sleep sessions for the specified wake date or date range (from, to)
+func (h *SleepHandler) GetSleepSessions(w http.ResponseWriter, r *http.Request) {
+	// set a timeout for the request context
+	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(config.RequestTimeoutSecond)*time.Second)
+	defer cancel()
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	start, end, err := parseDateQuery(r.URL.Query())
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	sessions, err := h.DB.ReadSleepSessionsByRange(ctx, userID, start, end)
+	if err != nil {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeInternalServer, "failed to read sleep sessions: "+err.Error()))
+		return
+	}
+
+	sendJSONResponse(w, SleepSessionResult{Sessions: sessions}, http.StatusOK)
+}
+
+// UpdateSleepSession handles the update of an existing sleep session identified by its id
+func (h *SleepHandler) UpdateSleepSession(w http.ResponseWriter, r *http.Request) {
+	// Set a timeout for the request context
+	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(config.RequestTimeoutSecond)*time.Second)
+	defer cancel()
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	r = r.WithContext(ctx)
+
+	s, err := h.decodeSleepSession(ctx, w, r)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	if s.ID == 0 {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeBadRequest, "id is required"))
+		return
+	}
+
+	if err := h.DB.UpdateSleepSession(ctx, userID, s); err != nil {
+		handleError(w, r, sleepSessionWriteError("failed to update sleep session", err))
+		return
+	}
+
+	updated, err := h.DB.ReadSleepSession(ctx, userID, s.ID)
+	if err != nil {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeInternalServer, "failed to read updated sleep session: "+err.Error()))
+		return
+	}
+
+	sendJSONResponse(w, SleepSessionResult{Sessions: []models.SleepSession{*updated}}, http.StatusOK)
+}
+
+// DeleteSleepSession handles the deletion of a sleep session
+func (h *SleepHandler) DeleteSleepSession(w http.ResponseWriter, r *http.Request) {
+	// Set a timeout for the request context
+	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(config.RequestTimeoutSecond)*time.Second)
+	defer cancel()
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	idStr := r.URL.Query().Get("id")
+	if idStr == "" {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeBadRequest, "id parameter is required"))
+		return
+	}
+
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "invalid id: "+idStr))
+		return
+	}
+
+	if err := h.DB.DeleteSleepSession(ctx, userID, id); err != nil {
+		handleError(w, r, databaseError("failed to delete sleep session", err))
+		return
+	}
+
+	// Send success response
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Sleep session deleted successfully"})
+}
+
+// decodeSleepSession reads, derives and validates a sleep session from the request body.
+// Overlaps with stored sessions are checked by the store when the session is written.
+func (h *SleepHandler) decodeSleepSession(ctx context.Context, w http.ResponseWriter, r *http.Request) (*models.SleepSession, error) {
+	body, err := readRequestBody(ctx, w, r)
+	if err != nil {
+		return nil, err
+	}
+
+	var s models.SleepSession
+	if err := json.Unmarshal(body, &s); err != nil {
+		return nil, apperr.NewAppError(apperr.ErrorTypeInvalidFormat, err.Error())
+	}
+
+	if err := h.validator.Validate(&s); err != nil {
+		return nil, err
+	}
+	s.SetDerivedFields()
+
+	return &s, nil
+}
+
+// sleepSessionWriteError converts an error of a sleep session write into an AppError.
+// The store reports an overlap with another session as a conflict.
+func sleepSessionWriteError(message string, err error) error {
+	if errors.Is(err, database.ErrConflict) {
+		return apperr.NewAppError(apperr.ErrorTypeConflict, message+": sleep session overlaps an existing session")
+	}
+	return databaseError(message, err)
+}
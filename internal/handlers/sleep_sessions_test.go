@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSleepHandler_Overlap(t *testing.T) {
+	handler := NewSleepHandler(newTestDB(t))
+
+	rr := serve(handler.CreateSleepSession, newTestRequest(http.MethodPost, "/health/sleep", `{"bed_time": "2024-05-01T23:00:00Z", "wake_time": "2024-05-02T07:00:00Z"}`))
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+
+	var night SleepSessionResult
+	parseJSONResponse(t, rr, &night)
+	require.Len(t, night.Sessions, 1)
+
+	// A nap that only touches the night sleep is accepted
+	rr = serve(handler.CreateSleepSession, newTestRequest(http.MethodPost, "/health/sleep", `{"bed_time": "2024-05-02T07:00:00Z", "wake_time": "2024-05-02T08:00:00Z"}`))
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+
+	rr = serve(handler.CreateSleepSession, newTestRequest(http.MethodPost, "/health/sleep", `{"bed_time": "2024-05-02T06:00:00Z", "wake_time": "2024-05-02T09:00:00Z"}`))
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Equal(t, "failed to create sleep session: sleep session overlaps an existing session", parseProblem(t, rr).Detail)
+
+	// Moving the night sleep over the nap is rejected as well
+	rr = serve(handler.UpdateSleepSession, newTestRequest(http.MethodPut, "/health/sleep", `{"id": `+strconv.FormatInt(night.Sessions[0].ID, 10)+`, "bed_time": "2024-05-01T23:00:00Z", "wake_time": "2024-05-02T07:30:00Z"}`))
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Equal(t, "failed to update sleep session: sleep session overlaps an existing session", parseProblem(t, rr).Detail)
+}
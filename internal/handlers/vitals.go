@@ -0,0 +1,244 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/apperr"
+	"github.com/nnamm/go-health-tracker/internal/config"
+	"github.com/nnamm/go-health-tracker/internal/database"
+	"github.com/nnamm/go-health-tracker/internal/models"
+	"github.com/nnamm/go-health-tracker/internal/validators"
+)
+
+// VitalHandler handles HTTP requests for blood pressure and heart rate readings
+type VitalHandler struct {
+	DB        database.DBInterface
+	validator validators.VitalValidator
+}
+
+// NewVitalHandler creates a new VitalHandler
+func NewVitalHandler(db database.DBInterface) *VitalHandler {
+	return &VitalHandler{
+		DB:        db,
+		validator: validators.NewVitalValidator(),
+	}
+}
+
+// BloodPressureResult represents the response structure for blood pressure readings
+type BloodPressureResult struct {
+	Readings []models.BloodPressureReading `json:"readings"`
+}
+
+// DailyBloodPressureResult represents the response structure for daily blood pressure summaries
+type DailyBloodPressureResult struct {
+	Days []models.DailyBloodPressure `json:"days"`
+}
+
+// HeartRateResult represents the response structure for heart rate readings
+type HeartRateResult struct {
+	Readings []models.HeartRateReading `json:"readings"`
+}
+
+// DailyHeartRateResult represents the response structure for daily heart rate summaries
+type DailyHeartRateResult struct {
+	Days []models.DailyHeartRate `json:"days"`
+}
+
+// CreateBloodPressureReading handles the creation of a new blood pressure reading
+func (h *VitalHandler) CreateBloodPressureReading(w http.ResponseWriter, r *http.Request) {
+	// set a timeout for the request context
+	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(config.RequestTimeoutSecond)*time.Second)
+	defer cancel()
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	r = r.WithContext(ctx)
+
+	body, err := readRequestBody(ctx, w, r)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	var bp models.BloodPressureReading
+	if err := json.Unmarshal(body, &bp); err != nil {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeInvalidFormat, err.Error()))
+		return
+	}
+
+	if err := h.validator.ValidateBloodPressure(&bp); err != nil {
+		handleError(w, r, err)
+		return
+	}
+	bp.SetDerivedFields()
+
+	created, err := h.DB.CreateBloodPressureReading(ctx, userID, &bp)
+	if err != nil {
+		handleError(w, r, databaseError("failed to create blood pressure reading", err))
+		return
+	}
+
+	sendJSONResponse(w, BloodPressureResult{Readings: []models.BloodPressureReading{*created}}, http.StatusCreated)
+}
+
+// GetBloodPressureReadings retrieves blood pressure readings for the specified date or date range (from, to)
+func (h *VitalHandler) GetBloodPressureReadings(w http.ResponseWriter, r *http.Request) {
+	// set a timeout for the request context
+	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(config.RequestTimeoutSecond)*time.Second)
+	defer cancel()
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	readings, err := h.readBloodPressure(ctx, userID, r)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	sendJSONResponse(w, BloodPressureResult{Readings: readings}, http.StatusOK)
+}
+
+// GetDailyBloodPressure retrieves the daily min, max and average blood pressure
+func (h *VitalHandler) GetDailyBloodPressure(w http.ResponseWriter, r *http.Request) {
+	// set a timeout for the request context
+	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(config.RequestTimeoutSecond)*time.Second)
+	defer cancel()
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	readings, err := h.readBloodPressure(ctx, userID, r)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	sendJSONResponse(w, DailyBloodPressureResult{Days: models.SummarizeBloodPressure(readings)}, http.StatusOK)
+}
+
+// CreateHeartRateReading handles the creation of a new heart rate reading
+func (h *VitalHandler) CreateHeartRateReading(w http.ResponseWriter, r *http.Request) {
+	// set a timeout for the request context
+	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(config.RequestTimeoutSecond)*time.Second)
+	defer cancel()
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	r = r.WithContext(ctx)
+
+	body, err := readRequestBody(ctx, w, r)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	var hr models.HeartRateReading
+	if err := json.Unmarshal(body, &hr); err != nil {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeInvalidFormat, err.Error()))
+		return
+	}
+
+	if err := h.validator.ValidateHeartRate(&hr); err != nil {
+		handleError(w, r, err)
+		return
+	}
+	hr.SetDerivedFields()
+
+	created, err := h.DB.CreateHeartRateReading(ctx, userID, &hr)
+	if err != nil {
+		handleError(w, r, databaseError("failed to create heart rate reading", err))
+		return
+	}
+
+	sendJSONResponse(w, HeartRateResult{Readings: []models.HeartRateReading{*created}}, http.StatusCreated)
+}
+
+// GetHeartRateReadings retrieves heart rate readings for the specified date or date range (from, to)
+func (h *VitalHandler) GetHeartRateReadings(w http.ResponseWriter, r *http.Request) {
+	// set a timeout for the request context
+	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(config.RequestTimeoutSecond)*time.Second)
+	defer cancel()
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	readings, err := h.readHeartRate(ctx, userID, r)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	sendJSONResponse(w, HeartRateResult{Readings: readings}, http.StatusOK)
+}
+
+// GetDailyHeartRate retrieves the daily min, max and average heart rate
+func (h *VitalHandler) GetDailyHeartRate(w http.ResponseWriter, r *http.Request) {
+	// set a timeout for the request context
+	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(config.RequestTimeoutSecond)*time.Second)
+	defer cancel()
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	readings, err := h.readHeartRate(ctx, userID, r)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	sendJSONResponse(w, DailyHeartRateResult{Days: models.SummarizeHeartRate(readings)}, http.StatusOK)
+}
+
+// readBloodPressure reads the blood pressure readings selected by the date or from/to query parameters
+func (h *VitalHandler) readBloodPressure(ctx context.Context, userID int64, r *http.Request) ([]models.BloodPressureReading, error) {
+	start, end, err := parseDateQuery(r.URL.Query())
+	if err != nil {
+		return nil, err
+	}
+
+	readings, err := h.DB.ReadBloodPressureReadingsByRange(ctx, userID, start, end)
+	if err != nil {
+		return nil, apperr.NewAppError(apperr.ErrorTypeInternalServer, "failed to read blood pressure readings: "+err.Error())
+	}
+
+	return readings, nil
+}
+
+// readHeartRate reads the heart rate readings selected by the date or from/to query parameters
+func (h *VitalHandler) readHeartRate(ctx context.Context, userID int64, r *http.Request) ([]models.HeartRateReading, error) {
+	start, end, err := parseDateQuery(r.URL.Query())
+	if err != nil {
+		return nil, err
+	}
+
+	readings, err := h.DB.ReadHeartRateReadingsByRange(ctx, userID, start, end)
+	if err != nil {
+		return nil, apperr.NewAppError(apperr.ErrorTypeInternalServer, "failed to read heart rate readings: "+err.Error())
+	}
+
+	return readings, nil
+}
@@ -0,0 +1,280 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/apperr"
+	"github.com/nnamm/go-health-tracker/internal/config"
+	"github.com/nnamm/go-health-tracker/internal/database"
+	"github.com/nnamm/go-health-tracker/internal/models"
+	"github.com/nnamm/go-health-tracker/internal/validators"
+)
+
+// WeightHandler handles HTTP requests for weight readings and the height profile
+type WeightHandler struct {
+	DB        database.DBInterface
+	validator validators.WeightValidator
+}
+
+// NewWeightHandler creates a new WeightHandler
+func NewWeightHandler(db database.DBInterface) *WeightHandler {
+	return &WeightHandler{
+		DB:        db,
+		validator: validators.NewWeightValidator(),
+	}
+}
+
+// WeightReadingResult represents the response structure for weight readings
+type WeightReadingResult struct {
+	Readings []models.WeightReading `json:"readings"`
+}
+
+// DailyWeightResult represents the response structure for daily weight values
+type DailyWeightResult struct {
+	Days []models.DailyWeight `json:"days"`
+}
+
+// CreateWeightReading handles the creation of a new weight reading
+func (h *WeightHandler) CreateWeightReading(w http.ResponseWriter, r *http.Request) {
+	// set a timeout for the request context
+	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(config.RequestTimeoutSecond)*time.Second)
+	defer cancel()
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	r = r.WithContext(ctx)
+
+	body, err := readRequestBody(ctx, w, r)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	var wr models.WeightReading
+	if err := json.Unmarshal(body, &wr); err != nil {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeInvalidFormat, err.Error()))
+		return
+	}
+
+	if err := h.validator.Validate(&wr); err != nil {
+		handleError(w, r, err)
+		return
+	}
+	wr.SetDerivedFields()
+
+	created, err := h.DB.CreateWeightReading(ctx, userID, &wr)
+	if err != nil {
+		handleError(w, r, databaseError("failed to create weight reading", err))
+		return
+	}
+
+	heightCm, err := h.readHeightCm(ctx, userID)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+	setBMI(created, heightCm)
+
+	sendJSONResponse(w, WeightReadingResult{Readings: []models.WeightReading{*created}}, http.StatusCreated)
+}
+
+// GetWeightReadings retrieves the individual weight readings for the specified date or date range (from, to)
+func (h *WeightHandler) GetWeightReadings(w http.ResponseWriter, r *http.Request) {
+	// set a timeout for the request context
+	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(config.RequestTimeoutSecond)*time.Second)
+	defer cancel()
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	readings, heightCm, err := h.readReadings(ctx, userID, r)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	for i := range readings {
+		setBMI(&readings[i], heightCm)
+	}
+
+	sendJSONResponse(w, WeightReadingResult{Readings: readings}, http.StatusOK)
+}
+
+// GetDailyWeights retrieves one value per day, combining several readings by the aggregate parameter (latest or average)
+func (h *WeightHandler) GetDailyWeights(w http.ResponseWriter, r *http.Request) {
+	// set a timeout for the request context
+	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(config.RequestTimeoutSecond)*time.Second)
+	defer cancel()
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	mode, err := models.ParseWeightAggregation(r.URL.Query().Get("aggregate"))
+	if err != nil {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeInvalidFormat, err.Error()))
+		return
+	}
+
+	readings, heightCm, err := h.readReadings(ctx, userID, r)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	sendJSONResponse(w, DailyWeightResult{Days: models.AggregateDailyWeights(readings, mode, heightCm)}, http.StatusOK)
+}
+
+// DeleteWeightReading handles the deletion of a weight reading
+func (h *WeightHandler) DeleteWeightReading(w http.ResponseWriter, r *http.Request) {
+	// Set a timeout for the request context
+	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(config.RequestTimeoutSecond)*time.Second)
+	defer cancel()
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	idStr := r.URL.Query().Get("id")
+	if idStr == "" {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeBadRequest, "id parameter is required"))
+		return
+	}
+
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "invalid id: "+idStr))
+		return
+	}
+
+	if err := h.DB.DeleteWeightReading(ctx, userID, id); err != nil {
+		handleError(w, r, databaseError("failed to delete weight reading", err))
+		return
+	}
+
+	// Send success response
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Weight reading deleted successfully"})
+}
+
+// GetHeightProfile retrieves the stored height profile
+func (h *WeightHandler) GetHeightProfile(w http.ResponseWriter, r *http.Request) {
+	// set a timeout for the request context
+	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(config.RequestTimeoutSecond)*time.Second)
+	defer cancel()
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	hp, err := h.DB.ReadHeightProfile(ctx, userID)
+	if err != nil {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeInternalServer, "failed to read height profile: "+err.Error()))
+		return
+	}
+	if hp == nil {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeNotFound, "height profile not set"))
+		return
+	}
+
+	sendJSONResponse(w, hp, http.StatusOK)
+}
+
+// PutHeightProfile creates or replaces the height profile
+func (h *WeightHandler) PutHeightProfile(w http.ResponseWriter, r *http.Request) {
+	// Set a timeout for the request context
+	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(config.RequestTimeoutSecond)*time.Second)
+	defer cancel()
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	r = r.WithContext(ctx)
+
+	body, err := readRequestBody(ctx, w, r)
+	if err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	var hp models.HeightProfile
+	if err := json.Unmarshal(body, &hp); err != nil {
+		handleError(w, r, apperr.NewAppError(apperr.ErrorTypeInvalidFormat, err.Error()))
+		return
+	}
+
+	if err := h.validator.ValidateHeightProfile(&hp); err != nil {
+		handleError(w, r, err)
+		return
+	}
+
+	stored, err := h.DB.UpsertHeightProfile(ctx, userID, &hp)
+	if err != nil {
+		handleError(w, r, databaseError("failed to store height profile", err))
+		return
+	}
+
+	sendJSONResponse(w, stored, http.StatusOK)
+}
+
+// readReadings reads the readings selected by the date or from/to query parameters,
+// along with the stored height (zero when no height profile is set)
+func (h *WeightHandler) readReadings(ctx context.Context, userID int64, r *http.Request) ([]models.WeightReading, float64, error) {
+	start, end, err := parseDateQuery(r.URL.Query())
+	if err != nil {
+		return nil, 0, err
+	}
+
+	readings, err := h.DB.ReadWeightReadingsByRange(ctx, userID, start, end)
+	if err != nil {
+		return nil, 0, apperr.NewAppError(apperr.ErrorTypeInternalServer, "failed to read weight readings: "+err.Error())
+	}
+
+	heightCm, err := h.readHeightCm(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return readings, heightCm, nil
+}
+
+// readHeightCm returns the stored height, or zero when no height profile is set
+func (h *WeightHandler) readHeightCm(ctx context.Context, userID int64) (float64, error) {
+	hp, err := h.DB.ReadHeightProfile(ctx, userID)
+	if err != nil {
+		return 0, apperr.NewAppError(apperr.ErrorTypeInternalServer, "failed to read height profile: "+err.Error())
+	}
+	if hp == nil {
+		return 0, nil
+	}
+	return hp.HeightCm, nil
+}
+
+// setBMI derives the reading's BMI when a height is known
+func setBMI(wr *models.WeightReading, heightCm float64) {
+	if heightCm <= 0 {
+		return
+	}
+	bmi := models.CalculateBMI(wr.WeightKg, heightCm)
+	wr.BMI = &bmi
+}
@@ -0,0 +1,91 @@
+package importer
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/models"
+)
+
+// appleHealthStepCount is the record type of step samples in an Apple Health export
+const appleHealthStepCount = "HKQuantityTypeIdentifierStepCount"
+
+// appleHealthTimeLayout is the layout of the sample timestamps, which carry the UTC offset of the device
+const appleHealthTimeLayout = "2006-01-02 15:04:05 -0700"
+
+// ReadAppleHealthSteps stream-parses an Apple Health export.xml and returns the daily step counts ordered by date.
+// Samples count towards the local day they started on, in the time zone the device was in.
+// An iPhone and an Apple Watch both record the same walk, so the samples are summed per day and source,
+// and the source with the most steps gives the day's count.
+func ReadAppleHealthSteps(r io.Reader) ([]models.HealthRecord, error) {
+	dec := xml.NewDecoder(r)
+
+	// date -> source -> steps
+	daily := make(map[string]map[string]float64)
+
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse apple health export: %w", err)
+		}
+
+		el, ok := tok.(xml.StartElement)
+		if !ok || el.Name.Local != "Record" {
+			continue
+		}
+
+		var typ, source, start, value string
+		for _, attr := range el.Attr {
+			switch attr.Name.Local {
+			case "type":
+				typ = attr.Value
+			case "sourceName":
+				source = attr.Value
+			case "startDate":
+				start = attr.Value
+			case "value":
+				value = attr.Value
+			}
+		}
+		if typ != appleHealthStepCount {
+			continue
+		}
+
+		startedAt, err := time.Parse(appleHealthTimeLayout, start)
+		if err != nil {
+			return nil, fmt.Errorf("invalid step sample startDate: %q", start)
+		}
+		steps, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid step sample value: %q", value)
+		}
+
+		date := startedAt.Format("2006-01-02")
+		if daily[date] == nil {
+			daily[date] = make(map[string]float64)
+		}
+		daily[date][source] += steps
+	}
+
+	records := make([]models.HealthRecord, 0, len(daily))
+	for date, sources := range daily {
+		var best float64
+		for _, steps := range sources {
+			best = math.Max(best, steps)
+		}
+
+		d, _ := time.Parse("2006-01-02", date)
+		records = append(records, models.HealthRecord{Date: d, StepCount: int(math.Round(best))})
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Date.Before(records[j].Date) })
+
+	return records, nil
+}
@@ -0,0 +1,62 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const appleHealthExport = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE HealthData [
+<!ELEMENT HealthData (ExportDate,Me,(Record|Workout)*)>
+]>
+<HealthData locale="ja_JP">
+ <ExportDate value="2024-03-03 09:00:00 +0900"/>
+ <Me HKCharacteristicTypeIdentifierDateOfBirth="1990-01-01"/>
+ <Record type="HKQuantityTypeIdentifierStepCount" sourceName="iPhone" unit="count" startDate="2024-03-01 08:00:00 +0900" endDate="2024-03-01 08:10:00 +0900" value="1200"/>
+ <Record type="HKQuantityTypeIdentifierStepCount" sourceName="iPhone" unit="count" startDate="2024-03-01 23:50:00 +0900" endDate="2024-03-02 00:05:00 +0900" value="300"/>
+ <Record type="HKQuantityTypeIdentifierStepCount" sourceName="Apple Watch" unit="count" startDate="2024-03-01 08:00:00 +0900" endDate="2024-03-01 08:10:00 +0900" value="1400">
+  <MetadataEntry key="HKMetadataKeySyncIdentifier" value="abc"/>
+ </Record>
+ <Record type="HKQuantityTypeIdentifierHeartRate" sourceName="Apple Watch" unit="count/min" startDate="2024-03-01 08:00:00 +0900" endDate="2024-03-01 08:00:00 +0900" value="72"/>
+ <Record type="HKQuantityTypeIdentifierStepCount" sourceName="iPhone" unit="count" startDate="2024-03-02 00:30:00 +0900" endDate="2024-03-02 00:40:00 +0900" value="500"/>
+ <Record type="HKQuantityTypeIdentifierStepCount" sourceName="iPhone" unit="count" startDate="2024-03-01 23:30:00 -0800" endDate="2024-03-01 23:40:00 -0800" value="250"/>
+ <Workout workoutActivityType="HKWorkoutActivityTypeWalking" startDate="2024-03-01 08:00:00 +0900"/>
+</HealthData>`
+
+func TestReadAppleHealthSteps(t *testing.T) {
+	records, err := ReadAppleHealthSteps(strings.NewReader(appleHealthExport))
+	require.NoError(t, err)
+
+	date := func(s string) time.Time {
+		d, _ := time.Parse("2006-01-02", s)
+		return d
+	}
+	assert.Equal(t, []models.HealthRecord{
+		// iPhone 1200+300+250 (the last sample was in UTC-8, still 2024-03-01 locally) beats Apple Watch 1400
+		{Date: date("2024-03-01"), StepCount: 1750},
+		{Date: date("2024-03-02"), StepCount: 500},
+	}, records)
+}
+
+func TestReadAppleHealthSteps_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "malformed xml", input: `<HealthData><Record type="HKQuantityTypeIdentifierStepCount"`},
+		{name: "invalid date", input: `<HealthData><Record type="HKQuantityTypeIdentifierStepCount" sourceName="iPhone" startDate="2024-03-01" value="1"/></HealthData>`},
+		{name: "invalid value", input: `<HealthData><Record type="HKQuantityTypeIdentifierStepCount" sourceName="iPhone" startDate="2024-03-01 08:00:00 +0900" value="many"/></HealthData>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadAppleHealthSteps(strings.NewReader(tt.input))
+			assert.Error(t, err)
+		})
+	}
+}
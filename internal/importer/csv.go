@@ -0,0 +1,151 @@
+// Package importer reads health records from import files and writes the CSV export format.
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/models"
+	"github.com/nnamm/go-health-tracker/internal/validators"
+)
+
+// CSVHeader is the header row of exported CSV files and the columns required in imported ones
+var CSVHeader = []string{"date", "step_count"}
+
+// Row is a valid health record read from the given line of an import file
+type Row struct {
+	Line   int
+	Record models.HealthRecord
+}
+
+// RowError reports why the given line of an import file was rejected
+type RowError struct {
+	Line   int    `json:"line"`
+	Reason string `json:"reason"`
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Reason)
+}
+
+// CSVReader reads health records from CSV with a header row naming the date (YYYY-MM-DD) and step_count columns.
+// Other columns are ignored. Rows are read one at a time, so an import never holds the whole file.
+type CSVReader struct {
+	cr      *csv.Reader
+	columns map[string]int
+	v       validators.HealthRecordValidator
+	seen    map[string]int
+}
+
+// NewCSVReader reads the header row of r and returns a reader of the rows after it
+func NewCSVReader(r io.Reader, v validators.HealthRecordValidator) (*CSVReader, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	cr.ReuseRecord = true
+
+	header, err := cr.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, fmt.Errorf("csv is empty")
+		}
+		return nil, fmt.Errorf("read csv header: %w", err)
+	}
+	columns, err := csvColumns(header)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CSVReader{cr: cr, columns: columns, v: v, seen: make(map[string]int)}, nil
+}
+
+// Read returns the next valid row, or io.EOF after the last one.
+// A row that fails to parse or validate, or repeats an earlier date, is returned as a *RowError
+// and reading can continue; any other error means the input itself cannot be read.
+func (r *CSVReader) Read() (Row, error) {
+	fields, err := r.cr.Read()
+	if err != nil {
+		if err == io.EOF {
+			return Row{}, io.EOF
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return Row{}, &RowError{Line: parseErr.StartLine, Reason: parseErr.Err.Error()}
+		}
+		return Row{}, fmt.Errorf("read csv: %w", err)
+	}
+
+	line, _ := r.cr.FieldPos(0)
+	hr, err := parseCSVRecord(fields, r.columns)
+	if err == nil {
+		err = r.v.Validate(hr)
+	}
+	if err != nil {
+		return Row{}, &RowError{Line: line, Reason: err.Error()}
+	}
+
+	date := hr.Date.Format("2006-01-02")
+	if first, ok := r.seen[date]; ok {
+		return Row{}, &RowError{Line: line, Reason: fmt.Sprintf("duplicate date %s (first on line %d)", date, first)}
+	}
+	r.seen[date] = line
+
+	return Row{Line: line, Record: *hr}, nil
+}
+
+// WriteCSV writes health records as CSV with the CSVHeader columns
+func WriteCSV(w io.Writer, records []models.HealthRecord) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(CSVHeader); err != nil {
+		return err
+	}
+	for _, hr := range records {
+		if err := cw.Write([]string{hr.Date.Format("2006-01-02"), strconv.Itoa(hr.StepCount)}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvColumns maps the CSVHeader columns to their index in the header row
+func csvColumns(header []string) (map[string]int, error) {
+	columns := make(map[string]int, len(header))
+	// Spreadsheet applications often start the file with a byte order mark
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, name := range CSVHeader {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("csv header is missing the %s column", name)
+		}
+	}
+	return columns, nil
+}
+
+// parseCSVRecord parses the date and step count fields of a CSV row
+func parseCSVRecord(fields []string, columns map[string]int) (*models.HealthRecord, error) {
+	field := func(name string) string {
+		if i := columns[name]; i < len(fields) {
+			return strings.TrimSpace(fields[i])
+		}
+		return ""
+	}
+
+	date, err := time.Parse("2006-01-02", field("date"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid date: %q (use YYYY-MM-DD)", field("date"))
+	}
+
+	stepCount, err := strconv.Atoi(field("step_count"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid step_count: %q", field("step_count"))
+	}
+
+	return &models.HealthRecord{Date: date, StepCount: stepCount}, nil
+}
@@ -0,0 +1,110 @@
+package importer
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/models"
+	"github.com/nnamm/go-health-tracker/internal/validators"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readCSV reads every row of the input, collecting the rejected ones
+func readCSV(r io.Reader) ([]Row, []RowError, error) {
+	cr, err := NewCSVReader(r, validators.NewHealthRecordValidator())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var rows []Row
+	var rejected []RowError
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			return rows, rejected, nil
+		}
+		var rowErr *RowError
+		if errors.As(err, &rowErr) {
+			rejected = append(rejected, *rowErr)
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		rows = append(rows, row)
+	}
+}
+
+func TestCSVReader(t *testing.T) {
+	input := strings.Join([]string{
+		"\ufeffstep_count,date,note",
+		"8000,2024-01-01,first",
+		"9000,2024/01/02,",
+		"abc,2024-01-03,",
+		"200000,2024-01-04,",
+		"",
+		"5000,2024-01-06",
+		`7000,2024-01-07,"unterminated`,
+	}, "\n")
+
+	rows, rejected, err := readCSV(strings.NewReader(input))
+	require.NoError(t, err)
+
+	require.Len(t, rows, 2)
+	assert.Equal(t, Row{Line: 2, Record: models.HealthRecord{Date: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), StepCount: 8000}}, rows[0])
+	assert.Equal(t, 7, rows[1].Line)
+
+	require.Len(t, rejected, 4)
+	assert.Equal(t, RowError{Line: 3, Reason: `invalid date: "2024/01/02" (use YYYY-MM-DD)`}, rejected[0])
+	assert.Equal(t, RowError{Line: 4, Reason: `invalid step_count: "abc"`}, rejected[1])
+	assert.Equal(t, RowError{Line: 5, Reason: "step count is unrealistically high"}, rejected[2])
+	assert.Equal(t, 8, rejected[3].Line)
+}
+
+func TestCSVReader_DuplicateDate(t *testing.T) {
+	input := "date,step_count\n2024-01-01,8000\n2024-01-01,6000\n"
+
+	rows, rejected, err := readCSV(strings.NewReader(input))
+	require.NoError(t, err)
+	assert.Len(t, rows, 1)
+	assert.Equal(t, []RowError{{Line: 3, Reason: "duplicate date 2024-01-01 (first on line 2)"}}, rejected)
+}
+
+func TestCSVReader_InvalidHeader(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "empty", input: ""},
+		{name: "missing column", input: "date,steps\n2024-01-01,8000\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := readCSV(strings.NewReader(tt.input))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestWriteCSV_RoundTrip(t *testing.T) {
+	records := []models.HealthRecord{
+		{Date: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), StepCount: 8000},
+		{Date: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), StepCount: 0},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(&buf, records))
+	assert.Equal(t, "date,step_count\n2024-01-01,8000\n2024-01-02,0\n", buf.String())
+
+	rows, rejected, err := readCSV(&buf)
+	require.NoError(t, err)
+	assert.Empty(t, rejected)
+	require.Len(t, rows, 2)
+	assert.Equal(t, records[1].Date, rows[1].Record.Date)
+}
@@ -0,0 +1,60 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/models"
+)
+
+// fitbitTimeLayout is the layout of the timestamps in a Fitbit data export, which are in UTC
+const fitbitTimeLayout = "01/02/06 15:04:05"
+
+// Fitbit imports a steps-YYYY-MM-DD.json file of a Fitbit data export, which holds minute-by-minute step counts.
+// The samples are summed per day in Location.
+type Fitbit struct {
+	Location *time.Location
+}
+
+// fitbitSample is a minute step count of a Fitbit data export
+type fitbitSample struct {
+	DateTime string `json:"dateTime"`
+	Value    string `json:"value"`
+}
+
+// Import implements the Importer interface
+func (f Fitbit) Import(r io.Reader) ([]models.HealthRecord, error) {
+	loc := f.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	dec := json.NewDecoder(r)
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+		return nil, fmt.Errorf("parse fitbit json: expected an array of samples")
+	}
+
+	// The file holds a month of minutes, so the samples are decoded one by one
+	days := make(dailySteps)
+	for dec.More() {
+		var sample fitbitSample
+		if err := dec.Decode(&sample); err != nil {
+			return nil, fmt.Errorf("parse fitbit json: %w", err)
+		}
+
+		t, err := time.Parse(fitbitTimeLayout, sample.DateTime)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fitbit dateTime: %q", sample.DateTime)
+		}
+		steps, err := strconv.ParseFloat(sample.Value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fitbit step value: %q", sample.Value)
+		}
+		days.add(t, loc, steps)
+	}
+
+	return days.records(), nil
+}
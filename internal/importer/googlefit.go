@@ -0,0 +1,122 @@
+package importer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/models"
+)
+
+// googleFitStepDelta is the data type of step samples in Google Fit data
+const googleFitStepDelta = "com.google.step_count.delta"
+
+// GoogleFitCSV imports the "Daily activity metrics.csv" of a Google Takeout Fit export,
+// which has one row per day. Days without a step count are skipped.
+type GoogleFitCSV struct{}
+
+// Import implements the Importer interface
+func (GoogleFitCSV) Import(r io.Reader) ([]models.HealthRecord, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read google fit csv header: %w", err)
+	}
+	dateCol, stepsCol := -1, -1
+	for i, name := range header {
+		switch strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")) {
+		case "Date":
+			dateCol = i
+		case "Step count":
+			stepsCol = i
+		}
+	}
+	if dateCol < 0 || stepsCol < 0 {
+		return nil, fmt.Errorf("google fit csv needs the Date and Step count columns")
+	}
+
+	days := make(dailySteps)
+	for {
+		fields, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read google fit csv: %w", err)
+		}
+		if dateCol >= len(fields) || stepsCol >= len(fields) || strings.TrimSpace(fields[stepsCol]) == "" {
+			continue
+		}
+
+		line, _ := cr.FieldPos(0)
+		date, err := time.Parse("2006-01-02", strings.TrimSpace(fields[dateCol]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid date: %q", line, fields[dateCol])
+		}
+		steps, err := strconv.ParseFloat(strings.TrimSpace(fields[stepsCol]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid step count: %q", line, fields[stepsCol])
+		}
+		days.add(date, time.UTC, steps)
+	}
+
+	return days.records(), nil
+}
+
+// GoogleFitJSON imports a step count data source of the "All data" folder of a Google Takeout Fit export,
+// such as derived_com.google.step_count.delta_com.google.android.gms_merge_step_deltas.json.
+// The samples are summed per day in Location.
+type GoogleFitJSON struct {
+	Location *time.Location
+}
+
+// googleFitDataSource is the JSON layout of a Google Fit data source export
+type googleFitDataSource struct {
+	DataPoints []struct {
+		DataTypeName   string `json:"dataTypeName"`
+		StartTimeNanos int64  `json:"startTimeNanos"`
+		FitValue       []struct {
+			Value struct {
+				IntVal *int64   `json:"intVal"`
+				FpVal  *float64 `json:"fpVal"`
+			} `json:"value"`
+		} `json:"fitValue"`
+	} `json:"Data Points"`
+}
+
+// Import implements the Importer interface
+func (g GoogleFitJSON) Import(r io.Reader) ([]models.HealthRecord, error) {
+	var source googleFitDataSource
+	if err := json.NewDecoder(r).Decode(&source); err != nil {
+		return nil, fmt.Errorf("parse google fit json: %w", err)
+	}
+
+	loc := g.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	days := make(dailySteps)
+	for _, p := range source.DataPoints {
+		if p.DataTypeName != googleFitStepDelta || len(p.FitValue) == 0 {
+			continue
+		}
+
+		var steps float64
+		switch v := p.FitValue[0].Value; {
+		case v.IntVal != nil:
+			steps = float64(*v.IntVal)
+		case v.FpVal != nil:
+			steps = *v.FpVal
+		}
+		days.add(time.Unix(0, p.StartTimeNanos), loc, steps)
+	}
+
+	return days.records(), nil
+}
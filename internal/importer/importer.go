@@ -0,0 +1,73 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/models"
+)
+
+// Import formats accepted by New
+const (
+	FormatAppleHealth   = "apple-health"
+	FormatGoogleFitCSV  = "google-fit-csv"
+	FormatGoogleFitJSON = "google-fit-json"
+	FormatFitbit        = "fitbit"
+)
+
+// Importer reads the daily step counts of an export file.
+// The records are ordered by date, with at most one record per date.
+type Importer interface {
+	Import(r io.Reader) ([]models.HealthRecord, error)
+}
+
+// New returns the Importer for an import format.
+// loc is the time zone days are counted in for formats with UTC timestamps; nil means UTC.
+func New(format string, loc *time.Location) (Importer, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	switch format {
+	case FormatAppleHealth:
+		return AppleHealth{}, nil
+	case FormatGoogleFitCSV:
+		return GoogleFitCSV{}, nil
+	case FormatGoogleFitJSON:
+		return GoogleFitJSON{Location: loc}, nil
+	case FormatFitbit:
+		return Fitbit{Location: loc}, nil
+	default:
+		return nil, fmt.Errorf("unknown import format: %s", format)
+	}
+}
+
+// AppleHealth imports an Apple Health export.xml, see ReadAppleHealthSteps
+type AppleHealth struct{}
+
+// Import implements the Importer interface
+func (AppleHealth) Import(r io.Reader) ([]models.HealthRecord, error) {
+	return ReadAppleHealthSteps(r)
+}
+
+// dailySteps collects step counts per day and returns them as health records ordered by date
+type dailySteps map[time.Time]float64
+
+// add adds steps to the day of t in loc
+func (d dailySteps) add(t time.Time, loc *time.Location, steps float64) {
+	t = t.In(loc)
+	d[time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)] += steps
+}
+
+// records returns the daily totals ordered by date
+func (d dailySteps) records() []models.HealthRecord {
+	records := make([]models.HealthRecord, 0, len(d))
+	for date, steps := range d {
+		records = append(records, models.HealthRecord{Date: date, StepCount: int(math.Round(steps))})
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Date.Before(records[j].Date) })
+	return records
+}
@@ -0,0 +1,155 @@
+package importer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/database"
+	"github.com/nnamm/go-health-tracker/internal/models"
+	"github.com/nnamm/go-health-tracker/internal/validators"
+	"github.com/nnamm/go-health-tracker/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("time zone %s not available: %v", name, err)
+	}
+	return loc
+}
+
+func record(date string, steps int) models.HealthRecord {
+	d, _ := time.Parse("2006-01-02", date)
+	return models.HealthRecord{Date: d, StepCount: steps}
+}
+
+func importTestdata(t *testing.T, imp Importer, name string) []models.HealthRecord {
+	t.Helper()
+	f, err := os.Open(filepath.Join("testdata", name))
+	require.NoError(t, err)
+	defer f.Close()
+
+	records, err := imp.Import(f)
+	require.NoError(t, err)
+	return records
+}
+
+func TestImporters(t *testing.T) {
+	tokyo := mustLoadLocation(t, "Asia/Tokyo")
+	losAngeles := mustLoadLocation(t, "America/Los_Angeles")
+
+	tests := []struct {
+		name   string
+		format string
+		loc    *time.Location
+		file   string
+		want   []models.HealthRecord
+	}{
+		{
+			name:   "google fit daily csv skips days without steps",
+			format: FormatGoogleFitCSV,
+			file:   "Daily activity metrics.csv",
+			want:   []models.HealthRecord{record("2024-01-01", 7012), record("2024-01-03", 11230)},
+		},
+		{
+			name:   "google fit json in UTC",
+			format: FormatGoogleFitJSON,
+			file:   "derived_com.google.step_count.delta_com.google.android.gms_merge_step_deltas.json",
+			want:   []models.HealthRecord{record("2024-01-01", 2000)},
+		},
+		{
+			name:   "google fit json counts days in the time zone",
+			format: FormatGoogleFitJSON,
+			loc:    losAngeles,
+			file:   "derived_com.google.step_count.delta_com.google.android.gms_merge_step_deltas.json",
+			want:   []models.HealthRecord{record("2023-12-31", 1200), record("2024-01-01", 800)},
+		},
+		{
+			name:   "fitbit in UTC",
+			format: FormatFitbit,
+			file:   "steps-2024-01-01.json",
+			want:   []models.HealthRecord{record("2024-01-01", 65), record("2024-01-02", 112)},
+		},
+		{
+			name:   "fitbit counts days in the time zone",
+			format: FormatFitbit,
+			loc:    tokyo,
+			file:   "steps-2024-01-01.json",
+			want:   []models.HealthRecord{record("2024-01-01", 40), record("2024-01-02", 137)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			imp, err := New(tt.format, tt.loc)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, importTestdata(t, imp, tt.file))
+		})
+	}
+}
+
+func TestNew_UnknownFormat(t *testing.T) {
+	_, err := New("garmin", nil)
+	assert.Error(t, err)
+}
+
+func TestImportRecords(t *testing.T) {
+	db, cleanup := testutils.SetupSQLiteTester(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	for _, hr := range []models.HealthRecord{record("2024-01-01", 7012), record("2024-01-03", 9000)} {
+		_, err := db.CreateHealthRecord(ctx, testutils.TestUserID, &hr)
+		require.NoError(t, err)
+	}
+
+	records := []models.HealthRecord{
+		record("2024-01-01", 7012),   // same as stored
+		record("2024-01-02", 5000),   // new
+		record("2024-01-03", 11230),  // differs from stored
+		record("2024-01-04", 200000), // invalid
+		record("2024-01-02", 5000),   // repeated in the import
+		record("2024-01-02", 6000),   // repeated with another value
+	}
+	stepCount := func(date string) int {
+		hr, err := db.ReadHealthRecord(ctx, testutils.TestUserID, record(date, 0).Date)
+		require.NoError(t, err)
+		if hr == nil {
+			return 0
+		}
+		return hr.StepCount
+	}
+
+	// A stored date fails the whole import, writing nothing
+	_, err := ImportRecords(ctx, db, testutils.TestUserID, records, validators.NewHealthRecordValidator(), models.ConflictFail)
+	assert.ErrorIs(t, err, database.ErrConflict)
+	assert.Zero(t, stepCount("2024-01-02"))
+
+	report, err := ImportRecords(ctx, db, testutils.TestUserID, records, validators.NewHealthRecordValidator(), models.ConflictSkip)
+	require.NoError(t, err)
+
+	assert.Equal(t, 6, report.Days)
+	assert.Equal(t, 1, report.Created)
+	assert.Zero(t, report.Overwritten)
+	assert.Equal(t, []string{"2024-01-01", "2024-01-02"}, report.Duplicates)
+	assert.Equal(t, []Conflict{
+		{Date: "2024-01-03", Stored: 9000, Imported: 11230},
+		{Date: "2024-01-02", Stored: 5000, Imported: 6000},
+	}, report.Conflicts)
+	assert.Equal(t, []DayError{{Date: "2024-01-04", Reason: "step count is unrealistically high"}}, report.Rejected)
+
+	// Nothing stored was overwritten
+	assert.Equal(t, 9000, stepCount("2024-01-03"))
+	assert.Equal(t, 5000, stepCount("2024-01-02"))
+
+	report, err = ImportRecords(ctx, db, testutils.TestUserID, records[:3], validators.NewHealthRecordValidator(), models.ConflictOverwrite)
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.Overwritten)
+	assert.Equal(t, 11230, stepCount("2024-01-03"))
+}
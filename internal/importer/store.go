@@ -0,0 +1,89 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nnamm/go-health-tracker/internal/database"
+	"github.com/nnamm/go-health-tracker/internal/models"
+	"github.com/nnamm/go-health-tracker/internal/validators"
+)
+
+// DayError reports why the record of a day was not imported
+type DayError struct {
+	Date   string `json:"date"`
+	Reason string `json:"reason"`
+}
+
+// Conflict is an imported day whose step count differs from the stored record
+type Conflict struct {
+	Date     string `json:"date"`
+	Stored   int    `json:"stored_step_count"`
+	Imported int    `json:"imported_step_count"`
+}
+
+// ImportReport reports the outcome of importing daily records
+type ImportReport struct {
+	Days        int                   `json:"days"`
+	OnConflict  models.ConflictPolicy `json:"on_conflict"`
+	Created     int                   `json:"created"`
+	Overwritten int                   `json:"overwritten"`
+	Duplicates  []string              `json:"duplicates"`
+	Conflicts   []Conflict            `json:"conflicts"`
+	Rejected    []DayError            `json:"rejected"`
+}
+
+// ImportRecords validates the imported daily records and writes the valid ones in a single transaction,
+// so a failed import stores nothing. A date that already has a record is handled by the conflict policy:
+// skipped days are reported as duplicates when the stored step count is the same and as conflicts otherwise,
+// and under ConflictFail the import returns database.ErrConflict. Dates repeated within records are handled the same way.
+func ImportRecords(ctx context.Context, db database.DBInterface, userID int64, records []models.HealthRecord, v validators.HealthRecordValidator, onConflict models.ConflictPolicy) (*ImportReport, error) {
+	report := &ImportReport{
+		Days:       len(records),
+		OnConflict: onConflict,
+		Duplicates: []string{},
+		Conflicts:  []Conflict{},
+		Rejected:   []DayError{},
+	}
+
+	// The store reports one outcome per yielded record, in order, so the valid records are kept to match them up
+	var valid []models.HealthRecord
+	for i := range records {
+		if err := v.Validate(&records[i]); err != nil {
+			report.Rejected = append(report.Rejected, DayError{Date: records[i].Date.Format("2006-01-02"), Reason: err.Error()})
+			continue
+		}
+		valid = append(valid, records[i])
+	}
+
+	seq := func(yield func(models.HealthRecord, error) bool) {
+		for _, hr := range valid {
+			if !yield(hr, nil) {
+				return
+			}
+		}
+	}
+	imports, err := db.ImportHealthRecords(ctx, userID, seq, models.ImportOptions{OnConflict: onConflict})
+	if err != nil {
+		return nil, fmt.Errorf("import health records: %w", err)
+	}
+
+	for i, imported := range imports {
+		hr := valid[i]
+		switch imported.Action {
+		case models.ImportCreated:
+			report.Created++
+		case models.ImportOverwritten:
+			report.Overwritten++
+		case models.ImportSkipped:
+			date := hr.Date.Format("2006-01-02")
+			if imported.Record.StepCount == hr.StepCount {
+				report.Duplicates = append(report.Duplicates, date)
+			} else {
+				report.Conflicts = append(report.Conflicts, Conflict{Date: date, Stored: imported.Record.StepCount, Imported: hr.StepCount})
+			}
+		}
+	}
+
+	return report, nil
+}
@@ -0,0 +1,64 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := WithLogger(context.Background(), New(&buf, slog.LevelInfo))
+	ctx = WithRequestID(ctx, "abc-123")
+
+	assert.Equal(t, "abc-123", RequestID(ctx))
+
+	FromContext(ctx).Info("handled", slog.Int("status", 200))
+	FromContext(ctx).Debug("below the level")
+
+	var line map[string]any
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, "handled", line["msg"])
+	assert.Equal(t, "abc-123", line["request_id"])
+	assert.Equal(t, float64(200), line["status"])
+	assert.Equal(t, 1, strings.Count(buf.String(), "\n"))
+}
+
+func TestFromContext_Default(t *testing.T) {
+	assert.Same(t, slog.Default(), FromContext(context.Background()))
+	assert.Equal(t, "", RequestID(context.Background()))
+}
+
+func TestNewRequestID(t *testing.T) {
+	id := NewRequestID()
+	assert.Len(t, id, 32)
+	assert.True(t, ValidRequestID(id))
+	assert.NotEqual(t, id, NewRequestID())
+}
+
+func TestValidRequestID(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		want bool
+	}{
+		{"uuid", "3f1c2a9e-7b7d-4c1e-9f5a-2d4b6e8a0c1f", true},
+		{"trace style", "req_01:a.b", true},
+		{"empty", "", false},
+		{"too long", strings.Repeat("a", 129), false},
+		{"space", "abc 123", false},
+		{"newline", "abc\n{\"msg\":\"forged\"}", false},
+		{"non ascii", "ïd", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ValidRequestID(tt.id))
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"net/http"
+	"time"
+)
+
+// APIKeyScope limits what a request authenticated with an API key may do
+type APIKeyScope string
+
+const (
+	APIKeyScopeReadOnly  APIKeyScope = "read-only"
+	APIKeyScopeReadWrite APIKeyScope = "read-write"
+)
+
+// Allows reports whether the scope permits requests with the given HTTP method.
+// Read-only keys are limited to safe methods.
+func (s APIKeyScope) Allows(method string) bool {
+	switch s {
+	case APIKeyScopeReadWrite:
+		return true
+	case APIKeyScopeReadOnly:
+		return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+	default:
+		return false
+	}
+}
+
+// APIKey is a long-lived credential for scripts and devices that cannot log in interactively.
+// Only a hash of the key is stored; Prefix identifies the key to its owner.
+type APIKey struct {
+	ID         int64       `json:"id"`
+	UserID     int64       `json:"-"`
+	Name       string      `json:"name"`
+	Prefix     string      `json:"prefix"`
+	KeyHash    string      `json:"-"`
+	Scope      APIKeyScope `json:"scope"`
+	LastUsedAt *time.Time  `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time  `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time   `json:"created_at"`
+	UpdatedAt  time.Time   `json:"updated_at"`
+}
+
+// IsRevoked returns true if the key has been revoked
+func (k *APIKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
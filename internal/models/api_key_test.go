@@ -0,0 +1,28 @@
+package models
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestAPIKeyScope_Allows(t *testing.T) {
+	tests := []struct {
+		scope  APIKeyScope
+		method string
+		want   bool
+	}{
+		{APIKeyScopeReadOnly, http.MethodGet, true},
+		{APIKeyScopeReadOnly, http.MethodOptions, true},
+		{APIKeyScopeReadOnly, http.MethodPost, false},
+		{APIKeyScopeReadOnly, http.MethodDelete, false},
+		{APIKeyScopeReadWrite, http.MethodGet, true},
+		{APIKeyScopeReadWrite, http.MethodPut, true},
+		{APIKeyScope("admin"), http.MethodGet, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.scope.Allows(tt.method); got != tt.want {
+			t.Errorf("%q.Allows(%s) = %v, want %v", tt.scope, tt.method, got, tt.want)
+		}
+	}
+}
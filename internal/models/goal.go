@@ -0,0 +1,159 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// StepGoal is a daily step goal that applies from EffectiveFrom until the next goal takes effect.
+// Changing the goal adds a new entry, so days before the change keep their original goal.
+type StepGoal struct {
+	ID            int64     `json:"id"`
+	EffectiveFrom time.Time `json:"effective_from"`
+	StepCount     int       `json:"step_count"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// converts the effective date to YYYY-MM-DD format JSON output.
+func (g *StepGoal) MarshalJSON() ([]byte, error) {
+	type Alias StepGoal
+	return json.Marshal(&struct {
+		EffectiveFrom string `json:"effective_from"`
+		*Alias
+	}{
+		EffectiveFrom: g.EffectiveFrom.Format("2006-01-02"),
+		Alias:         (*Alias)(g),
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// effective_from is a YYYY-MM-DD date.
+func (g *StepGoal) UnmarshalJSON(data []byte) error {
+	type Alias StepGoal
+	aux := &struct {
+		EffectiveFrom string `json:"effective_from"`
+		*Alias
+	}{
+		Alias: (*Alias)(g),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return fmt.Errorf("failed to unmarshal step goal: %w", err)
+	}
+
+	if aux.EffectiveFrom == "" {
+		g.EffectiveFrom = time.Time{}
+		return nil
+	}
+	t, err := time.Parse("2006-01-02", aux.EffectiveFrom)
+	if err != nil {
+		return fmt.Errorf("invalid effective_from format: %s (use YYYY-MM-DD)", aux.EffectiveFrom)
+	}
+	g.EffectiveFrom = t
+	return nil
+}
+
+// GoalDay reports whether the goal in effect on a date was met.
+// A day without a health record counts as missed.
+type GoalDay struct {
+	Date      time.Time `json:"date"`
+	StepCount int       `json:"step_count"`
+	Goal      int       `json:"goal"`
+	Recorded  bool      `json:"recorded"`
+	Met       bool      `json:"met"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// converts the day's date to YYYY-MM-DD format JSON output.
+func (d *GoalDay) MarshalJSON() ([]byte, error) {
+	type Alias GoalDay
+	return json.Marshal(&struct {
+		Date string `json:"date"`
+		*Alias
+	}{
+		Date:  d.Date.Format("2006-01-02"),
+		Alias: (*Alias)(d),
+	})
+}
+
+// MonthlyGoalCompletion is the share of days in a month on which the goal was met
+type MonthlyGoalCompletion struct {
+	Month          string  `json:"month"`
+	Days           int     `json:"days"`
+	MetDays        int     `json:"met_days"`
+	CompletionRate float64 `json:"completion_rate"`
+}
+
+// EvaluateGoalDays evaluates every day in [start, end) against the goal in effect on that day.
+// records must be ordered by date and goals by EffectiveFrom. Days before the first goal are skipped.
+func EvaluateGoalDays(records []HealthRecord, goals []StepGoal, start, end time.Time) []GoalDay {
+	steps := make(map[string]int, len(records))
+	for _, hr := range records {
+		steps[hr.Date.Format("2006-01-02")] = hr.StepCount
+	}
+
+	var days []GoalDay
+	g := -1
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		for g+1 < len(goals) && !goals[g+1].EffectiveFrom.After(day) {
+			g++
+		}
+		if g < 0 {
+			continue
+		}
+
+		d := GoalDay{Date: day, Goal: goals[g].StepCount}
+		d.StepCount, d.Recorded = steps[day.Format("2006-01-02")]
+		d.Met = d.Recorded && d.StepCount >= d.Goal
+		days = append(days, d)
+	}
+	return days
+}
+
+// GoalStreaks returns the current and the longest run of consecutive days on which the goal was met.
+// days must be consecutive as returned by EvaluateGoalDays. The current streak ends on the last day;
+// if that day is today and not met yet, it is still in progress and does not break the streak.
+func GoalStreaks(days []GoalDay, today time.Time) (current, longest int) {
+	run := 0
+	for _, d := range days {
+		if d.Met {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+
+	end := len(days)
+	if end > 0 && !days[end-1].Met && days[end-1].Date.Equal(dateOf(today)) {
+		end--
+	}
+	for i := end - 1; i >= 0 && days[i].Met; i-- {
+		current++
+	}
+	return current, longest
+}
+
+// MonthlyGoalCompletions computes the completion rate per month of days ordered by date
+func MonthlyGoalCompletions(days []GoalDay) []MonthlyGoalCompletion {
+	var months []MonthlyGoalCompletion
+	for _, d := range days {
+		month := d.Date.Format("2006-01")
+		if len(months) == 0 || months[len(months)-1].Month != month {
+			months = append(months, MonthlyGoalCompletion{Month: month})
+		}
+
+		m := &months[len(months)-1]
+		m.Days++
+		if d.Met {
+			m.MetDays++
+		}
+	}
+
+	for i := range months {
+		months[i].CompletionRate = float64(months[i].MetDays) / float64(months[i].Days)
+	}
+	return months
+}
@@ -0,0 +1,134 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func parseDay(s string) time.Time {
+	t, _ := time.Parse("2006-01-02", s)
+	return t
+}
+
+func TestStepGoal_JSON(t *testing.T) {
+	var g StepGoal
+	if err := json.Unmarshal([]byte(`{"effective_from":"2024-03-01","step_count":8000}`), &g); err != nil {
+		t.Fatalf("UnmarshalJSON() error = %v", err)
+	}
+	if !g.EffectiveFrom.Equal(parseDay("2024-03-01")) || g.StepCount != 8000 {
+		t.Errorf("UnmarshalJSON() = %+v", g)
+	}
+
+	if err := json.Unmarshal([]byte(`{"effective_from":"2024/03/01","step_count":8000}`), &g); err == nil {
+		t.Error("UnmarshalJSON() with an invalid date should fail")
+	}
+
+	out, err := json.Marshal(&StepGoal{EffectiveFrom: parseDay("2024-03-01"), StepCount: 8000})
+	if err != nil {
+		t.Fatalf("MarshalJSON() error = %v", err)
+	}
+	var m map[string]any
+	json.Unmarshal(out, &m)
+	if m["effective_from"] != "2024-03-01" {
+		t.Errorf("effective_from = %v, want 2024-03-01", m["effective_from"])
+	}
+}
+
+func TestEvaluateGoalDays(t *testing.T) {
+	records := []HealthRecord{
+		{Date: parseDay("2024-03-01"), StepCount: 9000},
+		{Date: parseDay("2024-03-02"), StepCount: 7000},
+		{Date: parseDay("2024-03-04"), StepCount: 7000},
+	}
+	goals := []StepGoal{
+		{EffectiveFrom: parseDay("2024-03-02"), StepCount: 8000},
+		{EffectiveFrom: parseDay("2024-03-04"), StepCount: 6000},
+	}
+
+	days := EvaluateGoalDays(records, goals, parseDay("2024-03-01"), parseDay("2024-03-06"))
+
+	want := []GoalDay{
+		// 2024-03-01 has no goal in effect yet and is skipped
+		{Date: parseDay("2024-03-02"), StepCount: 7000, Goal: 8000, Recorded: true, Met: false},
+		{Date: parseDay("2024-03-03"), Goal: 8000, Recorded: false, Met: false},
+		{Date: parseDay("2024-03-04"), StepCount: 7000, Goal: 6000, Recorded: true, Met: true},
+		{Date: parseDay("2024-03-05"), Goal: 6000, Recorded: false, Met: false},
+	}
+	if len(days) != len(want) {
+		t.Fatalf("got %d days, want %d", len(days), len(want))
+	}
+	for i := range want {
+		if days[i] != want[i] {
+			t.Errorf("day %d = %+v, want %+v", i, days[i], want[i])
+		}
+	}
+}
+
+func TestGoalStreaks(t *testing.T) {
+	met := func(date string, ok bool) GoalDay {
+		return GoalDay{Date: parseDay(date), Met: ok}
+	}
+
+	tests := []struct {
+		name        string
+		days        []GoalDay
+		today       time.Time
+		wantCurrent int
+		wantLongest int
+	}{
+		{name: "no days", wantCurrent: 0, wantLongest: 0},
+		{
+			name:        "missed day breaks the streak",
+			days:        []GoalDay{met("2024-03-01", true), met("2024-03-02", true), met("2024-03-03", false), met("2024-03-04", true)},
+			today:       parseDay("2024-03-10"),
+			wantCurrent: 1,
+			wantLongest: 2,
+		},
+		{
+			name:        "current streak ended before the last day",
+			days:        []GoalDay{met("2024-03-01", true), met("2024-03-02", true), met("2024-03-03", false)},
+			today:       parseDay("2024-03-10"),
+			wantCurrent: 0,
+			wantLongest: 2,
+		},
+		{
+			name:        "today not met yet keeps the streak",
+			days:        []GoalDay{met("2024-03-01", true), met("2024-03-02", true), met("2024-03-03", false)},
+			today:       parseDay("2024-03-03"),
+			wantCurrent: 2,
+			wantLongest: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, longest := GoalStreaks(tt.days, tt.today)
+			if current != tt.wantCurrent || longest != tt.wantLongest {
+				t.Errorf("GoalStreaks() = %d, %d, want %d, %d", current, longest, tt.wantCurrent, tt.wantLongest)
+			}
+		})
+	}
+}
+
+func TestMonthlyGoalCompletions(t *testing.T) {
+	days := []GoalDay{
+		{Date: parseDay("2024-01-30"), Met: true},
+		{Date: parseDay("2024-01-31"), Met: false},
+		{Date: parseDay("2024-02-01"), Met: true},
+	}
+
+	got := MonthlyGoalCompletions(days)
+	want := []MonthlyGoalCompletion{
+		{Month: "2024-01", Days: 2, MetDays: 1, CompletionRate: 0.5},
+		{Month: "2024-02", Days: 1, MetDays: 1, CompletionRate: 1},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d months, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("month %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
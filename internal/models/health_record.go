@@ -12,10 +12,17 @@ type HealthRecord struct {
 	ID        int64     `json:"id"`
 	Date      time.Time `json:"date"`
 	StepCount int       `json:"step_count"`
+	Version   int64     `json:"version,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// HealthRecordUpsert is a record stored by a create-or-replace and whether it was created
+type HealthRecordUpsert struct {
+	Record  HealthRecord
+	Created bool
+}
+
 // MarshalJSON implements the json.Marshaler interface.
 // converts the record's date to YYYY-MM-DD format JSON output.
 func (hr *HealthRecord) MarshalJSON() ([]byte, error) {
@@ -63,3 +70,8 @@ func (hr *HealthRecord) UnmarshalJSON(date []byte) error {
 		return fmt.Errorf("unexpected date type: %T", aux.Date)
 	}
 }
+
+// dateOf returns the calendar date of t in its own offset, as midnight UTC
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// IdempotencyKey is the stored outcome of a request sent with an Idempotency-Key header.
+// It is reserved when the first request starts and holds its response once that completes,
+// so retries with the same key get the same response instead of repeating the request.
+type IdempotencyKey struct {
+	Key string
+
+	// RequestHash identifies the request, so a reused key with a different payload can be detected
+	RequestHash string
+
+	// StatusCode is 0 while the first request is still being processed
+	StatusCode int
+	Header     map[string]string
+	Body       []byte
+
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// Completed reports whether the response of the first request has been stored
+func (k *IdempotencyKey) Completed() bool {
+	return k.StatusCode != 0
+}
@@ -0,0 +1,50 @@
+package models
+
+import "fmt"
+
+// ConflictPolicy decides what an import does with a record for a date that already has one
+type ConflictPolicy string
+
+const (
+	// ConflictSkip keeps the stored record and skips the imported one
+	ConflictSkip ConflictPolicy = "skip"
+	// ConflictOverwrite replaces the stored record with the imported one
+	ConflictOverwrite ConflictPolicy = "overwrite"
+	// ConflictFail aborts the whole import without writing anything
+	ConflictFail ConflictPolicy = "fail"
+)
+
+// ParseConflictPolicy parses a conflict policy. An empty value means ConflictSkip.
+func ParseConflictPolicy(s string) (ConflictPolicy, error) {
+	switch p := ConflictPolicy(s); p {
+	case "":
+		return ConflictSkip, nil
+	case ConflictSkip, ConflictOverwrite, ConflictFail:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown conflict policy: %s (use skip, overwrite or fail)", s)
+	}
+}
+
+// ImportAction is what an import did with a record
+type ImportAction string
+
+const (
+	ImportCreated     ImportAction = "created"
+	ImportOverwritten ImportAction = "overwritten"
+	ImportSkipped     ImportAction = "skipped"
+)
+
+// ImportOptions controls how a batch of records is imported
+type ImportOptions struct {
+	OnConflict ConflictPolicy
+	// DryRun rolls the import back after every record has been applied
+	DryRun bool
+}
+
+// HealthRecordImport is the outcome of importing a record.
+// Record is the stored record, which for a skipped import is the one that was already there.
+type HealthRecordImport struct {
+	Record HealthRecord
+	Action ImportAction
+}
@@ -0,0 +1,28 @@
+package models
+
+import "testing"
+
+func TestParseConflictPolicy(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    ConflictPolicy
+		wantErr bool
+	}{
+		{input: "", want: ConflictSkip},
+		{input: "skip", want: ConflictSkip},
+		{input: "overwrite", want: ConflictOverwrite},
+		{input: "fail", want: ConflictFail},
+		{input: "merge", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseConflictPolicy(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseConflictPolicy(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseConflictPolicy(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// MetricValueType defines how the values of a custom metric are interpreted
+type MetricValueType string
+
+const (
+	MetricValueTypeInt      MetricValueType = "int"
+	MetricValueTypeFloat    MetricValueType = "float"
+	MetricValueTypeDuration MetricValueType = "duration" // seconds
+	MetricValueTypeEnum     MetricValueType = "enum"
+)
+
+// MetricAggregation defines how several values of one day are combined
+type MetricAggregation string
+
+const (
+	MetricAggregationSum  MetricAggregation = "sum"
+	MetricAggregationAvg  MetricAggregation = "avg"
+	MetricAggregationLast MetricAggregation = "last"
+)
+
+// MetricDefinition describes a user-registered custom metric.
+// Min and Max bound numeric values, and EnumValues lists the allowed values of an enum metric.
+type MetricDefinition struct {
+	ID          int64             `json:"id"`
+	Name        string            `json:"name"`
+	Unit        string            `json:"unit,omitempty"`
+	ValueType   MetricValueType   `json:"value_type"`
+	Min         *float64          `json:"min,omitempty"`
+	Max         *float64          `json:"max,omitempty"`
+	EnumValues  []string          `json:"enum_values,omitempty"`
+	Aggregation MetricAggregation `json:"aggregation"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// IsNumeric reports whether the metric stores numeric values rather than enum values
+func (d *MetricDefinition) IsNumeric() bool {
+	return d.ValueType != MetricValueTypeEnum
+}
+
+// MetricValue is a single recorded value of a custom metric.
+// Numeric metrics use Value (durations in seconds), enum metrics use EnumValue.
+type MetricValue struct {
+	ID         int64     `json:"id"`
+	MetricID   int64     `json:"metric_id"`
+	Metric     string    `json:"metric"`
+	Date       time.Time `json:"date"`
+	RecordedAt time.Time `json:"recorded_at"`
+	Value      *float64  `json:"value,omitempty"`
+	EnumValue  string    `json:"enum_value,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// SetDerivedFields fills Date from RecordedAt in the offset it was reported in
+func (mv *MetricValue) SetDerivedFields() {
+	mv.Date = dateOf(mv.RecordedAt)
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// converts the value's date to YYYY-MM-DD format JSON output.
+func (mv *MetricValue) MarshalJSON() ([]byte, error) {
+	type Alias MetricValue
+	return json.Marshal(&struct {
+		Date string `json:"date"`
+		*Alias
+	}{
+		Date:  mv.Date.Format("2006-01-02"),
+		Alias: (*Alias)(mv),
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// date is derived from recorded_at, so it is ignored on input.
+func (mv *MetricValue) UnmarshalJSON(data []byte) error {
+	type Alias MetricValue
+	aux := &struct {
+		Date any `json:"date"`
+		*Alias
+	}{
+		Alias: (*Alias)(mv),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return fmt.Errorf("failed to unmarshal metric value: %w", err)
+	}
+	return nil
+}
+
+// DailyMetricValue is the daily value of a custom metric, combined by the definition's aggregation
+type DailyMetricValue struct {
+	Date        time.Time         `json:"date"`
+	Value       *float64          `json:"value,omitempty"`
+	EnumValue   string            `json:"enum_value,omitempty"`
+	ValueCount  int               `json:"value_count"`
+	Aggregation MetricAggregation `json:"aggregation"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// converts the daily value's date to YYYY-MM-DD format JSON output.
+func (d *DailyMetricValue) MarshalJSON() ([]byte, error) {
+	type Alias DailyMetricValue
+	return json.Marshal(&struct {
+		Date string `json:"date"`
+		*Alias
+	}{
+		Date:  d.Date.Format("2006-01-02"),
+		Alias: (*Alias)(d),
+	})
+}
+
+// AggregateMetricValues combines values ordered by RecordedAt into one value per date,
+// using the aggregation rule of the metric definition
+func AggregateMetricValues(def *MetricDefinition, values []MetricValue) []DailyMetricValue {
+	var daily []DailyMetricValue
+	for start := 0; start < len(values); {
+		end := start + 1
+		for end < len(values) && values[end].Date.Equal(values[start].Date) {
+			end++
+		}
+
+		day := values[start:end]
+		d := DailyMetricValue{
+			Date:        day[0].Date,
+			ValueCount:  len(day),
+			Aggregation: def.Aggregation,
+		}
+
+		switch def.Aggregation {
+		case MetricAggregationSum, MetricAggregationAvg:
+			var sum float64
+			for _, v := range day {
+				if v.Value != nil {
+					sum += *v.Value
+				}
+			}
+			if def.Aggregation == MetricAggregationAvg {
+				sum = roundTo(sum/float64(len(day)), 2)
+			}
+			d.Value = &sum
+		default:
+			last := day[len(day)-1]
+			d.Value = last.Value
+			d.EnumValue = last.EnumValue
+		}
+
+		daily = append(daily, d)
+		start = end
+	}
+	return daily
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAggregateMetricValues(t *testing.T) {
+	day := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	value := func(at time.Time, v float64) MetricValue {
+		mv := MetricValue{RecordedAt: at, Value: &v}
+		mv.SetDerivedFields()
+		return mv
+	}
+	values := []MetricValue{
+		value(day, 2),
+		value(day.Add(4*time.Hour), 3),
+		value(day.Add(8*time.Hour), 4),
+		value(day.AddDate(0, 0, 1), 5),
+	}
+
+	tests := []struct {
+		aggregation MetricAggregation
+		want        float64
+	}{
+		{aggregation: MetricAggregationSum, want: 9},
+		{aggregation: MetricAggregationAvg, want: 3},
+		{aggregation: MetricAggregationLast, want: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.aggregation), func(t *testing.T) {
+			def := &MetricDefinition{ValueType: MetricValueTypeInt, Aggregation: tt.aggregation}
+			daily := AggregateMetricValues(def, values)
+			if len(daily) != 2 {
+				t.Fatalf("got %d days, want 2", len(daily))
+			}
+			if daily[0].Value == nil || *daily[0].Value != tt.want || daily[0].ValueCount != 3 {
+				t.Errorf("day 1 = %+v, want value %v over 3 values", daily[0], tt.want)
+			}
+		})
+	}
+}
+
+func TestAggregateMetricValues_Enum(t *testing.T) {
+	day := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	var values []MetricValue
+	for i, mood := range []string{"low", "high"} {
+		mv := MetricValue{RecordedAt: day.Add(time.Duration(i) * time.Hour), EnumValue: mood}
+		mv.SetDerivedFields()
+		values = append(values, mv)
+	}
+
+	def := &MetricDefinition{ValueType: MetricValueTypeEnum, Aggregation: MetricAggregationLast}
+	daily := AggregateMetricValues(def, values)
+	if len(daily) != 1 || daily[0].EnumValue != "high" || daily[0].Value != nil {
+		t.Errorf("daily = %+v, want the last enum value", daily)
+	}
+}
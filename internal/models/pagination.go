@@ -0,0 +1,100 @@
+package models
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Page size limits of list endpoints
+const (
+	DefaultPageLimit = 100
+	MaxPageLimit     = 1000
+)
+
+// RecordSort defines the order of a health record listing
+type RecordSort string
+
+const (
+	RecordSortDate          RecordSort = "date"
+	RecordSortDateDesc      RecordSort = "-date"
+	RecordSortStepCount     RecordSort = "step_count"
+	RecordSortStepCountDesc RecordSort = "-step_count"
+)
+
+// ParseRecordSort parses a sort order, defaulting to ascending date when empty
+func ParseRecordSort(s string) (RecordSort, error) {
+	switch RecordSort(s) {
+	case "", RecordSortDate:
+		return RecordSortDate, nil
+	case RecordSortDateDesc, RecordSortStepCount, RecordSortStepCountDesc:
+		return RecordSort(s), nil
+	default:
+		return "", fmt.Errorf("unknown sort: %s (use date, -date, step_count or -step_count)", s)
+	}
+}
+
+// HealthRecordCursor marks the last record of a page.
+// The next page starts after it in the sort order; the date breaks ties between equal step counts.
+type HealthRecordCursor struct {
+	Sort      RecordSort
+	Date      time.Time
+	StepCount int
+}
+
+// CursorFor returns the cursor pointing after the given record
+func CursorFor(sort RecordSort, hr *HealthRecord) *HealthRecordCursor {
+	return &HealthRecordCursor{Sort: sort, Date: hr.Date, StepCount: hr.StepCount}
+}
+
+// Encode returns the opaque string form of the cursor
+func (c *HealthRecordCursor) Encode() string {
+	raw := string(c.Sort) + "|" + c.Date.Format("2006-01-02") + "|" + strconv.Itoa(c.StepCount)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeHealthRecordCursor parses a cursor returned by Encode
+func DecodeHealthRecordCursor(s string) (*HealthRecordCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	parts := strings.Split(string(raw), "|")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	sort, err := ParseRecordSort(parts[0])
+	if err != nil || parts[0] == "" {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	date, err := time.Parse("2006-01-02", parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	stepCount, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	return &HealthRecordCursor{Sort: sort, Date: date, StepCount: stepCount}, nil
+}
+
+// PageRequest selects one page of a health record listing.
+// After is nil for the first page.
+type PageRequest struct {
+	Limit int
+	Sort  RecordSort
+	After *HealthRecordCursor
+}
+
+// HealthRecordPage is one page of health records.
+// NextCursor is empty on the last page, and Total counts all records of the listing.
+type HealthRecordPage struct {
+	Records    []HealthRecord
+	NextCursor string
+	Total      int
+}
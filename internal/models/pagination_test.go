@@ -0,0 +1,51 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRecordSort(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    RecordSort
+		wantErr bool
+	}{
+		{input: "", want: RecordSortDate},
+		{input: "date", want: RecordSortDate},
+		{input: "-date", want: RecordSortDateDesc},
+		{input: "step_count", want: RecordSortStepCount},
+		{input: "-step_count", want: RecordSortStepCountDesc},
+		{input: "created_at", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseRecordSort(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseRecordSort(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseRecordSort(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestHealthRecordCursor(t *testing.T) {
+	hr := &HealthRecord{Date: time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC), StepCount: 9876}
+	cursor := CursorFor(RecordSortStepCountDesc, hr)
+
+	got, err := DecodeHealthRecordCursor(cursor.Encode())
+	if err != nil {
+		t.Fatalf("DecodeHealthRecordCursor() error = %v", err)
+	}
+	if *got != *cursor {
+		t.Errorf("DecodeHealthRecordCursor() = %+v, want %+v", got, cursor)
+	}
+
+	for _, invalid := range []string{"", "not base64!", "ZGF0ZXwyMDI0LTAzLTE1", "fDIwMjQtMDMtMTV8MQ"} {
+		if _, err := DecodeHealthRecordCursor(invalid); err == nil {
+			t.Errorf("DecodeHealthRecordCursor(%q) should fail", invalid)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// SleepSession represents a single period of sleep.
+// A session belongs to the date of its wake time, so naps and split sleep
+// produce several sessions for the same date.
+type SleepSession struct {
+	ID              int64     `json:"id"`
+	Date            time.Time `json:"date"`
+	BedTime         time.Time `json:"bed_time"`
+	WakeTime        time.Time `json:"wake_time"`
+	DurationMinutes int       `json:"duration_minutes"`
+	Quality         *int      `json:"quality,omitempty"`
+	IsNap           bool      `json:"is_nap"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// SetDerivedFields fills Date and DurationMinutes from BedTime and WakeTime.
+// Date is the calendar day of the wake time in the offset it was reported in.
+func (s *SleepSession) SetDerivedFields() {
+	s.Date = dateOf(s.WakeTime)
+	s.DurationMinutes = int(s.WakeTime.Sub(s.BedTime).Minutes())
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// converts the session's date to YYYY-MM-DD format JSON output.
+func (s *SleepSession) MarshalJSON() ([]byte, error) {
+	type Alias SleepSession
+	return json.Marshal(&struct {
+		Date string `json:"date"`
+		*Alias
+	}{
+		Date:  s.Date.Format("2006-01-02"),
+		Alias: (*Alias)(s),
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// date and duration are derived values, so they are ignored on input.
+func (s *SleepSession) UnmarshalJSON(data []byte) error {
+	type Alias SleepSession
+	aux := &struct {
+		Date            any `json:"date"`
+		DurationMinutes any `json:"duration_minutes"`
+		*Alias
+	}{
+		Alias: (*Alias)(s),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return fmt.Errorf("failed to unmarshal sleep session: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestSleepSession_SetDerivedFields(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	s := SleepSession{
+		BedTime:  time.Date(2024, 5, 1, 23, 30, 0, 0, jst),
+		WakeTime: time.Date(2024, 5, 2, 6, 45, 0, 0, jst),
+	}
+
+	s.SetDerivedFields()
+
+	if got := s.Date.Format("2006-01-02"); got != "2024-05-02" {
+		t.Errorf("Date = %s, want 2024-05-02", got)
+	}
+	if s.DurationMinutes != 435 {
+		t.Errorf("DurationMinutes = %d, want 435", s.DurationMinutes)
+	}
+}
+
+func TestSleepSession_UnmarshalJSON(t *testing.T) {
+	input := `{"date":"1999-01-01","duration_minutes":1,"bed_time":"2024-05-01T23:30:00+09:00","wake_time":"2024-05-02T06:45:00+09:00","quality":85,"is_nap":false}`
+
+	var s SleepSession
+	if err := json.Unmarshal([]byte(input), &s); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+
+	if !s.Date.IsZero() || s.DurationMinutes != 0 {
+		t.Errorf("derived fields should be ignored on input, got date=%v duration=%d", s.Date, s.DurationMinutes)
+	}
+	if s.Quality == nil || *s.Quality != 85 {
+		t.Errorf("Quality = %v, want 85", s.Quality)
+	}
+	if s.WakeTime.IsZero() || s.BedTime.IsZero() {
+		t.Errorf("bed and wake times should be parsed")
+	}
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// StatsGrouping defines the periods step count statistics are computed for
+type StatsGrouping string
+
+const (
+	StatsGroupNone  StatsGrouping = ""
+	StatsGroupDay   StatsGrouping = "day"
+	StatsGroupWeek  StatsGrouping = "week" // ISO 8601 week, e.g. 2024-W01
+	StatsGroupMonth StatsGrouping = "month"
+	StatsGroupYear  StatsGrouping = "year"
+)
+
+// StatsPercentiles are the percentiles computed for every period
+var StatsPercentiles = []int{25, 50, 75, 90, 95}
+
+// ParseStatsGrouping parses a grouping name; empty computes one set of statistics for the whole range
+func ParseStatsGrouping(s string) (StatsGrouping, error) {
+	switch StatsGrouping(s) {
+	case StatsGroupNone, StatsGroupDay, StatsGroupWeek, StatsGroupMonth, StatsGroupYear:
+		return StatsGrouping(s), nil
+	default:
+		return "", fmt.Errorf("unknown group_by: %s (use day, week, month or year)", s)
+	}
+}
+
+// StepCountExtreme is the minimum or maximum step count of a period and the date it was recorded.
+// Of several days with the same step count, the earliest is reported.
+type StepCountExtreme struct {
+	StepCount int       `json:"step_count"`
+	Date      time.Time `json:"date"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// converts the date to YYYY-MM-DD format JSON output.
+func (e *StepCountExtreme) MarshalJSON() ([]byte, error) {
+	type Alias StepCountExtreme
+	return json.Marshal(&struct {
+		Date string `json:"date"`
+		*Alias
+	}{
+		Date:  e.Date.Format("2006-01-02"),
+		Alias: (*Alias)(e),
+	})
+}
+
+// StepCountStats holds the step count statistics of one period.
+// StdDev is the population standard deviation, and percentiles are linearly interpolated
+// between the closest ranks and keyed p25, p50, and so on.
+type StepCountStats struct {
+	Period      string             `json:"period"`
+	Count       int                `json:"count"`
+	Sum         int64              `json:"sum"`
+	Mean        float64            `json:"mean"`
+	Median      float64            `json:"median"`
+	Min         StepCountExtreme   `json:"min"`
+	Max         StepCountExtreme   `json:"max"`
+	StdDev      float64            `json:"stddev"`
+	Percentiles map[string]float64 `json:"percentiles"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestParseStatsGrouping(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    StatsGrouping
+		wantErr bool
+	}{
+		{input: "", want: StatsGroupNone},
+		{input: "day", want: StatsGroupDay},
+		{input: "week", want: StatsGroupWeek},
+		{input: "month", want: StatsGroupMonth},
+		{input: "year", want: StatsGroupYear},
+		{input: "quarter", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseStatsGrouping(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseStatsGrouping(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseStatsGrouping(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestStepCountExtreme_MarshalJSON(t *testing.T) {
+	e := StepCountExtreme{StepCount: 12000, Date: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)}
+	got, err := json.Marshal(&e)
+	if err != nil {
+		t.Fatalf("MarshalJSON() error = %v", err)
+	}
+	if want := `{"date":"2024-05-01","step_count":12000}`; string(got) != want {
+		t.Errorf("MarshalJSON() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,9 @@
+package models
+
+// Usage summarizes how the service is used across all users, for monitoring
+type Usage struct {
+	// Users is the number of user accounts
+	Users int64
+	// RecordsWritten is the number of health records created or updated since the requested time
+	RecordsWritten int64
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// User is an account that owns health data.
+// Every record is stored against the ID of the user it belongs to.
+type User struct {
+	ID           int64     `json:"id"`
+	Email        string    `json:"email"`
+	PasswordHash string    `json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
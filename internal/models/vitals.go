@@ -0,0 +1,239 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+)
+
+// VitalContext describes the situation a vital sign reading was taken in
+type VitalContext string
+
+const (
+	VitalContextResting      VitalContext = "resting"
+	VitalContextPostExercise VitalContext = "post_exercise"
+	VitalContextOther        VitalContext = "other"
+)
+
+// MeasurementArm is the arm a blood pressure reading was taken on
+type MeasurementArm string
+
+const (
+	MeasurementArmLeft  MeasurementArm = "left"
+	MeasurementArmRight MeasurementArm = "right"
+)
+
+// BloodPressureReading represents a single timestamped blood pressure measurement (mmHg)
+type BloodPressureReading struct {
+	ID         int64          `json:"id"`
+	Date       time.Time      `json:"date"`
+	MeasuredAt time.Time      `json:"measured_at"`
+	Systolic   int            `json:"systolic"`
+	Diastolic  int            `json:"diastolic"`
+	Pulse      *int           `json:"pulse,omitempty"`
+	Context    VitalContext   `json:"context,omitempty"`
+	Arm        MeasurementArm `json:"arm,omitempty"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
+
+// SetDerivedFields fills Date from MeasuredAt in the offset it was reported in
+func (bp *BloodPressureReading) SetDerivedFields() {
+	bp.Date = dateOf(bp.MeasuredAt)
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// converts the reading's date to YYYY-MM-DD format JSON output.
+func (bp *BloodPressureReading) MarshalJSON() ([]byte, error) {
+	type Alias BloodPressureReading
+	return json.Marshal(&struct {
+		Date string `json:"date"`
+		*Alias
+	}{
+		Date:  bp.Date.Format("2006-01-02"),
+		Alias: (*Alias)(bp),
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// date is derived from measured_at, so it is ignored on input.
+func (bp *BloodPressureReading) UnmarshalJSON(data []byte) error {
+	type Alias BloodPressureReading
+	aux := &struct {
+		Date any `json:"date"`
+		*Alias
+	}{
+		Alias: (*Alias)(bp),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return fmt.Errorf("failed to unmarshal blood pressure reading: %w", err)
+	}
+	return nil
+}
+
+// HeartRateReading represents a single timestamped heart rate measurement
+type HeartRateReading struct {
+	ID         int64        `json:"id"`
+	Date       time.Time    `json:"date"`
+	MeasuredAt time.Time    `json:"measured_at"`
+	BPM        int          `json:"bpm"`
+	Context    VitalContext `json:"context,omitempty"`
+	CreatedAt  time.Time    `json:"created_at"`
+	UpdatedAt  time.Time    `json:"updated_at"`
+}
+
+// SetDerivedFields fills Date from MeasuredAt in the offset it was reported in
+func (hr *HeartRateReading) SetDerivedFields() {
+	hr.Date = dateOf(hr.MeasuredAt)
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// converts the reading's date to YYYY-MM-DD format JSON output.
+func (hr *HeartRateReading) MarshalJSON() ([]byte, error) {
+	type Alias HeartRateReading
+	return json.Marshal(&struct {
+		Date string `json:"date"`
+		*Alias
+	}{
+		Date:  hr.Date.Format("2006-01-02"),
+		Alias: (*Alias)(hr),
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// date is derived from measured_at, so it is ignored on input.
+func (hr *HeartRateReading) UnmarshalJSON(data []byte) error {
+	type Alias HeartRateReading
+	aux := &struct {
+		Date any `json:"date"`
+		*Alias
+	}{
+		Alias: (*Alias)(hr),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return fmt.Errorf("failed to unmarshal heart rate reading: %w", err)
+	}
+	return nil
+}
+
+// VitalStats holds the minimum, maximum and average of one value over a day
+type VitalStats struct {
+	Min int     `json:"min"`
+	Max int     `json:"max"`
+	Avg float64 `json:"avg"`
+}
+
+// DailyBloodPressure summarizes the blood pressure readings of one date
+type DailyBloodPressure struct {
+	Date         time.Time   `json:"date"`
+	Systolic     VitalStats  `json:"systolic"`
+	Diastolic    VitalStats  `json:"diastolic"`
+	Pulse        *VitalStats `json:"pulse,omitempty"`
+	ReadingCount int         `json:"reading_count"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// converts the summary's date to YYYY-MM-DD format JSON output.
+func (d *DailyBloodPressure) MarshalJSON() ([]byte, error) {
+	type Alias DailyBloodPressure
+	return json.Marshal(&struct {
+		Date string `json:"date"`
+		*Alias
+	}{
+		Date:  d.Date.Format("2006-01-02"),
+		Alias: (*Alias)(d),
+	})
+}
+
+// DailyHeartRate summarizes the heart rate readings of one date
+type DailyHeartRate struct {
+	Date         time.Time  `json:"date"`
+	BPM          VitalStats `json:"bpm"`
+	ReadingCount int        `json:"reading_count"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// converts the summary's date to YYYY-MM-DD format JSON output.
+func (d *DailyHeartRate) MarshalJSON() ([]byte, error) {
+	type Alias DailyHeartRate
+	return json.Marshal(&struct {
+		Date string `json:"date"`
+		*Alias
+	}{
+		Date:  d.Date.Format("2006-01-02"),
+		Alias: (*Alias)(d),
+	})
+}
+
+// SummarizeBloodPressure computes the daily min, max and average of readings ordered by date
+func SummarizeBloodPressure(readings []BloodPressureReading) []DailyBloodPressure {
+	var daily []DailyBloodPressure
+	for start := 0; start < len(readings); {
+		end := start + 1
+		for end < len(readings) && readings[end].Date.Equal(readings[start].Date) {
+			end++
+		}
+
+		var systolic, diastolic, pulse []int
+		for _, r := range readings[start:end] {
+			systolic = append(systolic, r.Systolic)
+			diastolic = append(diastolic, r.Diastolic)
+			if r.Pulse != nil {
+				pulse = append(pulse, *r.Pulse)
+			}
+		}
+
+		d := DailyBloodPressure{
+			Date:         readings[start].Date,
+			Systolic:     vitalStatsOf(systolic),
+			Diastolic:    vitalStatsOf(diastolic),
+			ReadingCount: end - start,
+		}
+		if len(pulse) > 0 {
+			stats := vitalStatsOf(pulse)
+			d.Pulse = &stats
+		}
+
+		daily = append(daily, d)
+		start = end
+	}
+	return daily
+}
+
+// SummarizeHeartRate computes the daily min, max and average of readings ordered by date
+func SummarizeHeartRate(readings []HeartRateReading) []DailyHeartRate {
+	var daily []DailyHeartRate
+	for start := 0; start < len(readings); {
+		end := start + 1
+		for end < len(readings) && readings[end].Date.Equal(readings[start].Date) {
+			end++
+		}
+
+		var bpm []int
+		for _, r := range readings[start:end] {
+			bpm = append(bpm, r.BPM)
+		}
+
+		daily = append(daily, DailyHeartRate{
+			Date:         readings[start].Date,
+			BPM:          vitalStatsOf(bpm),
+			ReadingCount: end - start,
+		})
+		start = end
+	}
+	return daily
+}
+
+// vitalStatsOf computes the stats of a non-empty set of values
+func vitalStatsOf(values []int) VitalStats {
+	stats := VitalStats{Min: values[0], Max: values[0]}
+	sum := 0
+	for _, v := range values {
+		stats.Min = min(stats.Min, v)
+		stats.Max = max(stats.Max, v)
+		sum += v
+	}
+	stats.Avg = math.Round(float64(sum)/float64(len(values))*10) / 10
+	return stats
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSummarizeBloodPressure(t *testing.T) {
+	day1 := time.Date(2024, 5, 1, 7, 0, 0, 0, time.UTC)
+	pulse := 70
+	var readings []BloodPressureReading
+	for _, bp := range []BloodPressureReading{
+		{MeasuredAt: day1, Systolic: 120, Diastolic: 80, Pulse: &pulse},
+		{MeasuredAt: day1.Add(12 * time.Hour), Systolic: 131, Diastolic: 85},
+		{MeasuredAt: day1.AddDate(0, 0, 1), Systolic: 118, Diastolic: 76},
+	} {
+		bp.SetDerivedFields()
+		readings = append(readings, bp)
+	}
+
+	daily := SummarizeBloodPressure(readings)
+	if len(daily) != 2 {
+		t.Fatalf("got %d days, want 2", len(daily))
+	}
+
+	want := VitalStats{Min: 120, Max: 131, Avg: 125.5}
+	if daily[0].Systolic != want {
+		t.Errorf("day 1 systolic = %+v, want %+v", daily[0].Systolic, want)
+	}
+	if daily[0].Pulse == nil || *daily[0].Pulse != (VitalStats{Min: 70, Max: 70, Avg: 70}) {
+		t.Errorf("day 1 pulse = %v, want stats of the one reading with a pulse", daily[0].Pulse)
+	}
+	if daily[1].Pulse != nil || daily[1].ReadingCount != 1 {
+		t.Errorf("day 2 = %+v, want one reading without pulse", daily[1])
+	}
+}
+
+func TestSummarizeHeartRate(t *testing.T) {
+	day := time.Date(2024, 5, 1, 7, 0, 0, 0, time.UTC)
+	var readings []HeartRateReading
+	for i, bpm := range []int{58, 61, 150} {
+		hr := HeartRateReading{MeasuredAt: day.Add(time.Duration(i) * time.Hour), BPM: bpm}
+		hr.SetDerivedFields()
+		readings = append(readings, hr)
+	}
+
+	daily := SummarizeHeartRate(readings)
+	if len(daily) != 1 {
+		t.Fatalf("got %d days, want 1", len(daily))
+	}
+
+	want := VitalStats{Min: 58, Max: 150, Avg: 89.7}
+	if daily[0].BPM != want || daily[0].ReadingCount != 3 {
+		t.Errorf("daily heart rate = %+v, want %+v over 3 readings", daily[0], want)
+	}
+}
@@ -0,0 +1,180 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+)
+
+// WeightAggregation defines how several readings on one day are combined into a daily value
+type WeightAggregation string
+
+const (
+	WeightAggregationLatest  WeightAggregation = "latest"
+	WeightAggregationAverage WeightAggregation = "average"
+)
+
+// ParseWeightAggregation parses an aggregation name, defaulting to latest when empty
+func ParseWeightAggregation(s string) (WeightAggregation, error) {
+	switch WeightAggregation(s) {
+	case "", WeightAggregationLatest:
+		return WeightAggregationLatest, nil
+	case WeightAggregationAverage:
+		return WeightAggregationAverage, nil
+	default:
+		return "", fmt.Errorf("unknown aggregation: %s (use latest or average)", s)
+	}
+}
+
+// WeightReading represents a single body weight measurement.
+// Body composition values are optional, and BMI is derived from the height profile on read.
+type WeightReading struct {
+	ID             int64     `json:"id"`
+	Date           time.Time `json:"date"`
+	MeasuredAt     time.Time `json:"measured_at"`
+	WeightKg       float64   `json:"weight_kg"`
+	BodyFatPercent *float64  `json:"body_fat_percent,omitempty"`
+	MuscleMassKg   *float64  `json:"muscle_mass_kg,omitempty"`
+	BMI            *float64  `json:"bmi,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// SetDerivedFields fills Date from MeasuredAt in the offset it was reported in
+func (wr *WeightReading) SetDerivedFields() {
+	wr.Date = dateOf(wr.MeasuredAt)
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// converts the reading's date to YYYY-MM-DD format JSON output.
+func (wr *WeightReading) MarshalJSON() ([]byte, error) {
+	type Alias WeightReading
+	return json.Marshal(&struct {
+		Date string `json:"date"`
+		*Alias
+	}{
+		Date:  wr.Date.Format("2006-01-02"),
+		Alias: (*Alias)(wr),
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// date and bmi are derived values, so they are ignored on input.
+func (wr *WeightReading) UnmarshalJSON(data []byte) error {
+	type Alias WeightReading
+	aux := &struct {
+		Date any `json:"date"`
+		BMI  any `json:"bmi"`
+		*Alias
+	}{
+		Alias: (*Alias)(wr),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return fmt.Errorf("failed to unmarshal weight reading: %w", err)
+	}
+	return nil
+}
+
+// HeightProfile holds the height used to derive BMI
+type HeightProfile struct {
+	HeightCm  float64   `json:"height_cm"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CalculateBMI returns the body mass index rounded to one decimal place
+func CalculateBMI(weightKg, heightCm float64) float64 {
+	heightM := heightCm / 100
+	return math.Round(weightKg/(heightM*heightM)*10) / 10
+}
+
+// DailyWeight is the daily value of one or more weight readings
+type DailyWeight struct {
+	Date           time.Time         `json:"date"`
+	WeightKg       float64           `json:"weight_kg"`
+	BodyFatPercent *float64          `json:"body_fat_percent,omitempty"`
+	MuscleMassKg   *float64          `json:"muscle_mass_kg,omitempty"`
+	BMI            *float64          `json:"bmi,omitempty"`
+	ReadingCount   int               `json:"reading_count"`
+	Aggregation    WeightAggregation `json:"aggregation"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// converts the daily value's date to YYYY-MM-DD format JSON output.
+func (dw *DailyWeight) MarshalJSON() ([]byte, error) {
+	type Alias DailyWeight
+	return json.Marshal(&struct {
+		Date string `json:"date"`
+		*Alias
+	}{
+		Date:  dw.Date.Format("2006-01-02"),
+		Alias: (*Alias)(dw),
+	})
+}
+
+// AggregateDailyWeights combines readings ordered by MeasuredAt into one value per date.
+// heightCm is used to derive BMI and may be zero when no height profile is stored.
+func AggregateDailyWeights(readings []WeightReading, mode WeightAggregation, heightCm float64) []DailyWeight {
+	var daily []DailyWeight
+	for start := 0; start < len(readings); {
+		end := start + 1
+		for end < len(readings) && readings[end].Date.Equal(readings[start].Date) {
+			end++
+		}
+		daily = append(daily, aggregateDay(readings[start:end], mode, heightCm))
+		start = end
+	}
+	return daily
+}
+
+// aggregateDay combines the readings of a single date
+func aggregateDay(readings []WeightReading, mode WeightAggregation, heightCm float64) DailyWeight {
+	dw := DailyWeight{
+		Date:         readings[0].Date,
+		ReadingCount: len(readings),
+		Aggregation:  mode,
+	}
+
+	if mode == WeightAggregationLatest {
+		latest := readings[len(readings)-1]
+		dw.WeightKg = latest.WeightKg
+		dw.BodyFatPercent = latest.BodyFatPercent
+		dw.MuscleMassKg = latest.MuscleMassKg
+	} else {
+		var weightSum, fatSum, muscleSum float64
+		var fatCount, muscleCount int
+		for _, r := range readings {
+			weightSum += r.WeightKg
+			if r.BodyFatPercent != nil {
+				fatSum += *r.BodyFatPercent
+				fatCount++
+			}
+			if r.MuscleMassKg != nil {
+				muscleSum += *r.MuscleMassKg
+				muscleCount++
+			}
+		}
+		dw.WeightKg = roundTo(weightSum/float64(len(readings)), 2)
+		if fatCount > 0 {
+			v := roundTo(fatSum/float64(fatCount), 2)
+			dw.BodyFatPercent = &v
+		}
+		if muscleCount > 0 {
+			v := roundTo(muscleSum/float64(muscleCount), 2)
+			dw.MuscleMassKg = &v
+		}
+	}
+
+	if heightCm > 0 {
+		bmi := CalculateBMI(dw.WeightKg, heightCm)
+		dw.BMI = &bmi
+	}
+
+	return dw
+}
+
+// roundTo rounds v to the given number of decimal places
+func roundTo(v float64, places int) float64 {
+	p := math.Pow(10, float64(places))
+	return math.Round(v*p) / p
+}
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func weightReading(measuredAt time.Time, weightKg float64, bodyFat *float64) WeightReading {
+	wr := WeightReading{MeasuredAt: measuredAt, WeightKg: weightKg, BodyFatPercent: bodyFat}
+	wr.SetDerivedFields()
+	return wr
+}
+
+func TestParseWeightAggregation(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    WeightAggregation
+		wantErr bool
+	}{
+		{input: "", want: WeightAggregationLatest},
+		{input: "latest", want: WeightAggregationLatest},
+		{input: "average", want: WeightAggregationAverage},
+		{input: "median", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseWeightAggregation(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseWeightAggregation(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseWeightAggregation(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestCalculateBMI(t *testing.T) {
+	if got := CalculateBMI(70, 175); got != 22.9 {
+		t.Errorf("CalculateBMI(70, 175) = %v, want 22.9", got)
+	}
+}
+
+func TestAggregateDailyWeights(t *testing.T) {
+	day1 := time.Date(2024, 5, 1, 7, 0, 0, 0, time.UTC)
+	day2 := time.Date(2024, 5, 2, 7, 0, 0, 0, time.UTC)
+	fat := 20.0
+	readings := []WeightReading{
+		weightReading(day1, 70.0, &fat),
+		weightReading(day1.Add(12*time.Hour), 71.0, nil),
+		weightReading(day2, 69.5, nil),
+	}
+
+	t.Run("latest", func(t *testing.T) {
+		daily := AggregateDailyWeights(readings, WeightAggregationLatest, 0)
+		if len(daily) != 2 {
+			t.Fatalf("got %d days, want 2", len(daily))
+		}
+		if daily[0].WeightKg != 71.0 || daily[0].ReadingCount != 2 || daily[0].BodyFatPercent != nil {
+			t.Errorf("day 1 = %+v, want latest reading 71.0 of 2 without body fat", daily[0])
+		}
+		if daily[0].BMI != nil {
+			t.Errorf("BMI should not be set without a height")
+		}
+	})
+
+	t.Run("average", func(t *testing.T) {
+		daily := AggregateDailyWeights(readings, WeightAggregationAverage, 175)
+		if daily[0].WeightKg != 70.5 {
+			t.Errorf("day 1 weight = %v, want 70.5", daily[0].WeightKg)
+		}
+		if daily[0].BodyFatPercent == nil || *daily[0].BodyFatPercent != 20.0 {
+			t.Errorf("day 1 body fat = %v, want 20.0 averaged over readings that have it", daily[0].BodyFatPercent)
+		}
+		if daily[1].BMI == nil || *daily[1].BMI != 22.7 {
+			t.Errorf("day 2 BMI = %v, want 22.7", daily[1].BMI)
+		}
+	})
+
+	t.Run("no readings", func(t *testing.T) {
+		if daily := AggregateDailyWeights(nil, WeightAggregationLatest, 0); len(daily) != 0 {
+			t.Errorf("got %d days, want 0", len(daily))
+		}
+	})
+}
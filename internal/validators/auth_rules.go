@@ -0,0 +1,57 @@
+package validators
+
+import (
+	"net/mail"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/nnamm/go-health-tracker/internal/apperr"
+	"github.com/nnamm/go-health-tracker/internal/models"
+)
+
+// Password length limits. bcrypt ignores input beyond 72 bytes.
+const (
+	minPasswordLength   = 8
+	maxPasswordLength   = 72
+	maxAPIKeyNameLength = 100
+)
+
+// ValidateCredentials validates the email and password of a registration or login request
+func ValidateCredentials(email, password string) error {
+	if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+		return apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "a valid email address is required")
+	}
+
+	if len(password) < minPasswordLength || len(password) > maxPasswordLength {
+		return apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "password must be between 8 and 72 characters")
+	}
+
+	return nil
+}
+
+// ValidateAPIKeyName validates the name of an API key
+func ValidateAPIKeyName(name string) error {
+	if strings.TrimSpace(name) == "" || utf8.RuneCountInString(name) > maxAPIKeyNameLength {
+		return apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "api key name must be between 1 and 100 characters")
+	}
+	return nil
+}
+
+// ValidateAPIKey validates the name and scope of a new API key
+func ValidateAPIKey(k *models.APIKey) error {
+	if k == nil {
+		return apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "api key is required")
+	}
+
+	if err := ValidateAPIKeyName(k.Name); err != nil {
+		return err
+	}
+
+	switch k.Scope {
+	case models.APIKeyScopeReadOnly, models.APIKeyScopeReadWrite:
+	default:
+		return apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "scope must be read-only or read-write")
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package validators
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nnamm/go-health-tracker/internal/apperr"
+	"github.com/nnamm/go-health-tracker/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCredentials(t *testing.T) {
+	tests := []struct {
+		name     string
+		email    string
+		password string
+		wantErr  bool
+		errorMsg string
+	}{
+		{name: "valid credentials", email: "alice@example.com", password: "correct horse"},
+		{name: "missing email", email: "", password: "correct horse", wantErr: true, errorMsg: "a valid email address is required"},
+		{name: "email without domain", email: "alice", password: "correct horse", wantErr: true, errorMsg: "a valid email address is required"},
+		{name: "email with display name", email: "Alice <alice@example.com>", password: "correct horse", wantErr: true, errorMsg: "a valid email address is required"},
+		{name: "password too short", email: "alice@example.com", password: "short", wantErr: true, errorMsg: "password must be between 8 and 72 characters"},
+		{name: "password too long", email: "alice@example.com", password: strings.Repeat("a", 73), wantErr: true, errorMsg: "password must be between 8 and 72 characters"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCredentials(tt.email, tt.password)
+			if tt.wantErr {
+				assert.Error(t, err)
+				if appErr, ok := err.(apperr.AppError); ok {
+					assert.Equal(t, apperr.ErrorTypeInvalidFormat, appErr.Type)
+					assert.Equal(t, tt.errorMsg, appErr.Message)
+				} else {
+					t.Errorf("expected apperr.AppError, got %T", err)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateAPIKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      *models.APIKey
+		wantErr  bool
+		errorMsg string
+	}{
+		{name: "valid read-only key", key: &models.APIKey{Name: "step counter", Scope: models.APIKeyScopeReadOnly}},
+		{name: "valid read-write key", key: &models.APIKey{Name: "nightly import", Scope: models.APIKeyScopeReadWrite}},
+		{name: "nil key", key: nil, wantErr: true, errorMsg: "api key is required"},
+		{name: "blank name", key: &models.APIKey{Name: "  ", Scope: models.APIKeyScopeReadOnly}, wantErr: true, errorMsg: "api key name must be between 1 and 100 characters"},
+		{name: "name too long", key: &models.APIKey{Name: strings.Repeat("a", 101), Scope: models.APIKeyScopeReadOnly}, wantErr: true, errorMsg: "api key name must be between 1 and 100 characters"},
+		{name: "missing scope", key: &models.APIKey{Name: "cron"}, wantErr: true, errorMsg: "scope must be read-only or read-write"},
+		{name: "unknown scope", key: &models.APIKey{Name: "cron", Scope: "admin"}, wantErr: true, errorMsg: "scope must be read-only or read-write"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAPIKey(tt.key)
+			if tt.wantErr {
+				assert.Error(t, err)
+				if appErr, ok := err.(apperr.AppError); ok {
+					assert.Equal(t, tt.errorMsg, appErr.Message)
+				} else {
+					t.Errorf("expected apperr.AppError, got %T", err)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package validators
+
+import (
+	"github.com/nnamm/go-health-tracker/internal/apperr"
+	"github.com/nnamm/go-health-tracker/internal/models"
+)
+
+// ValidateStepGoal checks a daily step goal.
+// The goal must be reachable within the step counts accepted for health records.
+func ValidateStepGoal(g *models.StepGoal) error {
+	if g == nil {
+		return apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "step goal is required")
+	}
+
+	if g.EffectiveFrom.IsZero() {
+		return apperr.NewAppError(apperr.ErrorTypeInvalidDate, "effective_from is required")
+	}
+
+	if g.StepCount < 1 || g.StepCount > 100000 {
+		return apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "step goal must be between 1 and 100000")
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package validators
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/apperr"
+	"github.com/nnamm/go-health-tracker/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateStepGoal(t *testing.T) {
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		goal     *models.StepGoal
+		wantErr  bool
+		errType  apperr.ErrorType
+		errorMsg string
+	}{
+		{name: "valid goal", goal: &models.StepGoal{EffectiveFrom: date, StepCount: 8000}},
+		{name: "future effective date", goal: &models.StepGoal{EffectiveFrom: time.Now().AddDate(0, 1, 0), StepCount: 8000}},
+		{name: "nil goal", goal: nil, wantErr: true, errType: apperr.ErrorTypeInvalidFormat, errorMsg: "step goal is required"},
+		{name: "missing effective date", goal: &models.StepGoal{StepCount: 8000}, wantErr: true, errType: apperr.ErrorTypeInvalidDate, errorMsg: "effective_from is required"},
+		{name: "zero steps", goal: &models.StepGoal{EffectiveFrom: date}, wantErr: true, errType: apperr.ErrorTypeInvalidFormat, errorMsg: "step goal must be between 1 and 100000"},
+		{name: "too many steps", goal: &models.StepGoal{EffectiveFrom: date, StepCount: 100001}, wantErr: true, errType: apperr.ErrorTypeInvalidFormat, errorMsg: "step goal must be between 1 and 100000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateStepGoal(tt.goal)
+			if tt.wantErr {
+				assert.Error(t, err)
+				if appErr, ok := err.(apperr.AppError); ok {
+					assert.Equal(t, tt.errType, appErr.Type)
+					assert.Equal(t, tt.errorMsg, appErr.Message)
+				} else {
+					t.Errorf("expected apperr.AppError, got %T", err)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
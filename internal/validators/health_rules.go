@@ -1,14 +1,20 @@
 package validators
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/nnamm/go-health-tracker/internal/apperr"
 	"github.com/nnamm/go-health-tracker/internal/models"
 )
 
+// MaxDateRangeDays is the maximum number of days a single range query may cover
+const MaxDateRangeDays = 366
+
 type HealthRecordValidator interface {
 	Validate(*models.HealthRecord) error
+	ValidateMetricDefinition(*models.MetricDefinition) error
+	ValidateMetricValue(*models.MetricDefinition, *models.MetricValue) error
 }
 
 type DefaultHealthRecordValidator struct{}
@@ -17,26 +23,45 @@ func NewHealthRecordValidator() HealthRecordValidator {
 	return &DefaultHealthRecordValidator{}
 }
 
+// Validate checks a health record and reports every violation, not just the first one
 func (v *DefaultHealthRecordValidator) Validate(hr *models.HealthRecord) error {
 	if hr == nil {
 		return apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "health record is required")
 	}
 
+	var errs violations
+
 	if hr.Date.IsZero() {
-		return apperr.NewAppError(apperr.ErrorTypeInvalidDate, "date is required")
+		errs.add(apperr.ErrorTypeInvalidDate, "date", "date is required")
+	} else if hr.Date.After(time.Now()) {
+		errs.add(apperr.ErrorTypeInvalidDate, "date", "future dates are not allowed")
 	}
 
 	if hr.StepCount < 0 {
-		return apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "step count must not be negative")
+		errs.add(apperr.ErrorTypeInvalidFormat, "step_count", "step count must not be negative")
 	}
 
 	if hr.StepCount > 100000 {
-		return apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "step count is unrealistically high")
+		errs.add(apperr.ErrorTypeInvalidFormat, "step_count", "step count is unrealistically high")
+	}
+
+	return errs.err()
+}
+
+// ValidateDateRange checks an inclusive date range used for range queries.
+// from must not be after to, and the range must not exceed MaxDateRangeDays.
+func ValidateDateRange(from, to time.Time) error {
+	if from.IsZero() || to.IsZero() {
+		return apperr.NewAppError(apperr.ErrorTypeInvalidRange, "both from and to are required")
+	}
+
+	if from.After(to) {
+		return apperr.NewAppError(apperr.ErrorTypeInvalidRange, "from must not be after to")
 	}
 
-	now := time.Now()
-	if hr.Date.After(now) {
-		return apperr.NewAppError(apperr.ErrorTypeInvalidDate, "future dates are not allowed")
+	days := int(to.Sub(from).Hours()/24) + 1
+	if days > MaxDateRangeDays {
+		return apperr.NewAppError(apperr.ErrorTypeInvalidRange, fmt.Sprintf("date range must not exceed %d days", MaxDateRangeDays))
 	}
 
 	return nil
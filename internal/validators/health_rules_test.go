@@ -121,3 +121,88 @@ func TestDefaultHealthRecordValidator_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestDefaultHealthRecordValidator_ValidateCollectsAllViolations(t *testing.T) {
+	v := NewHealthRecordValidator()
+
+	err := v.Validate(&models.HealthRecord{
+		Date:      time.Now().AddDate(0, 0, 1),
+		StepCount: -1,
+	})
+
+	appErr, ok := err.(apperr.AppError)
+	if !ok {
+		t.Fatalf("expected apperr.AppError, got %T", err)
+	}
+	assert.Equal(t, apperr.ErrorTypeInvalidDate, appErr.Type)
+	assert.Equal(t, "future dates are not allowed; step count must not be negative", appErr.Message)
+	assert.Equal(t, []apperr.FieldError{
+		{Field: "date", Code: "invalid_date", Message: "future dates are not allowed"},
+		{Field: "step_count", Code: "invalid_format", Message: "step count must not be negative"},
+	}, appErr.Fields)
+}
+
+func TestValidateDateRange(t *testing.T) {
+	from := time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		from     time.Time
+		to       time.Time
+		wantErr  bool
+		errorMsg string
+	}{
+		{
+			name: "valid range across year boundary",
+			from: from,
+			to:   from.AddDate(0, 0, 13),
+		},
+		{
+			name: "single day range",
+			from: from,
+			to:   from,
+		},
+		{
+			name: "boundary - maximum range",
+			from: from,
+			to:   from.AddDate(0, 0, MaxDateRangeDays-1),
+		},
+		{
+			name:     "missing to",
+			from:     from,
+			wantErr:  true,
+			errorMsg: "both from and to are required",
+		},
+		{
+			name:     "inverted range",
+			from:     from,
+			to:       from.AddDate(0, 0, -1),
+			wantErr:  true,
+			errorMsg: "from must not be after to",
+		},
+		{
+			name:     "oversized range",
+			from:     from,
+			to:       from.AddDate(0, 0, MaxDateRangeDays),
+			wantErr:  true,
+			errorMsg: "date range must not exceed 366 days",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateDateRange(tt.from, tt.to)
+			if tt.wantErr {
+				assert.Error(t, err)
+				if appErr, ok := err.(apperr.AppError); ok {
+					assert.Equal(t, apperr.ErrorTypeInvalidRange, appErr.Type)
+					assert.Equal(t, tt.errorMsg, appErr.Message)
+				} else {
+					t.Errorf("expected apperr.AppError, got %T", err)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
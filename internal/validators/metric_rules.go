@@ -0,0 +1,108 @@
+package validators
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/apperr"
+	"github.com/nnamm/go-health-tracker/internal/models"
+)
+
+// metricNamePattern restricts metric names to lowercase identifiers usable in query parameters
+var metricNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+func (v *DefaultHealthRecordValidator) ValidateMetricDefinition(def *models.MetricDefinition) error {
+	if def == nil {
+		return apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "metric definition is required")
+	}
+
+	if !metricNamePattern.MatchString(def.Name) {
+		return apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "metric name must be 1-50 lowercase letters, digits or underscores, starting with a letter")
+	}
+
+	switch def.ValueType {
+	case models.MetricValueTypeInt, models.MetricValueTypeFloat, models.MetricValueTypeDuration:
+		if len(def.EnumValues) > 0 {
+			return apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "enum_values are only allowed for enum metrics")
+		}
+		if def.Min != nil && def.Max != nil && *def.Min > *def.Max {
+			return apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "min must not be greater than max")
+		}
+	case models.MetricValueTypeEnum:
+		if len(def.EnumValues) == 0 {
+			return apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "enum metrics require enum_values")
+		}
+		if def.Min != nil || def.Max != nil {
+			return apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "min and max are only allowed for numeric metrics")
+		}
+		if def.Aggregation != models.MetricAggregationLast {
+			return apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "enum metrics only support the last aggregation")
+		}
+	default:
+		return apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "value_type must be int, float, duration or enum")
+	}
+
+	switch def.Aggregation {
+	case models.MetricAggregationSum, models.MetricAggregationAvg, models.MetricAggregationLast:
+	default:
+		return apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "aggregation must be sum, avg or last")
+	}
+
+	return nil
+}
+
+func (v *DefaultHealthRecordValidator) ValidateMetricValue(def *models.MetricDefinition, mv *models.MetricValue) error {
+	if def == nil || mv == nil {
+		return apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "metric value is required")
+	}
+
+	if mv.RecordedAt.IsZero() {
+		return apperr.NewAppError(apperr.ErrorTypeInvalidDate, "recorded_at is required")
+	}
+
+	if mv.RecordedAt.After(time.Now()) {
+		return apperr.NewAppError(apperr.ErrorTypeInvalidDate, "future values are not allowed")
+	}
+
+	if !def.IsNumeric() {
+		if mv.Value != nil {
+			return apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "enum metrics take enum_value, not value")
+		}
+		if !slices.Contains(def.EnumValues, mv.EnumValue) {
+			return apperr.NewAppError(apperr.ErrorTypeInvalidFormat, fmt.Sprintf("enum_value must be one of %v", def.EnumValues))
+		}
+		return nil
+	}
+
+	if mv.Value == nil {
+		return apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "value is required")
+	}
+	if mv.EnumValue != "" {
+		return apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "enum_value is only allowed for enum metrics")
+	}
+
+	value := *mv.Value
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "value must be a finite number")
+	}
+	if def.ValueType != models.MetricValueTypeFloat && value != math.Trunc(value) {
+		return apperr.NewAppError(apperr.ErrorTypeInvalidFormat, fmt.Sprintf("value must be a whole number for %s metrics", def.ValueType))
+	}
+	if def.Min != nil && value < *def.Min {
+		return apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "value must not be less than "+formatBound(*def.Min))
+	}
+	if def.Max != nil && value > *def.Max {
+		return apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "value must not be greater than "+formatBound(*def.Max))
+	}
+
+	return nil
+}
+
+// formatBound formats a range bound without trailing zeros
+func formatBound(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
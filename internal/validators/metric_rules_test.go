@@ -0,0 +1,160 @@
+package validators
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/apperr"
+	"github.com/nnamm/go-health-tracker/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultHealthRecordValidator_ValidateMetricDefinition(t *testing.T) {
+	v := NewHealthRecordValidator()
+
+	tests := []struct {
+		name     string
+		def      *models.MetricDefinition
+		wantErr  bool
+		errorMsg string
+	}{
+		{
+			name: "valid int metric with range",
+			def:  &models.MetricDefinition{Name: "water_glasses", Unit: "glass", ValueType: models.MetricValueTypeInt, Min: floatOf(0), Max: floatOf(30), Aggregation: models.MetricAggregationSum},
+		},
+		{
+			name: "valid enum metric",
+			def:  &models.MetricDefinition{Name: "mood", ValueType: models.MetricValueTypeEnum, EnumValues: []string{"low", "ok", "high"}, Aggregation: models.MetricAggregationLast},
+		},
+		{
+			name:     "invalid name",
+			def:      &models.MetricDefinition{Name: "Water Glasses", ValueType: models.MetricValueTypeInt, Aggregation: models.MetricAggregationSum},
+			wantErr:  true,
+			errorMsg: "metric name must be 1-50 lowercase letters, digits or underscores, starting with a letter",
+		},
+		{
+			name:     "unknown value type",
+			def:      &models.MetricDefinition{Name: "focus", ValueType: "percent", Aggregation: models.MetricAggregationAvg},
+			wantErr:  true,
+			errorMsg: "value_type must be int, float, duration or enum",
+		},
+		{
+			name:     "unknown aggregation",
+			def:      &models.MetricDefinition{Name: "focus", ValueType: models.MetricValueTypeFloat, Aggregation: "median"},
+			wantErr:  true,
+			errorMsg: "aggregation must be sum, avg or last",
+		},
+		{
+			name:     "min greater than max",
+			def:      &models.MetricDefinition{Name: "focus", ValueType: models.MetricValueTypeFloat, Min: floatOf(10), Max: floatOf(1), Aggregation: models.MetricAggregationAvg},
+			wantErr:  true,
+			errorMsg: "min must not be greater than max",
+		},
+		{
+			name:     "enum without values",
+			def:      &models.MetricDefinition{Name: "mood", ValueType: models.MetricValueTypeEnum, Aggregation: models.MetricAggregationLast},
+			wantErr:  true,
+			errorMsg: "enum metrics require enum_values",
+		},
+		{
+			name:     "enum with sum aggregation",
+			def:      &models.MetricDefinition{Name: "mood", ValueType: models.MetricValueTypeEnum, EnumValues: []string{"low"}, Aggregation: models.MetricAggregationSum},
+			wantErr:  true,
+			errorMsg: "enum metrics only support the last aggregation",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.ValidateMetricDefinition(tt.def)
+			if tt.wantErr {
+				assert.Error(t, err)
+				if appErr, ok := err.(apperr.AppError); ok {
+					assert.Equal(t, tt.errorMsg, appErr.Message)
+				} else {
+					t.Errorf("expected apperr.AppError, got %T", err)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDefaultHealthRecordValidator_ValidateMetricValue(t *testing.T) {
+	v := NewHealthRecordValidator()
+	recordedAt := time.Now().Add(-time.Hour)
+	glasses := &models.MetricDefinition{Name: "water_glasses", ValueType: models.MetricValueTypeInt, Min: floatOf(0), Max: floatOf(30), Aggregation: models.MetricAggregationSum}
+	meditation := &models.MetricDefinition{Name: "meditation", ValueType: models.MetricValueTypeDuration, Aggregation: models.MetricAggregationSum}
+	mood := &models.MetricDefinition{Name: "mood", ValueType: models.MetricValueTypeEnum, EnumValues: []string{"low", "ok", "high"}, Aggregation: models.MetricAggregationLast}
+
+	tests := []struct {
+		name     string
+		def      *models.MetricDefinition
+		value    *models.MetricValue
+		wantErr  bool
+		errorMsg string
+	}{
+		{name: "int value in range", def: glasses, value: &models.MetricValue{RecordedAt: recordedAt, Value: floatOf(8)}},
+		{name: "duration in seconds", def: meditation, value: &models.MetricValue{RecordedAt: recordedAt, Value: floatOf(900)}},
+		{name: "enum value", def: mood, value: &models.MetricValue{RecordedAt: recordedAt, EnumValue: "ok"}},
+		{
+			name:     "missing recorded_at",
+			def:      glasses,
+			value:    &models.MetricValue{Value: floatOf(8)},
+			wantErr:  true,
+			errorMsg: "recorded_at is required",
+		},
+		{
+			name:     "missing numeric value",
+			def:      glasses,
+			value:    &models.MetricValue{RecordedAt: recordedAt},
+			wantErr:  true,
+			errorMsg: "value is required",
+		},
+		{
+			name:     "fractional int value",
+			def:      glasses,
+			value:    &models.MetricValue{RecordedAt: recordedAt, Value: floatOf(2.5)},
+			wantErr:  true,
+			errorMsg: "value must be a whole number for int metrics",
+		},
+		{
+			name:     "value above max",
+			def:      glasses,
+			value:    &models.MetricValue{RecordedAt: recordedAt, Value: floatOf(31)},
+			wantErr:  true,
+			errorMsg: "value must not be greater than 30",
+		},
+		{
+			name:     "value below min",
+			def:      glasses,
+			value:    &models.MetricValue{RecordedAt: recordedAt, Value: floatOf(-1)},
+			wantErr:  true,
+			errorMsg: "value must not be less than 0",
+		},
+		{
+			name:     "unknown enum value",
+			def:      mood,
+			value:    &models.MetricValue{RecordedAt: recordedAt, EnumValue: "great"},
+			wantErr:  true,
+			errorMsg: "enum_value must be one of [low ok high]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.ValidateMetricValue(tt.def, tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				if appErr, ok := err.(apperr.AppError); ok {
+					assert.Equal(t, tt.errorMsg, appErr.Message)
+				} else {
+					t.Errorf("expected apperr.AppError, got %T", err)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package validators
+
+import (
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/apperr"
+	"github.com/nnamm/go-health-tracker/internal/models"
+)
+
+// maxSleepDuration is the longest session accepted as a single sleep
+const maxSleepDuration = 24 * time.Hour
+
+type SleepSessionValidator interface {
+	Validate(*models.SleepSession) error
+}
+
+type DefaultSleepSessionValidator struct{}
+
+func NewSleepSessionValidator() SleepSessionValidator {
+	return &DefaultSleepSessionValidator{}
+}
+
+func (v *DefaultSleepSessionValidator) Validate(s *models.SleepSession) error {
+	if s == nil {
+		return apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "sleep session is required")
+	}
+
+	if s.BedTime.IsZero() {
+		return apperr.NewAppError(apperr.ErrorTypeInvalidDate, "bed time is required")
+	}
+
+	if s.WakeTime.IsZero() {
+		return apperr.NewAppError(apperr.ErrorTypeInvalidDate, "wake time is required")
+	}
+
+	if !s.WakeTime.After(s.BedTime) {
+		return apperr.NewAppError(apperr.ErrorTypeInvalidDate, "wake time must be after bed time")
+	}
+
+	if s.WakeTime.Sub(s.BedTime) > maxSleepDuration {
+		return apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "sleep session must not exceed 24 hours")
+	}
+
+	if s.WakeTime.After(time.Now()) {
+		return apperr.NewAppError(apperr.ErrorTypeInvalidDate, "future wake times are not allowed")
+	}
+
+	if s.Quality != nil && (*s.Quality < 0 || *s.Quality > 100) {
+		return apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "sleep quality must be between 0 and 100")
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package validators
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/apperr"
+	"github.com/nnamm/go-health-tracker/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func qualityOf(q int) *int {
+	return &q
+}
+
+func TestDefaultSleepSessionValidator_Validate(t *testing.T) {
+	v := NewSleepSessionValidator()
+	wake := time.Now().Add(-time.Hour).Truncate(time.Minute)
+	bed := wake.Add(-7 * time.Hour)
+
+	tests := []struct {
+		name      string
+		session   *models.SleepSession
+		wantErr   bool
+		errorType apperr.ErrorType
+		errorMsg  string
+	}{
+		{
+			name:    "valid session",
+			session: &models.SleepSession{BedTime: bed, WakeTime: wake, Quality: qualityOf(80)},
+		},
+		{
+			name:    "valid nap without quality",
+			session: &models.SleepSession{BedTime: wake.Add(-20 * time.Minute), WakeTime: wake, IsNap: true},
+		},
+		{
+			name:      "nil session",
+			session:   nil,
+			wantErr:   true,
+			errorType: apperr.ErrorTypeInvalidFormat,
+			errorMsg:  "sleep session is required",
+		},
+		{
+			name:      "missing bed time",
+			session:   &models.SleepSession{WakeTime: wake},
+			wantErr:   true,
+			errorType: apperr.ErrorTypeInvalidDate,
+			errorMsg:  "bed time is required",
+		},
+		{
+			name:      "wake time before bed time",
+			session:   &models.SleepSession{BedTime: wake, WakeTime: bed},
+			wantErr:   true,
+			errorType: apperr.ErrorTypeInvalidDate,
+			errorMsg:  "wake time must be after bed time",
+		},
+		{
+			name:      "session longer than 24 hours",
+			session:   &models.SleepSession{BedTime: wake.Add(-25 * time.Hour), WakeTime: wake},
+			wantErr:   true,
+			errorType: apperr.ErrorTypeInvalidFormat,
+			errorMsg:  "sleep session must not exceed 24 hours",
+		},
+		{
+			name:      "future wake time",
+			session:   &models.SleepSession{BedTime: bed, WakeTime: time.Now().Add(time.Hour)},
+			wantErr:   true,
+			errorType: apperr.ErrorTypeInvalidDate,
+			errorMsg:  "future wake times are not allowed",
+		},
+		{
+			name:      "quality out of range",
+			session:   &models.SleepSession{BedTime: bed, WakeTime: wake, Quality: qualityOf(101)},
+			wantErr:   true,
+			errorType: apperr.ErrorTypeInvalidFormat,
+			errorMsg:  "sleep quality must be between 0 and 100",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.Validate(tt.session)
+			if tt.wantErr {
+				assert.Error(t, err)
+				if appErr, ok := err.(apperr.AppError); ok {
+					assert.Equal(t, tt.errorType, appErr.Type)
+					assert.Equal(t, tt.errorMsg, appErr.Message)
+				} else {
+					t.Errorf("expected apperr.AppError, got %T", err)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
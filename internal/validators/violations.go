@@ -0,0 +1,29 @@
+package validators
+
+import "github.com/nnamm/go-health-tracker/internal/apperr"
+
+// violations collects the field-level problems found while validating a value
+type violations struct {
+	errorType apperr.ErrorType
+	fields    []apperr.FieldError
+}
+
+// add records a problem of a field. The first problem decides the type of the resulting error.
+func (v *violations) add(errorType apperr.ErrorType, field, message string) {
+	if len(v.fields) == 0 {
+		v.errorType = errorType
+	}
+	v.fields = append(v.fields, apperr.FieldError{
+		Field:   field,
+		Code:    errorType.Code(),
+		Message: message,
+	})
+}
+
+// err returns a validation error listing every recorded problem, or nil if there were none
+func (v *violations) err() error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return apperr.NewValidationError(v.errorType, v.fields)
+}
@@ -0,0 +1,106 @@
+package validators
+
+import (
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/apperr"
+	"github.com/nnamm/go-health-tracker/internal/models"
+)
+
+// Plausible ranges for vital sign readings
+const (
+	minSystolic  = 50
+	maxSystolic  = 300
+	minDiastolic = 30
+	maxDiastolic = 200
+	minBPM       = 20
+	maxBPM       = 250
+)
+
+type VitalValidator interface {
+	ValidateBloodPressure(*models.BloodPressureReading) error
+	ValidateHeartRate(*models.HeartRateReading) error
+}
+
+type DefaultVitalValidator struct{}
+
+func NewVitalValidator() VitalValidator {
+	return &DefaultVitalValidator{}
+}
+
+func (v *DefaultVitalValidator) ValidateBloodPressure(bp *models.BloodPressureReading) error {
+	if bp == nil {
+		return apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "blood pressure reading is required")
+	}
+
+	if err := validateMeasuredAt(bp.MeasuredAt); err != nil {
+		return err
+	}
+
+	if bp.Systolic < minSystolic || bp.Systolic > maxSystolic {
+		return apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "systolic must be between 50 and 300 mmHg")
+	}
+
+	if bp.Diastolic < minDiastolic || bp.Diastolic > maxDiastolic {
+		return apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "diastolic must be between 30 and 200 mmHg")
+	}
+
+	if bp.Diastolic >= bp.Systolic {
+		return apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "diastolic must be lower than systolic")
+	}
+
+	if bp.Pulse != nil && (*bp.Pulse < minBPM || *bp.Pulse > maxBPM) {
+		return apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "pulse must be between 20 and 250 bpm")
+	}
+
+	if err := validateVitalContext(bp.Context); err != nil {
+		return err
+	}
+
+	switch bp.Arm {
+	case "", models.MeasurementArmLeft, models.MeasurementArmRight:
+	default:
+		return apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "arm must be left or right")
+	}
+
+	return nil
+}
+
+func (v *DefaultVitalValidator) ValidateHeartRate(hr *models.HeartRateReading) error {
+	if hr == nil {
+		return apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "heart rate reading is required")
+	}
+
+	if err := validateMeasuredAt(hr.MeasuredAt); err != nil {
+		return err
+	}
+
+	if hr.BPM < minBPM || hr.BPM > maxBPM {
+		return apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "bpm must be between 20 and 250")
+	}
+
+	return validateVitalContext(hr.Context)
+}
+
+// validateMeasuredAt checks a reading's timestamp is set and not in the future
+func validateMeasuredAt(measuredAt time.Time) error {
+	if measuredAt.IsZero() {
+		return apperr.NewAppError(apperr.ErrorTypeInvalidDate, "measured_at is required")
+	}
+
+	if measuredAt.After(time.Now()) {
+		return apperr.NewAppError(apperr.ErrorTypeInvalidDate, "future measurements are not allowed")
+	}
+
+	return nil
+}
+
+// validateVitalContext checks the optional reading context is a known value
+func validateVitalContext(c models.VitalContext) error {
+	switch c {
+	case "", models.VitalContextResting, models.VitalContextPostExercise, models.VitalContextOther:
+		return nil
+	default:
+		return apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "context must be resting, post_exercise or other")
+	}
+}
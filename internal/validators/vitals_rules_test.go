@@ -0,0 +1,122 @@
+package validators
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/apperr"
+	"github.com/nnamm/go-health-tracker/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func pulseOf(p int) *int {
+	return &p
+}
+
+func TestDefaultVitalValidator_ValidateBloodPressure(t *testing.T) {
+	v := NewVitalValidator()
+	measuredAt := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name     string
+		reading  *models.BloodPressureReading
+		wantErr  bool
+		errorMsg string
+	}{
+		{
+			name:    "valid reading with context",
+			reading: &models.BloodPressureReading{MeasuredAt: measuredAt, Systolic: 120, Diastolic: 80, Pulse: pulseOf(65), Context: models.VitalContextResting, Arm: models.MeasurementArmLeft},
+		},
+		{
+			name:    "valid reading without optional fields",
+			reading: &models.BloodPressureReading{MeasuredAt: measuredAt, Systolic: 135, Diastolic: 88},
+		},
+		{
+			name:     "nil reading",
+			wantErr:  true,
+			errorMsg: "blood pressure reading is required",
+		},
+		{
+			name:     "missing measured_at",
+			reading:  &models.BloodPressureReading{Systolic: 120, Diastolic: 80},
+			wantErr:  true,
+			errorMsg: "measured_at is required",
+		},
+		{
+			name:     "systolic out of range",
+			reading:  &models.BloodPressureReading{MeasuredAt: measuredAt, Systolic: 310, Diastolic: 80},
+			wantErr:  true,
+			errorMsg: "systolic must be between 50 and 300 mmHg",
+		},
+		{
+			name:     "diastolic not lower than systolic",
+			reading:  &models.BloodPressureReading{MeasuredAt: measuredAt, Systolic: 90, Diastolic: 95},
+			wantErr:  true,
+			errorMsg: "diastolic must be lower than systolic",
+		},
+		{
+			name:     "pulse out of range",
+			reading:  &models.BloodPressureReading{MeasuredAt: measuredAt, Systolic: 120, Diastolic: 80, Pulse: pulseOf(10)},
+			wantErr:  true,
+			errorMsg: "pulse must be between 20 and 250 bpm",
+		},
+		{
+			name:     "unknown context",
+			reading:  &models.BloodPressureReading{MeasuredAt: measuredAt, Systolic: 120, Diastolic: 80, Context: "sleeping"},
+			wantErr:  true,
+			errorMsg: "context must be resting, post_exercise or other",
+		},
+		{
+			name:     "unknown arm",
+			reading:  &models.BloodPressureReading{MeasuredAt: measuredAt, Systolic: 120, Diastolic: 80, Arm: "leg"},
+			wantErr:  true,
+			errorMsg: "arm must be left or right",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.ValidateBloodPressure(tt.reading)
+			if tt.wantErr {
+				assert.Error(t, err)
+				if appErr, ok := err.(apperr.AppError); ok {
+					assert.Equal(t, tt.errorMsg, appErr.Message)
+				} else {
+					t.Errorf("expected apperr.AppError, got %T", err)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDefaultVitalValidator_ValidateHeartRate(t *testing.T) {
+	v := NewVitalValidator()
+	measuredAt := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name    string
+		reading *models.HeartRateReading
+		wantErr bool
+	}{
+		{name: "valid resting reading", reading: &models.HeartRateReading{MeasuredAt: measuredAt, BPM: 58, Context: models.VitalContextResting}},
+		{name: "valid post-exercise reading", reading: &models.HeartRateReading{MeasuredAt: measuredAt, BPM: 165, Context: models.VitalContextPostExercise}},
+		{name: "nil reading", wantErr: true},
+		{name: "future measurement", reading: &models.HeartRateReading{MeasuredAt: time.Now().Add(time.Hour), BPM: 60}, wantErr: true},
+		{name: "bpm too low", reading: &models.HeartRateReading{MeasuredAt: measuredAt, BPM: 19}, wantErr: true},
+		{name: "bpm too high", reading: &models.HeartRateReading{MeasuredAt: measuredAt, BPM: 251}, wantErr: true},
+		{name: "unknown context", reading: &models.HeartRateReading{MeasuredAt: measuredAt, BPM: 60, Context: "running"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.ValidateHeartRate(tt.reading)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package validators
+
+import (
+	"github.com/nnamm/go-health-tracker/internal/apperr"
+	"github.com/nnamm/go-health-tracker/internal/models"
+)
+
+// Plausible ranges for body measurements of an adult
+const (
+	minWeightKg       = 20.0
+	maxWeightKg       = 400.0
+	minBodyFatPercent = 2.0
+	maxBodyFatPercent = 75.0
+	minHeightCm       = 50.0
+	maxHeightCm       = 250.0
+)
+
+type WeightValidator interface {
+	Validate(*models.WeightReading) error
+	ValidateHeightProfile(*models.HeightProfile) error
+}
+
+type DefaultWeightValidator struct{}
+
+func NewWeightValidator() WeightValidator {
+	return &DefaultWeightValidator{}
+}
+
+func (v *DefaultWeightValidator) Validate(wr *models.WeightReading) error {
+	if wr == nil {
+		return apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "weight reading is required")
+	}
+
+	if err := validateMeasuredAt(wr.MeasuredAt); err != nil {
+		return err
+	}
+
+	if wr.WeightKg < minWeightKg || wr.WeightKg > maxWeightKg {
+		return apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "weight must be between 20 and 400 kg")
+	}
+
+	if wr.BodyFatPercent != nil && (*wr.BodyFatPercent < minBodyFatPercent || *wr.BodyFatPercent > maxBodyFatPercent) {
+		return apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "body fat must be between 2 and 75 percent")
+	}
+
+	if wr.MuscleMassKg != nil && (*wr.MuscleMassKg <= 0 || *wr.MuscleMassKg >= wr.WeightKg) {
+		return apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "muscle mass must be positive and less than weight")
+	}
+
+	return nil
+}
+
+func (v *DefaultWeightValidator) ValidateHeightProfile(hp *models.HeightProfile) error {
+	if hp == nil {
+		return apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "height profile is required")
+	}
+
+	if hp.HeightCm < minHeightCm || hp.HeightCm > maxHeightCm {
+		return apperr.NewAppError(apperr.ErrorTypeInvalidFormat, "height must be between 50 and 250 cm")
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package validators
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nnamm/go-health-tracker/internal/apperr"
+	"github.com/nnamm/go-health-tracker/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func floatOf(f float64) *float64 {
+	return &f
+}
+
+func TestDefaultWeightValidator_Validate(t *testing.T) {
+	v := NewWeightValidator()
+	measuredAt := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name      string
+		reading   *models.WeightReading
+		wantErr   bool
+		errorType apperr.ErrorType
+		errorMsg  string
+	}{
+		{
+			name:    "valid reading with body composition",
+			reading: &models.WeightReading{MeasuredAt: measuredAt, WeightKg: 70.5, BodyFatPercent: floatOf(18.2), MuscleMassKg: floatOf(54.1)},
+		},
+		{
+			name:    "valid weight only",
+			reading: &models.WeightReading{MeasuredAt: measuredAt, WeightKg: 70.5},
+		},
+		{
+			name:      "nil reading",
+			reading:   nil,
+			wantErr:   true,
+			errorType: apperr.ErrorTypeInvalidFormat,
+			errorMsg:  "weight reading is required",
+		},
+		{
+			name:      "missing measured_at",
+			reading:   &models.WeightReading{WeightKg: 70.5},
+			wantErr:   true,
+			errorType: apperr.ErrorTypeInvalidDate,
+			errorMsg:  "measured_at is required",
+		},
+		{
+			name:      "future measurement",
+			reading:   &models.WeightReading{MeasuredAt: time.Now().Add(time.Hour), WeightKg: 70.5},
+			wantErr:   true,
+			errorType: apperr.ErrorTypeInvalidDate,
+			errorMsg:  "future measurements are not allowed",
+		},
+		{
+			name:      "weight too low",
+			reading:   &models.WeightReading{MeasuredAt: measuredAt, WeightKg: 19.9},
+			wantErr:   true,
+			errorType: apperr.ErrorTypeInvalidFormat,
+			errorMsg:  "weight must be between 20 and 400 kg",
+		},
+		{
+			name:      "weight too high",
+			reading:   &models.WeightReading{MeasuredAt: measuredAt, WeightKg: 400.1},
+			wantErr:   true,
+			errorType: apperr.ErrorTypeInvalidFormat,
+			errorMsg:  "weight must be between 20 and 400 kg",
+		},
+		{
+			name:      "body fat out of range",
+			reading:   &models.WeightReading{MeasuredAt: measuredAt, WeightKg: 70.5, BodyFatPercent: floatOf(80)},
+			wantErr:   true,
+			errorType: apperr.ErrorTypeInvalidFormat,
+			errorMsg:  "body fat must be between 2 and 75 percent",
+		},
+		{
+			name:      "muscle mass not less than weight",
+			reading:   &models.WeightReading{MeasuredAt: measuredAt, WeightKg: 70.5, MuscleMassKg: floatOf(70.5)},
+			wantErr:   true,
+			errorType: apperr.ErrorTypeInvalidFormat,
+			errorMsg:  "muscle mass must be positive and less than weight",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.Validate(tt.reading)
+			if tt.wantErr {
+				assert.Error(t, err)
+				if appErr, ok := err.(apperr.AppError); ok {
+					assert.Equal(t, tt.errorType, appErr.Type)
+					assert.Equal(t, tt.errorMsg, appErr.Message)
+				} else {
+					t.Errorf("expected apperr.AppError, got %T", err)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDefaultWeightValidator_ValidateHeightProfile(t *testing.T) {
+	v := NewWeightValidator()
+
+	tests := []struct {
+		name    string
+		profile *models.HeightProfile
+		wantErr bool
+	}{
+		{name: "valid height", profile: &models.HeightProfile{HeightCm: 172.5}},
+		{name: "nil profile", profile: nil, wantErr: true},
+		{name: "height too low", profile: &models.HeightProfile{HeightCm: 49}, wantErr: true},
+		{name: "height too high", profile: &models.HeightProfile{HeightCm: 251}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.ValidateHeightProfile(tt.profile)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
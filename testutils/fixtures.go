@@ -54,7 +54,7 @@ func SetupTestData(ctx context.Context, t *testing.T, ptc *PostgresTestContainer
 	t.Helper()
 
 	for _, record := range records {
-		_, err := ptc.DB.CreateHealthRecord(ctx, record)
+		_, err := ptc.DB.CreateHealthRecord(ctx, TestUserID, record)
 		if err != nil {
 			t.Fatalf("failed to setup test data: %v", err)
 		}
@@ -34,6 +34,8 @@ func SetupPostgresContainer(ctx context.Context, t *testing.T) *PostgresTestCont
 	db, err := database.NewPostgresDB(connStr)
 	require.NoError(t, err, "failed to connect to test database")
 
+	CreateTestUser(ctx, t, db)
+
 	return &PostgresTestContainer{
 		Container: container,
 		DB:        db,
@@ -15,13 +15,11 @@ import (
 func SetupSQLiteTester(t *testing.T) (*database.SQLiteDB, func()) {
 	t.Helper()
 
-	// Set up a database for testing
+	// Set up a database for testing. Opening it applies the schema migrations.
 	db, err := database.NewSQLiteDB(":memory:")
 	require.NoError(t, err)
 
-	// Create table. Assumes CreateTable() is now a public method.
-	err = db.CreateTable()
-	require.NoError(t, err)
+	CreateTestUser(context.Background(), t, db)
 
 	cleanup := func() {
 		db.Close()
@@ -63,10 +61,10 @@ func AssertHealthRecordsEqual(t *testing.T, got, want []models.HealthRecord) {
 	}
 }
 
-// CreateTestRecords creates records in the test table
+// CreateTestRecords creates records of the test user in the test table
 func CreateTestRecords(ctx context.Context, t *testing.T, db *sql.DB, records []models.HealthRecord) {
 	t.Helper()
-	stmt, err := db.PrepareContext(ctx, "INSERT INTO health_records (date, step_count, created_at, updated_at) VALUES (?, ?, ?, ?)")
+	stmt, err := db.PrepareContext(ctx, "INSERT INTO health_records (user_id, date, step_count, created_at, updated_at) VALUES (?, ?, ?, ?, ?)")
 	if err != nil {
 		t.Fatalf("statement preparation error: %v", err)
 	}
@@ -74,7 +72,7 @@ func CreateTestRecords(ctx context.Context, t *testing.T, db *sql.DB, records []
 
 	for _, r := range records {
 		now := time.Now()
-		_, err := stmt.ExecContext(ctx, r.Date, r.StepCount, now, now)
+		_, err := stmt.ExecContext(ctx, TestUserID, r.Date, r.StepCount, now, now)
 		if err != nil {
 			t.Fatalf("failed to create records: %v", err)
 		}
@@ -0,0 +1,26 @@
+package testutils
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nnamm/go-health-tracker/internal/database"
+	"github.com/nnamm/go-health-tracker/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUserID is the ID of the user created on a fresh test database,
+// which owns all records created by the test helpers
+const TestUserID int64 = 1
+
+// TestUserEmail is the email address of the test user
+const TestUserEmail = "test@example.com"
+
+// CreateTestUser creates the test user and checks it received TestUserID
+func CreateTestUser(ctx context.Context, t *testing.T, db database.UserStore) {
+	t.Helper()
+
+	user, err := db.CreateUser(ctx, &models.User{Email: TestUserEmail})
+	require.NoError(t, err, "failed to create test user")
+	require.Equal(t, TestUserID, user.ID, "test user must be the first user")
+}